@@ -87,6 +87,24 @@ type executionPayloadEnvelopeMarshaling struct {
 	BlockValue *hexutil.Big
 }
 
+// BorPayloadAttributes describes the environment context in which a Bor block
+// should be built. The timestamp, difficulty and extra data aren't part of it
+// as Bor derives them from the position of the local signer in the validator
+// set.
+type BorPayloadAttributes struct {
+	SuggestedFeeRecipient common.Address `json:"suggestedFeeRecipient"`
+}
+
+// BorExecutionPayloadEnvelope is a Bor block built through the engine API,
+// waiting for its seal. The full header is returned since Bor blocks carry
+// consensus data the post-merge payload has no room for: the difficulty of the
+// signer, and the validator set and transaction dependencies in the extra data.
+type BorExecutionPayloadEnvelope struct {
+	Header       *types.Header   `json:"header"`
+	Transactions []hexutil.Bytes `json:"transactions"`
+	BlockValue   *hexutil.Big    `json:"blockValue"`
+}
+
 type PayloadStatusV1 struct {
 	Status          string       `json:"status"`
 	LatestValidHash *common.Hash `json:"latestValidHash"`
@@ -242,6 +260,21 @@ func BlockToExecutableData(block *types.Block, fees *big.Int) *ExecutionPayloadE
 	return &ExecutionPayloadEnvelope{ExecutionPayload: data, BlockValue: fees}
 }
 
+// BlockToBorExecutionPayload constructs the BorExecutionPayloadEnvelope of the
+// given unsealed Bor block.
+func BlockToBorExecutionPayload(block *types.Block, fees *big.Int) *BorExecutionPayloadEnvelope {
+	txs := make([]hexutil.Bytes, 0, len(block.Transactions()))
+	for _, tx := range encodeTransactions(block.Transactions()) {
+		txs = append(txs, tx)
+	}
+
+	return &BorExecutionPayloadEnvelope{
+		Header:       block.Header(),
+		Transactions: txs,
+		BlockValue:   (*hexutil.Big)(fees),
+	}
+}
+
 // ExecutionPayloadBodyV1 is used in the response to GetPayloadBodiesByHashV1 and GetPayloadBodiesByRangeV1
 type ExecutionPayloadBodyV1 struct {
 	TransactionData []hexutil.Bytes     `json:"transactions"`
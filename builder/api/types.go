@@ -0,0 +1,209 @@
+// Package api contains the wire types exchanged between bor validators, block
+// builders and relays when block construction is outsourced (proposer-builder
+// separation).
+package api
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// ErrInvalidSignature is returned if a bid is not signed by the builder key
+	// it claims to come from.
+	ErrInvalidSignature = errors.New("invalid bid signature")
+
+	// ErrPayloadMismatch is returned if the transactions revealed for a bid do
+	// not match the transaction root the builder committed to.
+	ErrPayloadMismatch = errors.New("payload does not match bid")
+)
+
+// BidRequest describes the slot a proposer is collecting bids for.
+type BidRequest struct {
+	ParentHash common.Hash    `json:"parentHash"`
+	Number     hexutil.Uint64 `json:"number"`
+	Timestamp  hexutil.Uint64 `json:"timestamp"`
+	GasLimit   hexutil.Uint64 `json:"gasLimit"`
+	BaseFee    *hexutil.Big   `json:"baseFee"`
+	Span       hexutil.Uint64 `json:"span"`
+	Sprint     hexutil.Uint64 `json:"sprint"`
+	Proposer   common.Address `json:"proposer"`
+}
+
+// Bid is a builder's offer to fill the block requested by a BidRequest. The
+// builder commits to the transaction list through TxHash and only reveals it
+// once the proposer asks for the payload of the winning bid.
+type Bid struct {
+	ParentHash common.Hash    `json:"parentHash"`
+	Number     hexutil.Uint64 `json:"number"`
+	Proposer   common.Address `json:"proposer"`
+	TxHash     common.Hash    `json:"txHash"`
+	Value      *hexutil.Big   `json:"value"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	TxCount    hexutil.Uint64 `json:"txCount"`
+	Builder    hexutil.Bytes  `json:"builder"` // Uncompressed secp256k1 public key of the builder
+}
+
+// Hash returns the digest signed by the builder.
+func (b *Bid) Hash() common.Hash {
+	enc, _ := rlp.EncodeToBytes([]interface{}{
+		b.ParentHash,
+		uint64(b.Number),
+		b.Proposer,
+		b.TxHash,
+		(*big.Int)(b.Value),
+		uint64(b.GasUsed),
+		uint64(b.TxCount),
+		[]byte(b.Builder),
+	})
+
+	return crypto.Keccak256Hash(enc)
+}
+
+// BuilderAddress returns the address derived from the builder public key.
+func (b *Bid) BuilderAddress() (common.Address, error) {
+	pub, err := crypto.UnmarshalPubkey(b.Builder)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SignedBid is a bid together with the builder's signature over its hash.
+type SignedBid struct {
+	Message   *Bid          `json:"message"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// SignBid fills in the builder public key of the bid and signs it with the
+// given key.
+func SignBid(bid *Bid, key *ecdsa.PrivateKey) (*SignedBid, error) {
+	bid.Builder = crypto.FromECDSAPub(&key.PublicKey)
+
+	sig, err := crypto.Sign(bid.Hash().Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedBid{Message: bid, Signature: sig}, nil
+}
+
+// Verify checks that the bid is well formed and signed by its builder key.
+func (s *SignedBid) Verify() error {
+	if s.Message == nil || s.Message.Value == nil {
+		return errors.New("incomplete bid")
+	}
+
+	if len(s.Signature) != crypto.SignatureLength {
+		return ErrInvalidSignature
+	}
+
+	pub, err := crypto.SigToPub(s.Message.Hash().Bytes(), s.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	if string(crypto.FromECDSAPub(pub)) != string(s.Message.Builder) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Payload reveals the transactions of a winning bid.
+type Payload struct {
+	BidHash      common.Hash     `json:"bidHash"`
+	Transactions []hexutil.Bytes `json:"transactions"`
+}
+
+// NewPayload encodes the given transactions as the payload of a bid.
+func NewPayload(bidHash common.Hash, txs types.Transactions) (*Payload, error) {
+	payload := &Payload{
+		BidHash:      bidHash,
+		Transactions: make([]hexutil.Bytes, 0, len(txs)),
+	}
+
+	for _, tx := range txs {
+		enc, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		payload.Transactions = append(payload.Transactions, enc)
+	}
+
+	return payload, nil
+}
+
+// Decode decodes the payload transactions and checks them against the
+// transaction root committed to by the bid.
+func (p *Payload) Decode(bid *Bid) (types.Transactions, error) {
+	txs := make(types.Transactions, 0, len(p.Transactions))
+
+	for i, enc := range p.Transactions {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(enc); err != nil {
+			return nil, fmt.Errorf("invalid transaction %d: %v", i, err)
+		}
+
+		txs = append(txs, tx)
+	}
+
+	if TxHash(txs) != bid.TxHash {
+		return nil, ErrPayloadMismatch
+	}
+
+	return txs, nil
+}
+
+// TxHash computes the transaction root a builder commits to in its bid.
+func TxHash(txs types.Transactions) common.Hash {
+	return types.DeriveSha(txs, trie.NewStackTrie(nil))
+}
+
+// BlockSubmission is a block offered by a builder to a relay: the signed bid
+// proposers will see, the payload revealed to the winning one and the header
+// of the block as executed and sealed by the builder, which lets the relay
+// validate the payload before offering it. The fee recipient is the account
+// the fees were credited to when executing the block, the proposer unless the
+// builder pays it through a final transaction.
+type BlockSubmission struct {
+	Bid          *SignedBid     `json:"bid"`
+	Payload      *Payload       `json:"payload"`
+	Header       *types.Header  `json:"header"`
+	FeeRecipient common.Address `json:"feeRecipient"`
+}
+
+// Verify checks the bid signature and that the payload and header belong to
+// the bid.
+func (s *BlockSubmission) Verify() (types.Transactions, error) {
+	if s.Bid == nil || s.Payload == nil || s.Header == nil {
+		return nil, errors.New("incomplete submission")
+	}
+
+	if err := s.Bid.Verify(); err != nil {
+		return nil, err
+	}
+
+	if s.Payload.BidHash != s.Bid.Message.Hash() {
+		return nil, ErrPayloadMismatch
+	}
+
+	bid := s.Bid.Message
+	if s.Header.ParentHash != bid.ParentHash || s.Header.Number == nil || s.Header.Number.Uint64() != uint64(bid.Number) ||
+		s.Header.GasUsed != uint64(bid.GasUsed) || s.Header.TxHash != bid.TxHash {
+		return nil, errors.New("header does not match bid")
+	}
+
+	return s.Payload.Decode(bid)
+}
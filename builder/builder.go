@@ -0,0 +1,400 @@
+// Package builder implements the bor block builder mode: a non-signing node
+// which follows the chain, builds candidate blocks for the upcoming in-turn
+// proposers and submits them to a relay.
+package builder
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/builder/api"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+	chainHeadChanSize = 10
+
+	// submitTimeout is the maximum time a single relay submission may take.
+	submitTimeout = 2 * time.Second
+)
+
+var (
+	errNotBor = errors.New("builder mode requires the bor consensus engine")
+
+	buildTimer      = metrics.NewRegisteredTimer("builder/build", nil)
+	submitCounter   = metrics.NewRegisteredCounter("builder/submissions", nil)
+	submitErrMeter  = metrics.NewRegisteredMeter("builder/submissions/errors", nil)
+	buildErrorMeter = metrics.NewRegisteredMeter("builder/build/errors", nil)
+)
+
+// Config contains the settings of the builder mode.
+type Config struct {
+	RelayURL  string        // JSON-RPC endpoint of the relay receiving the blocks
+	Interval  time.Duration // Time between rebuilds of the block for the same slot
+	Proposers int           // Number of upcoming proposers to build blocks for
+}
+
+// DefaultConfig contains the default builder settings.
+var DefaultConfig = Config{
+	Interval:  500 * time.Millisecond,
+	Proposers: 1,
+}
+
+// Backend is the subset of the eth service needed to build blocks.
+type Backend interface {
+	BlockChain() *core.BlockChain
+	Engine() consensus.Engine
+	Miner() *miner.Miner
+}
+
+// Service is the builder mode lifecycle, submitting a freshly built block to
+// the relay for every predicted proposer of the next block.
+type Service struct {
+	config  Config
+	backend Backend
+	engine  *bor.Bor
+	key     *ecdsa.PrivateKey
+
+	relayMu sync.Mutex
+	relay   *rpc.Client
+
+	headSub event.Subscription
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New creates the builder service and registers it on the node. Bids are
+// signed with the given key, which identifies the builder towards relays and
+// proposers.
+func New(stack *node.Node, backend Backend, key *ecdsa.PrivateKey, config Config) (*Service, error) {
+	engine, ok := backend.Engine().(*bor.Bor)
+	if !ok {
+		return nil, errNotBor
+	}
+
+	if config.RelayURL == "" {
+		return nil, errors.New("builder relay url not set")
+	}
+
+	if config.Interval <= 0 {
+		log.Warn("Sanitizing builder interval", "provided", config.Interval, "updated", DefaultConfig.Interval)
+		config.Interval = DefaultConfig.Interval
+	}
+
+	if config.Proposers <= 0 {
+		config.Proposers = DefaultConfig.Proposers
+	}
+
+	s := &Service{
+		config:  config,
+		backend: backend,
+		engine:  engine,
+		key:     key,
+		quit:    make(chan struct{}),
+	}
+
+	stack.RegisterLifecycle(s)
+
+	return s, nil
+}
+
+// Start implements node.Lifecycle, starting to build blocks on every new head.
+func (s *Service) Start() error {
+	headCh := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	s.headSub = s.backend.BlockChain().SubscribeChainHeadEvent(headCh)
+
+	s.wg.Add(1)
+
+	go s.loop(headCh)
+
+	log.Info("Block builder started", "relay", s.config.RelayURL, "builder", s.Address())
+
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the builder.
+func (s *Service) Stop() error {
+	s.headSub.Unsubscribe()
+	close(s.quit)
+	s.wg.Wait()
+
+	s.relayMu.Lock()
+	if s.relay != nil {
+		s.relay.Close()
+	}
+	s.relayMu.Unlock()
+
+	log.Info("Block builder stopped")
+
+	return nil
+}
+
+// Address returns the address of the builder key.
+func (s *Service) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// loop rebuilds the next block every interval until its slot starts, resetting
+// whenever a new head arrives.
+func (s *Service) loop(headCh chan core.ChainHeadEvent) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(0)
+	<-timer.C
+
+	defer timer.Stop()
+
+	var (
+		head *types.Header
+		best map[common.Address]*big.Int // Most valuable submission per proposer for the current head
+	)
+
+	for {
+		select {
+		case ev := <-headCh:
+			head, best = ev.Block.Header(), make(map[common.Address]*big.Int)
+
+			s.buildAndSubmit(head, best)
+			timer.Reset(s.config.Interval)
+
+		case <-timer.C:
+			if head == nil || time.Now().Unix() >= int64(s.slotTime(head)) {
+				continue
+			}
+
+			s.buildAndSubmit(head, best)
+			timer.Reset(s.config.Interval)
+
+		case <-s.headSub.Err():
+			return
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// slotTime returns the timestamp of the in-turn block on top of head.
+func (s *Service) slotTime(head *types.Header) uint64 {
+	number := head.Number.Uint64() + 1
+	return head.Time + bor.CalcProducerDelay(number, 0, s.backend.BlockChain().Config().Bor)
+}
+
+// buildAndSubmit builds the block following head for every predicted proposer
+// and submits it, if it's more valuable than the last one submitted for that
+// proposer. Each block pays its proposer: the fees are credited to it, or to
+// the builder and then transferred through a final payout transaction if the
+// miner is configured with one, so that the value bid is what the proposer
+// gets when re-executing the transactions on its own header. The block is
+// sealed with the builder key, so that relays can re-execute it as is.
+func (s *Service) buildAndSubmit(head *types.Header, best map[common.Address]*big.Int) {
+	number := head.Number.Uint64() + 1
+
+	proposers, err := s.predictProposers(head)
+	if err != nil {
+		log.Debug("Failed to predict proposers", "number", number, "err", err)
+		return
+	}
+
+	timestamp := s.slotTime(head)
+	if now := uint64(time.Now().Unix()); timestamp < now {
+		timestamp = now
+	}
+
+	for _, proposer := range proposers {
+		start := time.Now()
+
+		block, value, err := s.backend.Miner().BuildBlock(head.Hash(), timestamp, proposer)
+		if err != nil {
+			buildErrorMeter.Mark(1)
+			log.Debug("Failed to build block", "number", number, "proposer", proposer, "err", err)
+
+			continue
+		}
+
+		buildTimer.UpdateSince(start)
+
+		if prev := best[proposer]; prev != nil && value.Cmp(prev) <= 0 {
+			continue
+		}
+
+		block, err = s.seal(block)
+		if err != nil {
+			buildErrorMeter.Mark(1)
+			log.Warn("Failed to seal block", "number", number, "err", err)
+
+			continue
+		}
+
+		if err := s.submit(block, value, proposer, s.backend.Miner().FeeRecipient(proposer)); err != nil {
+			submitErrMeter.Mark(1)
+			log.Warn("Failed to submit block to relay", "number", number, "proposer", proposer, "err", err)
+
+			continue
+		}
+
+		best[proposer] = value
+
+		log.Debug("Submitted block to relay", "number", number, "proposer", proposer, "txs", len(block.Transactions()), "gas", block.GasUsed(), "value", value)
+	}
+}
+
+// seal signs the block header with the builder key.
+func (s *Service) seal(block *types.Block) (*types.Block, error) {
+	header := block.Header()
+
+	signFn := func(_ accounts.Account, _ string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), s.key)
+	}
+
+	if err := bor.Sign(signFn, s.Address(), header, s.backend.BlockChain().Config().Bor); err != nil {
+		return nil, err
+	}
+
+	return block.WithSeal(header), nil
+}
+
+// predictProposers returns the proposers expected to ask for the block after
+// head, starting with the in-turn one.
+func (s *Service) predictProposers(head *types.Header) ([]common.Address, error) {
+	chain := s.backend.BlockChain()
+
+	slot, err := s.engine.GetSlot(context.Background(), chain, head)
+	if err != nil {
+		return nil, err
+	}
+
+	proposers := []common.Address{slot.Proposer}
+
+	var borAPI *bor.API
+
+	for _, engineAPI := range s.engine.APIs(chain) {
+		if service, ok := engineAPI.Service.(*bor.API); ok {
+			borAPI = service
+		}
+	}
+
+	if borAPI == nil {
+		return proposers, nil
+	}
+
+	blockNrOrHash := rpc.BlockNumberOrHashWithHash(head.Hash(), false)
+
+	sequence, err := borAPI.GetSnapshotProposerSequence(&blockNrOrHash)
+	if err != nil {
+		log.Debug("Failed to get proposer sequence", "number", head.Number, "err", err)
+		return proposers, nil
+	}
+
+	signers := make([]common.Address, 0, len(sequence.Signers))
+	for _, signer := range sequence.Signers {
+		signers = append(signers, signer.Signer)
+	}
+
+	return rankProposers(slot.Proposer, signers, s.config.Proposers), nil
+}
+
+// rankProposers orders the signer sequence so that it starts at the in-turn
+// proposer and returns at most limit entries.
+func rankProposers(inturn common.Address, sequence []common.Address, limit int) []common.Address {
+	start := 0
+
+	for i, signer := range sequence {
+		if signer == inturn {
+			start = i
+			break
+		}
+	}
+
+	ranked := []common.Address{inturn}
+
+	for i := 1; i < len(sequence) && len(ranked) < limit; i++ {
+		if signer := sequence[(start+i)%len(sequence)]; signer != inturn {
+			ranked = append(ranked, signer)
+		}
+	}
+
+	return ranked
+}
+
+// submit signs a bid for the block and sends it to the relay along with its
+// transactions, sealed header and the account its fees were credited to.
+func (s *Service) submit(block *types.Block, value *big.Int, proposer common.Address, feeRecipient common.Address) error {
+	bid, err := api.SignBid(&api.Bid{
+		ParentHash: block.ParentHash(),
+		Number:     hexutil.Uint64(block.NumberU64()),
+		Proposer:   proposer,
+		TxHash:     api.TxHash(block.Transactions()),
+		Value:      (*hexutil.Big)(value),
+		GasUsed:    hexutil.Uint64(block.GasUsed()),
+		TxCount:    hexutil.Uint64(len(block.Transactions())),
+	}, s.key)
+	if err != nil {
+		return err
+	}
+
+	payload, err := api.NewPayload(bid.Message.Hash(), block.Transactions())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), submitTimeout)
+	defer cancel()
+
+	client, err := s.dialRelay(ctx)
+	if err != nil {
+		return err
+	}
+
+	submission := &api.BlockSubmission{
+		Bid:          bid,
+		Payload:      payload,
+		Header:       block.Header(),
+		FeeRecipient: feeRecipient,
+	}
+
+	if err := client.CallContext(ctx, nil, "relay_submitBlock", submission); err != nil {
+		return err
+	}
+
+	submitCounter.Inc(1)
+
+	return nil
+}
+
+// dialRelay returns the relay connection, connecting on first use.
+func (s *Service) dialRelay(ctx context.Context) (*rpc.Client, error) {
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+
+	if s.relay != nil {
+		return s.relay, nil
+	}
+
+	client, err := rpc.DialContext(ctx, s.config.RelayURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s.relay = client
+
+	return client, nil
+}
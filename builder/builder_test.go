@@ -0,0 +1,153 @@
+package builder
+
+import (
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/builder/api"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// fakeRelay records the submissions it receives.
+type fakeRelay struct {
+	submissions []*api.BlockSubmission
+}
+
+func (r *fakeRelay) SubmitBlock(submission api.BlockSubmission) error {
+	if _, err := submission.Verify(); err != nil {
+		return err
+	}
+
+	r.submissions = append(r.submissions, &submission)
+
+	return nil
+}
+
+func TestRankProposers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = common.HexToAddress("0x01")
+		b = common.HexToAddress("0x02")
+		c = common.HexToAddress("0x03")
+	)
+
+	tests := []struct {
+		inturn   common.Address
+		sequence []common.Address
+		limit    int
+		want     []common.Address
+	}{
+		{a, []common.Address{a, b, c}, 1, []common.Address{a}},
+		{a, []common.Address{a, b, c}, 3, []common.Address{a, b, c}},
+		{b, []common.Address{a, b, c}, 3, []common.Address{b, c, a}},
+		{c, []common.Address{a, b, c}, 2, []common.Address{c, a}},
+		{c, []common.Address{a, b}, 3, []common.Address{c, b}},
+		{a, nil, 3, []common.Address{a}},
+	}
+
+	for i, tt := range tests {
+		if got := rankProposers(tt.inturn, tt.sequence, tt.limit); !equalAddresses(got, tt.want) {
+			t.Errorf("test %d: ranked proposers mismatch: have %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func equalAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestSubmit(t *testing.T) {
+	t.Parallel()
+
+	relay := new(fakeRelay)
+
+	server := rpc.NewServer("test", 0, 0)
+	if err := server.RegisterName("relay", relay); err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	defer server.Stop()
+
+	builderKey, _ := crypto.GenerateKey()
+	userKey, _ := crypto.GenerateKey()
+
+	s := &Service{
+		config: Config{RelayURL: httpServer.URL},
+		key:    builderKey,
+	}
+
+	defer func() {
+		if s.relay != nil {
+			s.relay.Close()
+		}
+	}()
+
+	var (
+		signer   = types.LatestSigner(params.TestChainConfig)
+		proposer = common.HexToAddress("0xb0b")
+		txs      = types.Transactions{
+			types.MustSignNewTx(userKey, signer, &types.LegacyTx{Nonce: 0, Gas: params.TxGas, GasPrice: big.NewInt(params.GWei)}),
+			types.MustSignNewTx(userKey, signer, &types.LegacyTx{Nonce: 1, Gas: params.TxGas, GasPrice: big.NewInt(params.GWei)}),
+		}
+		header = &types.Header{
+			ParentHash: common.HexToHash("0x01"),
+			Number:     big.NewInt(10),
+			GasUsed:    2 * params.TxGas,
+		}
+		block = types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+		value = big.NewInt(12345)
+	)
+
+	if err := s.submit(block, value, proposer, proposer); err != nil {
+		t.Fatalf("failed to submit block: %v", err)
+	}
+
+	if len(relay.submissions) != 1 {
+		t.Fatalf("relay submissions mismatch: have %d, want 1", len(relay.submissions))
+	}
+
+	bid := relay.submissions[0].Bid.Message
+	if bid.ParentHash != header.ParentHash || uint64(bid.Number) != 10 || bid.Proposer != proposer {
+		t.Fatalf("bid slot mismatch: parent %s, number %d, proposer %s", bid.ParentHash, bid.Number, bid.Proposer)
+	}
+
+	if relay.submissions[0].FeeRecipient != proposer {
+		t.Fatalf("fee recipient mismatch: have %s, want %s", relay.submissions[0].FeeRecipient, proposer)
+	}
+
+	if bid.Value.ToInt().Cmp(value) != 0 || uint64(bid.GasUsed) != header.GasUsed || uint64(bid.TxCount) != 2 {
+		t.Fatalf("bid content mismatch: value %v, gas %d, txs %d", bid.Value, bid.GasUsed, bid.TxCount)
+	}
+
+	if addr, err := bid.BuilderAddress(); err != nil || addr != s.Address() {
+		t.Fatalf("builder address mismatch: have %s, want %s (err %v)", addr, s.Address(), err)
+	}
+
+	// Tampered payloads must be rejected
+	submission := relay.submissions[0]
+	submission.Payload.Transactions = submission.Payload.Transactions[:1]
+
+	if _, err := submission.Verify(); !errors.Is(err, api.ErrPayloadMismatch) {
+		t.Fatalf("tampered payload error mismatch: have %v, want %v", err, api.ErrPayloadMismatch)
+	}
+}
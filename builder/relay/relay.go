@@ -0,0 +1,382 @@
+// Package relay implements a block relay sitting between bor block builders
+// and proposers: builders submit sealed blocks along with signed bids, the
+// relay re-executes them on top of the local chain and offers the most
+// valuable valid bid of every slot to the in-turn proposer.
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/builder/api"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrUnknownParent is returned if a submission or bid request builds on a
+	// block the relay doesn't know.
+	ErrUnknownParent = errors.New("unknown parent block")
+
+	// ErrStaleSlot is returned if a submission targets a block which is already
+	// part of the chain.
+	ErrStaleSlot = errors.New("slot already filled")
+
+	// ErrAuthorMismatch is returned if a block isn't sealed by the builder which
+	// signed its bid.
+	ErrAuthorMismatch = errors.New("block not sealed by bid builder")
+
+	// ErrValueMismatch is returned if a block pays less than promised by its bid.
+	ErrValueMismatch = errors.New("block value below bid value")
+
+	// ErrTooManyTxs is returned if a payload holds more transactions than the
+	// block gas limit can fit.
+	ErrTooManyTxs = errors.New("too many transactions for gas limit")
+
+	// ErrNotInTurn is returned if a proposer asks for a slot it isn't in-turn for.
+	ErrNotInTurn = errors.New("proposer not in-turn")
+
+	// ErrUnknownBid is returned if a proposer asks for the payload of a bid the
+	// relay doesn't hold.
+	ErrUnknownBid = errors.New("unknown bid")
+)
+
+// maxSlotBids is the number of most valuable bids of a slot whose payloads are
+// kept, so proposers may still take over a bid outbid since they fetched it.
+const maxSlotBids = 8
+
+var (
+	submissionMeter     = metrics.NewRegisteredMeter("relay/submissions", nil)
+	invalidMeter        = metrics.NewRegisteredMeter("relay/submissions/invalid", nil)
+	validationTimer     = metrics.NewRegisteredTimer("relay/validation", nil)
+	headerRequestMeter  = metrics.NewRegisteredMeter("relay/getheader", nil)
+	payloadRequestMeter = metrics.NewRegisteredMeter("relay/getpayload", nil)
+)
+
+// slot identifies the block a bid is competing for.
+type slot struct {
+	parent   common.Hash
+	proposer common.Address
+}
+
+// auction holds the most valuable valid submissions of a single slot.
+type auction struct {
+	number uint64
+	bids   []*auctionBid // Most valuable bids, best first
+}
+
+// auctionBid is a valid bid along with the payload it commits to.
+type auctionBid struct {
+	bid     *api.SignedBid
+	value   *big.Int
+	payload *api.Payload
+}
+
+// add records a bid if it's among the most valuable ones of the slot, dropping
+// the least valuable one if there are too many.
+func (a *auction) add(bid *api.SignedBid, payload *api.Payload) {
+	var (
+		hash  = bid.Message.Hash()
+		value = bid.Message.Value.ToInt()
+		pos   = len(a.bids)
+	)
+
+	for i, b := range a.bids {
+		if b.bid.Message.Hash() == hash {
+			return
+		}
+
+		if pos == len(a.bids) && value.Cmp(b.value) > 0 {
+			pos = i
+		}
+	}
+
+	if pos >= maxSlotBids {
+		return
+	}
+
+	a.bids = append(a.bids, nil)
+	copy(a.bids[pos+1:], a.bids[pos:])
+	a.bids[pos] = &auctionBid{bid: bid, value: value, payload: payload}
+
+	if len(a.bids) > maxSlotBids {
+		a.bids = a.bids[:maxSlotBids]
+	}
+}
+
+// Relay validates builder submissions and keeps the best bids per slot.
+type Relay struct {
+	chain *core.BlockChain
+
+	mu       sync.RWMutex
+	auctions map[slot]*auction
+}
+
+// New creates a relay validating submissions against the given chain.
+func New(chain *core.BlockChain) *Relay {
+	return &Relay{
+		chain:    chain,
+		auctions: make(map[slot]*auction),
+	}
+}
+
+// Register creates a relay on top of the chain and exposes its builder and
+// proposer facing APIs on the node.
+func Register(stack *node.Node, chain *core.BlockChain) *Relay {
+	r := New(chain)
+	stack.RegisterAPIs(r.APIs())
+
+	log.Info("Block relay enabled")
+
+	return r
+}
+
+// APIs returns the RPC APIs of the relay: the relay namespace receives builder
+// submissions, the builder namespace serves proposers.
+func (r *Relay) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "relay",
+			Service:   &RelayAPI{r},
+		},
+		{
+			Namespace: "builder",
+			Service:   &BuilderAPI{r},
+		},
+	}
+}
+
+// Submit validates a builder submission and records it if it's valid. It
+// becomes the slot's best bid if it's more valuable than the previous one, and
+// is dropped once there are enough more valuable ones.
+func (r *Relay) Submit(sub *api.BlockSubmission) error {
+	submissionMeter.Mark(1)
+
+	value, err := r.validate(sub)
+	if err != nil {
+		invalidMeter.Mark(1)
+		return err
+	}
+
+	bid := sub.Bid.Message
+	key := slot{parent: bid.ParentHash, proposer: bid.Proposer}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(r.chain.CurrentBlock().Number.Uint64())
+
+	a, ok := r.auctions[key]
+	if !ok {
+		a = &auction{number: uint64(bid.Number)}
+		r.auctions[key] = a
+	}
+
+	a.add(sub.Bid, sub.Payload)
+
+	log.Debug("Accepted builder submission", "number", uint64(bid.Number), "proposer", bid.Proposer, "value", value, "bid", bid.Value)
+
+	return nil
+}
+
+// validate checks a submission and re-executes its block on top of the parent
+// state, returning its value to the proposer: the tips and the transfers, as
+// the proposer computes it when taking the payload over.
+func (r *Relay) validate(sub *api.BlockSubmission) (*big.Int, error) {
+	start := time.Now()
+	defer validationTimer.UpdateSince(start)
+
+	txs, err := sub.Verify()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		bid    = sub.Bid.Message
+		header = types.CopyHeader(sub.Header)
+	)
+
+	parent := r.chain.GetHeaderByHash(bid.ParentHash)
+	if parent == nil {
+		return nil, ErrUnknownParent
+	}
+
+	if parent.Number.Uint64()+1 != uint64(bid.Number) {
+		return nil, fmt.Errorf("invalid number: have %d, want %d", uint64(bid.Number), parent.Number.Uint64()+1)
+	}
+
+	if current := r.chain.CurrentBlock(); current.Number.Uint64() >= uint64(bid.Number) {
+		return nil, ErrStaleSlot
+	}
+
+	builder, err := bid.BuilderAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := r.chain.Engine().Author(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if author != builder {
+		return nil, fmt.Errorf("%w: have %s, want %s", ErrAuthorMismatch, author, builder)
+	}
+
+	if r.chain.Config().IsLondon(header.Number) {
+		if err := misc.VerifyEip1559Header(r.chain.Config(), parent, header); err != nil {
+			return nil, err
+		}
+	} else if err := misc.VerifyGaslimit(parent.GasLimit, header.GasLimit); err != nil {
+		return nil, err
+	}
+
+	// Submissions come from anyone, so bound the work of re-executing them
+	// before starting: the gas limit follows the parent's, and every
+	// transaction uses at least the base transaction gas out of it
+	if header.GasUsed > header.GasLimit {
+		return nil, fmt.Errorf("invalid gas used: have %d, gas limit %d", header.GasUsed, header.GasLimit)
+	}
+
+	if uint64(len(txs)) > header.GasLimit/params.TxGas {
+		return nil, fmt.Errorf("%w: have %d, gas limit %d", ErrTooManyTxs, len(txs), header.GasLimit)
+	}
+
+	statedb, err := r.chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute the block as the chain would, measuring its value to the
+	// proposer from the balances of the proposer along the block and keeping
+	// the state syncs it commits away from the chain, which only holds the
+	// ones of its own blocks
+	var (
+		block     = types.NewBlockWithHeader(header).WithBody(txs, nil)
+		collector = core.NewStateSyncCollector(r.chain)
+		tracer    = newBalanceTracer(statedb, bid.Proposer)
+		vmConfig  = *r.chain.GetVMConfig()
+	)
+
+	vmConfig.Tracer = tracer
+
+	receipts, _, usedGas, err := collector.Processor().Process(block, statedb, vmConfig, context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.chain.Validator().ValidateState(block, statedb, receipts, usedGas); err != nil {
+		return nil, err
+	}
+
+	value := types.NewBlockProfit(r.chain.Config(), header, bid.Proposer, txs, receipts, tracer.balances, collector.GetStateSync()).Value()
+	if value.Cmp(bid.Value.ToInt()) < 0 {
+		return nil, fmt.Errorf("%w: have %v, bid %v", ErrValueMismatch, value, bid.Value)
+	}
+
+	return value, nil
+}
+
+// BestBid returns the most valuable bid for the given slot, nil if none.
+func (r *Relay) BestBid(req *api.BidRequest) (*api.SignedBid, error) {
+	parent := r.chain.GetHeaderByHash(req.ParentHash)
+	if parent == nil {
+		return nil, ErrUnknownParent
+	}
+
+	if engine, ok := r.chain.Engine().(*bor.Bor); ok {
+		s, err := engine.GetSlot(context.Background(), r.chain, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.Proposer != req.Proposer {
+			return nil, fmt.Errorf("%w: have %s, want %s", ErrNotInTurn, req.Proposer, s.Proposer)
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a := r.auctions[slot{parent: req.ParentHash, proposer: req.Proposer}]
+	if a == nil || len(a.bids) == 0 {
+		return nil, nil
+	}
+
+	return a.bids[0].bid, nil
+}
+
+// Payload reveals the transactions of a bid previously accepted by the relay.
+func (r *Relay) Payload(bid *api.SignedBid) (*api.Payload, error) {
+	if err := bid.Verify(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a := r.auctions[slot{parent: bid.Message.ParentHash, proposer: bid.Message.Proposer}]
+	if a == nil {
+		return nil, ErrUnknownBid
+	}
+
+	hash := bid.Message.Hash()
+
+	for _, b := range a.bids {
+		if b.bid.Message.Hash() == hash {
+			return b.payload, nil
+		}
+	}
+
+	return nil, ErrUnknownBid
+}
+
+// prune drops the auctions of blocks up to and including head. The lock must
+// be held by the caller.
+func (r *Relay) prune(head uint64) {
+	for key, a := range r.auctions {
+		if a.number <= head {
+			delete(r.auctions, key)
+		}
+	}
+}
+
+// RelayAPI is the builder facing API of the relay.
+type RelayAPI struct {
+	relay *Relay
+}
+
+// SubmitBlock validates and records a block submitted by a builder.
+func (s *RelayAPI) SubmitBlock(ctx context.Context, submission api.BlockSubmission) error {
+	return s.relay.Submit(&submission)
+}
+
+// BuilderAPI is the proposer facing API of the relay, matching the endpoints
+// validators query when outsourcing block bodies.
+type BuilderAPI struct {
+	relay *Relay
+}
+
+// GetHeader returns the best bid for the requested slot.
+func (s *BuilderAPI) GetHeader(ctx context.Context, req api.BidRequest) (*api.SignedBid, error) {
+	headerRequestMeter.Mark(1)
+	return s.relay.BestBid(&req)
+}
+
+// GetPayload returns the transactions of the given bid.
+func (s *BuilderAPI) GetPayload(ctx context.Context, bid api.SignedBid) (*api.Payload, error) {
+	payloadRequestMeter.Mark(1)
+	return s.relay.Payload(&bid)
+}
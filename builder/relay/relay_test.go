@@ -0,0 +1,208 @@
+package relay
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/builder/api"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	userKey, _ = crypto.GenerateKey()
+	userAddr   = crypto.PubkeyToAddress(userKey.PublicKey)
+	proposer   = common.HexToAddress("0xb0b")
+	payment    = big.NewInt(params.GWei)
+)
+
+// newTestRelay creates a relay on top of a chain only containing the genesis
+// block, along with a block for each given coinbase built on top of it with a
+// single transaction tipping 1 gwei per gas to the coinbase and paying 1 gwei
+// to the proposer.
+func newTestRelay(t *testing.T, coinbases ...common.Address) (*Relay, []*types.Block) {
+	t.Helper()
+
+	var (
+		engine = ethash.NewFaker()
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{userAddr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+
+	t.Cleanup(chain.Stop)
+
+	blocks := make([]*types.Block, 0, len(coinbases))
+
+	for _, coinbase := range coinbases {
+		_, generated, _ := core.GenerateChainWithGenesis(gspec, engine, 1, func(i int, gen *core.BlockGen) {
+			gen.SetCoinbase(coinbase)
+			gen.AddTx(types.MustSignNewTx(userKey, signer, &types.DynamicFeeTx{
+				ChainID:   gspec.Config.ChainID,
+				Nonce:     0,
+				GasTipCap: big.NewInt(params.GWei),
+				GasFeeCap: new(big.Int).Add(gen.BaseFee(), big.NewInt(params.GWei)),
+				Gas:       params.TxGas,
+				To:        &proposer,
+				Value:     payment,
+			}))
+		})
+		blocks = append(blocks, generated[0])
+	}
+
+	return New(chain), blocks
+}
+
+func newTestSubmission(t *testing.T, key *ecdsa.PrivateKey, block *types.Block, value *big.Int) *api.BlockSubmission {
+	t.Helper()
+
+	bid, err := api.SignBid(&api.Bid{
+		ParentHash: block.ParentHash(),
+		Number:     hexutil.Uint64(block.NumberU64()),
+		Proposer:   proposer,
+		TxHash:     api.TxHash(block.Transactions()),
+		Value:      (*hexutil.Big)(value),
+		GasUsed:    hexutil.Uint64(block.GasUsed()),
+		TxCount:    hexutil.Uint64(len(block.Transactions())),
+	}, key)
+	if err != nil {
+		t.Fatalf("failed to sign bid: %v", err)
+	}
+
+	payload, err := api.NewPayload(bid.Message.Hash(), block.Transactions())
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+
+	return &api.BlockSubmission{Bid: bid, Payload: payload, Header: block.Header(), FeeRecipient: block.Coinbase()}
+}
+
+func TestRelaySubmit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		builderKey, _ = crypto.GenerateKey()
+		otherKey, _   = crypto.GenerateKey()
+		builder       = crypto.PubkeyToAddress(builderKey.PublicKey)
+		other         = crypto.PubkeyToAddress(otherKey.PublicKey)
+	)
+
+	relay, blocks := newTestRelay(t, builder, other)
+
+	tampered := blocks[0].Header()
+	tampered.Root = common.HexToHash("0xdead")
+
+	overused := blocks[0].Header()
+	overused.GasUsed = overused.GasLimit + 1
+
+	// More transactions than the gas limit can fit are rejected before being
+	// executed, even if valid on their own
+	crowded := make(types.Transactions, blocks[0].GasLimit()/params.TxGas+1)
+	for i := range crowded {
+		crowded[i] = blocks[0].Transactions()[0]
+	}
+
+	oversized := blocks[0].Header()
+	oversized.TxHash = api.TxHash(crowded)
+
+	tests := []struct {
+		name string
+		sub  *api.BlockSubmission
+		err  error
+	}{
+		{"overbid", newTestSubmission(t, builderKey, blocks[0], new(big.Int).Add(payment, common.Big1)), ErrValueMismatch},
+		{"wrong author", newTestSubmission(t, builderKey, blocks[1], payment), ErrAuthorMismatch},
+		{"tampered state root", newTestSubmission(t, builderKey, blocks[0].WithSeal(tampered), payment), nil},
+		{"gas used above limit", newTestSubmission(t, builderKey, blocks[0].WithSeal(overused), payment), nil},
+		{"too many transactions", newTestSubmission(t, builderKey, blocks[0].WithSeal(oversized).WithBody(crowded, nil), payment), ErrTooManyTxs},
+	}
+
+	for _, tt := range tests {
+		err := relay.Submit(tt.sub)
+		if err == nil || (tt.err != nil && !errors.Is(err, tt.err)) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	req := &api.BidRequest{ParentHash: blocks[0].ParentHash(), Number: 1, Proposer: proposer}
+
+	if bid, err := relay.BestBid(req); err != nil || bid != nil {
+		t.Fatalf("unexpected bid before valid submission: %v (err %v)", bid, err)
+	}
+
+	// A valid submission becomes the best bid and isn't replaced by a lower one.
+	// The fees go to the builder, so it's worth the payment to the proposer.
+	best := newTestSubmission(t, builderKey, blocks[0], payment)
+	if err := relay.Submit(best); err != nil {
+		t.Fatalf("failed to submit valid block: %v", err)
+	}
+
+	lower := newTestSubmission(t, builderKey, blocks[0], common.Big1)
+	if err := relay.Submit(lower); err != nil {
+		t.Fatalf("failed to submit lower bid: %v", err)
+	}
+
+	bid, err := relay.BestBid(req)
+	if err != nil {
+		t.Fatalf("failed to get best bid: %v", err)
+	}
+
+	if bid == nil || bid.Message.Hash() != best.Bid.Message.Hash() {
+		t.Fatalf("best bid mismatch: have %v, want %v", bid, best.Bid)
+	}
+
+	// Payloads of the accepted bids are revealed, unknown ones aren't
+	payload, err := relay.Payload(bid)
+	if err != nil {
+		t.Fatalf("failed to get payload: %v", err)
+	}
+
+	txs, err := payload.Decode(bid.Message)
+	if err != nil || len(txs) != 1 {
+		t.Fatalf("payload mismatch: %d txs (err %v)", len(txs), err)
+	}
+
+	if _, err := relay.Payload(lower.Bid); err != nil {
+		t.Fatalf("failed to get payload of lower bid: %v", err)
+	}
+
+	unknown := newTestSubmission(t, builderKey, blocks[0], common.Big2)
+	if _, err := relay.Payload(unknown.Bid); !errors.Is(err, ErrUnknownBid) {
+		t.Fatalf("unknown bid error mismatch: have %v, want %v", err, ErrUnknownBid)
+	}
+
+	// Only the payloads of the most valuable bids are kept
+	for i := 0; i < maxSlotBids; i++ {
+		if err := relay.Submit(newTestSubmission(t, builderKey, blocks[0], big.NewInt(int64(2+i)))); err != nil {
+			t.Fatalf("failed to submit bid %d: %v", i, err)
+		}
+	}
+
+	if _, err := relay.Payload(lower.Bid); !errors.Is(err, ErrUnknownBid) {
+		t.Fatalf("outbid payload error mismatch: have %v, want %v", err, ErrUnknownBid)
+	}
+
+	if _, err := relay.Payload(best.Bid); err != nil {
+		t.Fatalf("failed to get payload of best bid: %v", err)
+	}
+
+	if bid, _ := relay.BestBid(req); bid == nil || bid.Message.Hash() != best.Bid.Message.Hash() {
+		t.Fatalf("best bid mismatch after outbidding: have %v, want %v", bid, best.Bid)
+	}
+}
@@ -0,0 +1,49 @@
+package relay
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// balanceTracer records the balance of an account after every transaction
+// of a block, once the fees of the transaction are credited.
+type balanceTracer struct {
+	statedb  *state.StateDB
+	account  common.Address
+	balances []*big.Int
+}
+
+// newBalanceTracer creates a tracer recording the balances of account in
+// statedb, starting with its current one.
+func newBalanceTracer(statedb *state.StateDB, account common.Address) *balanceTracer {
+	return &balanceTracer{
+		statedb:  statedb,
+		account:  account,
+		balances: []*big.Int{statedb.GetBalance(account)},
+	}
+}
+
+func (t *balanceTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *balanceTracer) CaptureTxEnd(restGas uint64) {
+	t.balances = append(t.balances, t.statedb.GetBalance(t.account))
+}
+
+func (t *balanceTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *balanceTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *balanceTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *balanceTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *balanceTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *balanceTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
@@ -103,6 +103,14 @@ var (
 
 	errUncleDetected     = errors.New("uncles not allowed")
 	errUnknownValidators = errors.New("unknown validators")
+
+	// ErrSpanCommit is returned if the next span can't be committed at the start
+	// of a sprint.
+	ErrSpanCommit = errors.New("span commit failed")
+
+	// ErrStateSyncCommit is returned if the pending state-sync events can't be
+	// committed at the start of a sprint.
+	ErrStateSyncCommit = errors.New("state sync commit failed")
 )
 
 // SignerFn is a signer callback function to request a header to be signed by a
@@ -227,6 +235,8 @@ type Bor struct {
 	fakeDiff      bool // Skip difficulty verifications
 	devFakeAuthor bool
 
+	clock func() time.Time // Source of the current time, simulated by network simulations
+
 	closeOnce sync.Once
 }
 
@@ -267,6 +277,7 @@ func New(
 		GenesisContractsClient: genesisContracts,
 		HeimdallClient:         heimdallClient,
 		devFakeAuthor:          devFakeAuthor,
+		clock:                  time.Now,
 	}
 
 	c.authorizedSigner.Store(&signer{
@@ -302,6 +313,12 @@ func (c *Bor) GetSpanner() Spanner {
 	return c.spanner
 }
 
+// SetClock sets the source of the current time the blocks are prepared,
+// sealed and verified against, to run the engine on a simulated clock.
+func (c *Bor) SetClock(clock func() time.Time) {
+	c.clock = clock
+}
+
 func (c *Bor) SetSpanner(spanner Spanner) {
 	c.spanner = spanner
 }
@@ -340,7 +357,7 @@ func (c *Bor) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 	number := header.Number.Uint64()
 
 	// Don't waste time checking blocks from the future
-	if header.Time > uint64(time.Now().Unix()) {
+	if header.Time > uint64(c.clock().Unix()) {
 		return consensus.ErrFutureBlock
 	}
 
@@ -799,8 +816,8 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 	}
 
 	header.Time = parent.Time + CalcProducerDelay(number, succession, c.config)
-	if header.Time < uint64(time.Now().Unix()) {
-		header.Time = uint64(time.Now().Unix())
+	if now := uint64(c.clock().Unix()); header.Time < now {
+		header.Time = now
 	}
 
 	return nil
@@ -809,30 +826,12 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 // Finalize implements consensus.Engine, ensuring no uncles are set, nor block
 // rewards given.
 func (c *Bor) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, _ []*types.Transaction, _ []*types.Header, withdrawals []*types.Withdrawal) {
-	var (
-		stateSyncData []*types.StateSyncData
-		err           error
-	)
-
 	headerNumber := header.Number.Uint64()
 
-	if IsSprintStart(headerNumber, c.config.CalculateSprint(headerNumber)) {
-		ctx := context.Background()
-		cx := statefull.ChainContext{Chain: chain, Bor: c}
-		// check and commit span
-		if err := c.checkAndCommitSpan(ctx, state, header, cx); err != nil {
-			log.Error("Error while committing span", "error", err)
-			return
-		}
-
-		if c.HeimdallClient != nil {
-			// commit states
-			stateSyncData, err = c.CommitStates(ctx, state, header, cx)
-			if err != nil {
-				log.Error("Error while committing states", "error", err)
-				return
-			}
-		}
+	stateSyncData, err := c.CommitSystemState(context.Background(), chain, header, state)
+	if err != nil {
+		log.Error("Error while committing system state", "error", err)
+		return
 	}
 
 	if err = c.changeContractCodeIfNeeded(headerNumber, state); err != nil {
@@ -845,7 +844,7 @@ func (c *Bor) Finalize(chain consensus.ChainHeaderReader, header *types.Header,
 	header.UncleHash = types.CalcUncleHash(nil)
 
 	// Set state sync data to blockchain
-	bc := chain.(*core.BlockChain)
+	bc := chain.(core.BorStateSyncer)
 	bc.SetStateSync(stateSyncData)
 }
 
@@ -1011,7 +1010,7 @@ func (c *Bor) Seal(ctx context.Context, chain consensus.ChainHeaderReader, block
 	}
 
 	// Sweet, the protocol permits us to sign the block, wait for our time
-	delay := time.Unix(int64(header.Time), 0).Sub(time.Now()) // nolint: gosimple
+	delay := time.Unix(int64(header.Time), 0).Sub(c.clock()) // nolint: gosimple
 	// wiggle was already accounted for in header.Time, this is just for logging
 	wiggle := time.Duration(successionNumber) * time.Duration(c.config.CalculateBackupMultiplier(number)) * time.Second
 
@@ -1122,6 +1121,36 @@ func (c *Bor) Close() error {
 	return nil
 }
 
+// CommitSystemState performs the span and state-sync commits due at the start
+// of every sprint on state. Errors are wrapped in ErrSpanCommit or
+// ErrStateSyncCommit depending on the failing step.
+func (c *Bor) CommitSystemState(ctx context.Context, chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) ([]*types.StateSyncData, error) {
+	headerNumber := header.Number.Uint64()
+
+	if !IsSprintStart(headerNumber, c.config.CalculateSprint(headerNumber)) {
+		return nil, nil
+	}
+
+	cx := statefull.ChainContext{Chain: chain, Bor: c}
+
+	// check and commit span
+	if err := c.checkAndCommitSpan(ctx, state, header, cx); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSpanCommit, err)
+	}
+
+	if c.HeimdallClient == nil {
+		return nil, nil
+	}
+
+	// commit states
+	stateSyncData, err := c.CommitStates(ctx, state, header, cx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStateSyncCommit, err)
+	}
+
+	return stateSyncData, nil
+}
+
 func (c *Bor) checkAndCommitSpan(
 	ctx context.Context,
 	state *state.StateDB,
@@ -1288,6 +1317,7 @@ func (c *Bor) CommitStates(
 			return nil, err
 		}
 
+		stateData.Gas = gasUsed
 		totalGas += int(gasUsed)
 
 		lastStateID++
@@ -1317,6 +1347,47 @@ func (c *Bor) GetCurrentValidators(ctx context.Context, headerHash common.Hash,
 	return c.spanner.GetCurrentValidatorsByHash(ctx, headerHash, blockNumber)
 }
 
+// Slot describes the proposer schedule of the block built on top of a parent.
+type Slot struct {
+	Number     uint64         // Number of the block to be built
+	Proposer   common.Address // In-turn proposer of the block
+	Span       uint64         // Span the block belongs to
+	Sprint     uint64         // Sprint the block belongs to
+	Succession int            // Succession number of the local signer, -1 if it isn't a validator
+}
+
+// GetSlot returns the proposer schedule for the block following parent, as
+// seen by the locally authorized signer.
+func (c *Bor) GetSlot(ctx context.Context, chain consensus.ChainHeaderReader, parent *types.Header) (*Slot, error) {
+	number := parent.Number.Uint64() + 1
+
+	snap, err := c.snapshot(chain, parent.Number.Uint64(), parent.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	currentSpan, err := c.spanner.GetCurrentSpan(ctx, parent.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	slot := &Slot{
+		Number:     number,
+		Proposer:   snap.ValidatorSet.GetProposer().Address,
+		Span:       currentSpan.ID,
+		Sprint:     number / c.config.CalculateSprint(number),
+		Succession: -1,
+	}
+
+	if signer := c.authorizedSigner.Load().signer; snap.ValidatorSet.HasAddress(signer) {
+		if slot.Succession, err = snap.GetSignerSuccessionNumber(signer); err != nil {
+			return nil, err
+		}
+	}
+
+	return slot, nil
+}
+
 //
 // Private methods
 //
@@ -0,0 +1,465 @@
+// Package failover implements a Heimdall client spreading its requests over
+// several Heimdall endpoints. The endpoints are health checked and scored on
+// their latency and errors, the requests fail over to the best endpoint when
+// the current one errors, and the critical responses are cross-checked between
+// endpoints to detect a lying or stale Heimdall.
+package failover
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// ErrCrossCheckFailed is returned if the endpoints disagree on a response
+	// and no majority of them agrees on one.
+	ErrCrossCheckFailed = errors.New("heimdall endpoints disagree")
+)
+
+const (
+	attemptTimeout      = 10 * time.Second // Time an endpoint has to answer, its own retries included
+	retryInterval       = 5 * time.Second  // Time to wait after all the endpoints failed
+	healthCheckInterval = 30 * time.Second
+	healthCheckTimeout  = 5 * time.Second
+	crossCheckTimeout   = 5 * time.Second
+
+	maxFailures      = 3                // Consecutive failures after which an endpoint is unhealthy
+	maxCheckpointLag = 1                // Checkpoints an endpoint can lag behind the others before it is stale
+	faultCooldown    = 10 * time.Minute // Time an endpoint losing a cross-check stays unhealthy
+	switchFactor     = 2                // Score ratio for a healthy endpoint to be switched away from
+)
+
+var (
+	failoverMeter  = metrics.NewRegisteredMeter("client/failover/switches", nil)
+	failureMeter   = metrics.NewRegisteredMeter("client/failover/failures", nil)
+	conflictMeter  = metrics.NewRegisteredMeter("client/crosscheck/conflicts", nil)
+	crossCheckFail = metrics.NewRegisteredMeter("client/crosscheck/failures", nil)
+)
+
+// Endpoint is a Heimdall to spread the requests over.
+type Endpoint struct {
+	URL    string // URL or address the client connects to, for reporting
+	Client bor.IHeimdallClient
+}
+
+// EndpointStatus reports the state of an endpoint.
+type EndpointStatus struct {
+	URL      string
+	Current  bool
+	Healthy  bool
+	Score    float64
+	Latency  time.Duration
+	Failures int
+}
+
+// Client is an IHeimdallClient failing over between several endpoints.
+type Client struct {
+	endpoints []*endpoint
+	current   *endpoint
+
+	crossCheck bool
+
+	attemptTimeout      time.Duration
+	retryInterval       time.Duration
+	healthCheckInterval time.Duration
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+// NewClient creates a client failing over between the given endpoints, in
+// order of preference until their health is known. If crossCheck is set, the
+// spans and milestones are cross-checked between the endpoints.
+func NewClient(endpoints []Endpoint, crossCheck bool) *Client {
+	c := &Client{
+		crossCheck:          crossCheck,
+		attemptTimeout:      attemptTimeout,
+		retryInterval:       retryInterval,
+		healthCheckInterval: healthCheckInterval,
+		closeCh:             make(chan struct{}),
+	}
+
+	for _, e := range endpoints {
+		c.endpoints = append(c.endpoints, &endpoint{url: e.URL, client: e.Client})
+	}
+
+	c.current = c.endpoints[0]
+
+	c.wg.Add(1)
+
+	go c.healthLoop()
+
+	return c
+}
+
+// Endpoints reports the state of the endpoints.
+func (c *Client) Endpoints() []EndpointStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	statuses := make([]EndpointStatus, 0, len(c.endpoints))
+
+	for _, e := range c.endpoints {
+		statuses = append(statuses, EndpointStatus{
+			URL:      e.url,
+			Current:  e == c.current,
+			Healthy:  e.healthy(),
+			Score:    e.score(),
+			Latency:  e.latency,
+			Failures: e.failures,
+		})
+	}
+
+	return statuses
+}
+
+// Current returns the URL of the endpoint the requests are sent to first.
+func (c *Client) Current() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.current.url
+}
+
+// Close stops the health checks and closes the endpoint clients.
+func (c *Client) Close() {
+	close(c.closeCh)
+	c.wg.Wait()
+
+	for _, e := range c.endpoints {
+		e.client.Close()
+	}
+}
+
+func (c *Client) StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	events, _, err := call(ctx, c, func(ctx context.Context, client bor.IHeimdallClient) ([]*clerk.EventRecordWithTime, error) {
+		return client.StateSyncEvents(ctx, fromID, to)
+	})
+
+	return events, err
+}
+
+// Span fetches the span, cross-checked as it determines the validators.
+func (c *Client) Span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	fetch := func(ctx context.Context, client bor.IHeimdallClient) (*span.HeimdallSpan, error) {
+		return client.Span(ctx, spanID)
+	}
+
+	sp, from, err := call(ctx, c, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	return crossCheck(ctx, c, "span", from, sp, fetch, func(_, _ *span.HeimdallSpan) bool {
+		return true
+	})
+}
+
+func (c *Client) FetchCheckpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	cp, _, err := call(ctx, c, func(ctx context.Context, client bor.IHeimdallClient) (*checkpoint.Checkpoint, error) {
+		return client.FetchCheckpoint(ctx, number)
+	})
+
+	return cp, err
+}
+
+func (c *Client) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	count, _, err := call(ctx, c, func(ctx context.Context, client bor.IHeimdallClient) (int64, error) {
+		return client.FetchCheckpointCount(ctx)
+	})
+
+	return count, err
+}
+
+// FetchMilestone fetches the latest milestone, cross-checked with the endpoints
+// having the same latest milestone, as it finalizes blocks.
+func (c *Client) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	fetch := func(ctx context.Context, client bor.IHeimdallClient) (*milestone.Milestone, error) {
+		return client.FetchMilestone(ctx)
+	}
+
+	m, from, err := call(ctx, c, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	return crossCheck(ctx, c, "milestone", from, m, fetch, func(a, b *milestone.Milestone) bool {
+		return a.EndBlock != nil && b.EndBlock != nil && a.EndBlock.Cmp(b.EndBlock) == 0
+	})
+}
+
+func (c *Client) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	count, _, err := call(ctx, c, func(ctx context.Context, client bor.IHeimdallClient) (int64, error) {
+		return client.FetchMilestoneCount(ctx)
+	})
+
+	return count, err
+}
+
+func (c *Client) FetchNoAckMilestone(ctx context.Context, milestoneID string) error {
+	_, _, err := call(ctx, c, func(ctx context.Context, client bor.IHeimdallClient) (struct{}, error) {
+		return struct{}{}, client.FetchNoAckMilestone(ctx, milestoneID)
+	})
+
+	return err
+}
+
+func (c *Client) FetchLastNoAckMilestone(ctx context.Context) (string, error) {
+	id, _, err := call(ctx, c, func(ctx context.Context, client bor.IHeimdallClient) (string, error) {
+		return client.FetchLastNoAckMilestone(ctx)
+	})
+
+	return id, err
+}
+
+func (c *Client) FetchMilestoneID(ctx context.Context, milestoneID string) error {
+	_, _, err := call(ctx, c, func(ctx context.Context, client bor.IHeimdallClient) (struct{}, error) {
+		return struct{}{}, client.FetchMilestoneID(ctx, milestoneID)
+	})
+
+	return err
+}
+
+// isAnswer returns whether err is an answer of Heimdall, rather than a failure
+// of the endpoint.
+func isAnswer(err error) bool {
+	return err == nil ||
+		errors.Is(err, heimdall.ErrServiceUnavailable) ||
+		errors.Is(err, heimdall.ErrNotInRejectedList) ||
+		errors.Is(err, heimdall.ErrNotInMilestoneList)
+}
+
+// call sends the request to the endpoints in order of preference until one of
+// them answers, and starts over after a while if they all failed, until ctx is
+// done or the client is closed. It returns the answer and the endpoint which
+// gave it.
+func call[T any](ctx context.Context, c *Client, fetch func(context.Context, bor.IHeimdallClient) (T, error)) (T, *endpoint, error) {
+	var zero T
+
+	for {
+		for _, e := range c.candidates() {
+			attemptCtx, cancel := context.WithTimeout(ctx, c.attemptTimeout)
+			start := time.Now()
+			res, err := fetch(attemptCtx, e.client)
+
+			cancel()
+
+			if isAnswer(err) {
+				c.succeeded(e, time.Since(start))
+				c.use(e)
+
+				return res, e, err
+			}
+
+			if ctx.Err() != nil {
+				return zero, nil, ctx.Err()
+			}
+
+			select {
+			case <-c.closeCh:
+				return zero, nil, heimdall.ErrShutdownDetected
+			default:
+			}
+
+			c.failed(e)
+			log.Warn("Heimdall endpoint failed, failing over", "endpoint", e.url, "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return zero, nil, ctx.Err()
+		case <-c.closeCh:
+			return zero, nil, heimdall.ErrShutdownDetected
+		case <-time.After(c.retryInterval):
+		}
+	}
+}
+
+// vote is the response of an endpoint to a cross-checked request.
+type vote[T any] struct {
+	endpoint *endpoint
+	res      T
+	key      string
+}
+
+// crossCheck sends the request answered with res by from to the other healthy
+// endpoints, and returns the response a majority of the endpoints agrees on.
+// The responses which aren't comparable to res, like the latest milestone of
+// an endpoint lagging behind, don't take part in the vote. The endpoints
+// disagreeing with the majority are marked faulty, and if there is none, no
+// response can be trusted and ErrCrossCheckFailed is returned.
+func crossCheck[T any](ctx context.Context, c *Client, kind string, from *endpoint, res T, fetch func(context.Context, bor.IHeimdallClient) (T, error), comparable func(a, b T) bool) (T, error) {
+	var zero T
+
+	if !c.crossCheck {
+		return res, nil
+	}
+
+	var others []*endpoint
+
+	for _, e := range c.candidates() {
+		if e != from && c.isHealthy(e) {
+			others = append(others, e)
+		}
+	}
+
+	if len(others) == 0 {
+		return res, nil
+	}
+
+	var (
+		votes = []*vote[T]{{endpoint: from, res: res, key: responseKey(res)}}
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+	)
+
+	for _, e := range others {
+		wg.Add(1)
+
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, crossCheckTimeout)
+			defer cancel()
+
+			other, err := fetch(checkCtx, e.client)
+			if err != nil || !comparable(res, other) {
+				log.Debug("Skipped Heimdall cross-check", "kind", kind, "endpoint", e.url, "err", err)
+				return
+			}
+
+			mu.Lock()
+			votes = append(votes, &vote[T]{endpoint: e, res: other, key: responseKey(other)})
+			mu.Unlock()
+		}(e)
+	}
+
+	wg.Wait()
+
+	counts := make(map[string]int)
+	for _, v := range votes {
+		counts[v.key]++
+	}
+
+	if len(counts) == 1 {
+		return res, nil
+	}
+
+	conflictMeter.Mark(1)
+
+	for key, count := range counts {
+		if 2*count <= len(votes) {
+			continue
+		}
+
+		var agreed *vote[T]
+
+		for _, v := range votes {
+			if v.key == key {
+				agreed = v
+				continue
+			}
+
+			c.faulted(v.endpoint)
+			log.Error("Heimdall endpoint disagrees with the others, ignoring it", "kind", kind, "endpoint", v.endpoint.url, "cooldown", faultCooldown)
+		}
+
+		c.use(agreed.endpoint)
+
+		return agreed.res, nil
+	}
+
+	crossCheckFail.Mark(1)
+
+	urls := make([]string, 0, len(votes))
+	for _, v := range votes {
+		urls = append(urls, v.endpoint.url)
+	}
+
+	log.Error("Heimdall endpoints disagree, no response can be trusted", "kind", kind, "endpoints", urls)
+
+	return zero, ErrCrossCheckFailed
+}
+
+// candidates returns the endpoints in order of preference: the current one if
+// healthy, then the healthy ones and the unhealthy ones, the best scored first.
+func (c *Client) candidates() []*endpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	candidates := make([]*endpoint, len(c.endpoints))
+	copy(candidates, c.endpoints)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+
+		if a.healthy() != b.healthy() {
+			return a.healthy()
+		}
+
+		if a.healthy() && (a == c.current || b == c.current) {
+			return a == c.current
+		}
+
+		return a.score() < b.score()
+	})
+
+	return candidates
+}
+
+// use makes e the current endpoint.
+func (c *Client) use(e *endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e == c.current {
+		return
+	}
+
+	log.Info("Switched Heimdall endpoint", "from", c.current.url, "to", e.url)
+	failoverMeter.Mark(1)
+
+	c.current = e
+}
+
+func (c *Client) isHealthy(e *endpoint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return e.healthy()
+}
+
+func (c *Client) succeeded(e *endpoint, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.succeeded(latency)
+}
+
+func (c *Client) failed(e *endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	failureMeter.Mark(1)
+	e.failures++
+}
+
+func (c *Client) faulted(e *endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.faultyUntil = time.Now().Add(faultCooldown)
+}
@@ -0,0 +1,187 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/mockserver"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+)
+
+// newTestClient starts n mock Heimdalls serving the first span, and a client
+// failing over between them quickly.
+func newTestClient(t *testing.T, n int, crossCheck bool) (*Client, []*mockserver.Server) {
+	t.Helper()
+
+	var (
+		servers   = make([]*mockserver.Server, n)
+		endpoints = make([]Endpoint, n)
+	)
+
+	for i := range servers {
+		servers[i] = mockserver.New()
+		t.Cleanup(servers[i].Close)
+
+		servers[i].AddSpan(&span.HeimdallSpan{Span: span.Span{ID: 1, StartBlock: 256, EndBlock: 6655}, ChainID: "15001"})
+
+		endpoints[i] = Endpoint{URL: servers[i].URL(), Client: heimdall.NewHeimdallClient(servers[i].URL())}
+	}
+
+	c := NewClient(endpoints, crossCheck)
+	t.Cleanup(c.Close)
+
+	c.attemptTimeout = 200 * time.Millisecond
+	c.retryInterval = 50 * time.Millisecond
+
+	return c, servers
+}
+
+func TestFailover(t *testing.T) {
+	t.Parallel()
+
+	c, servers := newTestClient(t, 2, false)
+
+	if sp, err := c.Span(context.Background(), 1); err != nil || sp.EndBlock != 6655 {
+		t.Fatalf("failed to fetch span: %v", err)
+	}
+
+	if have := c.Current(); have != servers[0].URL() {
+		t.Fatalf("current endpoint mismatch: have %s, want %s", have, servers[0].URL())
+	}
+
+	// Fail over to the second endpoint, and stick to it
+	servers[0].Fail("/bor/span/1", http.StatusInternalServerError, -1)
+
+	for i := 0; i < 2; i++ {
+		if sp, err := c.Span(context.Background(), 1); err != nil || sp.EndBlock != 6655 {
+			t.Fatalf("failed to fetch span: %v", err)
+		}
+	}
+
+	if have := c.Current(); have != servers[1].URL() {
+		t.Errorf("current endpoint mismatch: have %s, want %s", have, servers[1].URL())
+	}
+
+	if have := servers[0].Requests("/bor/span/1"); have != 2 {
+		t.Errorf("failing endpoint request count mismatch: have %d, want 2", have)
+	}
+
+	// Answers aren't failed over
+	servers[1].Fail("/milestone/latest", http.StatusServiceUnavailable, 1)
+
+	if _, err := c.FetchMilestone(context.Background()); !errors.Is(err, heimdall.ErrServiceUnavailable) {
+		t.Errorf("error mismatch: have %v, want %v", err, heimdall.ErrServiceUnavailable)
+	}
+
+	if have := servers[0].Requests("/milestone/latest"); have != 0 {
+		t.Errorf("request count mismatch: have %d, want 0", have)
+	}
+
+	// Keep retrying while all the endpoints fail
+	servers[1].Fail("/bor/span/1", http.StatusInternalServerError, -1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := c.Span(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error mismatch: have %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	for _, status := range c.Endpoints() {
+		if status.Healthy {
+			t.Errorf("endpoint %s still healthy after %d failures", status.URL, status.Failures)
+		}
+	}
+}
+
+func TestCrossCheck(t *testing.T) {
+	t.Parallel()
+
+	c, servers := newTestClient(t, 3, true)
+
+	// A lying endpoint is outvoted and ignored
+	servers[0].AddSpan(&span.HeimdallSpan{Span: span.Span{ID: 1, StartBlock: 256, EndBlock: 1000}, ChainID: "15001"})
+
+	sp, err := c.Span(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to fetch span: %v", err)
+	}
+
+	if sp.EndBlock != 6655 {
+		t.Errorf("span end block mismatch: have %d, want 6655", sp.EndBlock)
+	}
+
+	for _, status := range c.Endpoints() {
+		if healthy := status.URL != servers[0].URL(); status.Healthy != healthy {
+			t.Errorf("endpoint %s health mismatch: have %v, want %v", status.URL, status.Healthy, healthy)
+		}
+	}
+
+	if have := c.Current(); have == servers[0].URL() {
+		t.Errorf("still using the lying endpoint")
+	}
+
+	// Milestones are only compared between endpoints at the same height
+	servers[1].AddMilestone(&milestone.Milestone{StartBlock: big.NewInt(0), EndBlock: big.NewInt(15), Hash: common.Hash{0x01}})
+	servers[2].AddMilestone(&milestone.Milestone{StartBlock: big.NewInt(0), EndBlock: big.NewInt(31), Hash: common.Hash{0x02}})
+
+	if _, err := c.FetchMilestone(context.Background()); err != nil {
+		t.Fatalf("failed to fetch milestone: %v", err)
+	}
+
+	// No majority can't be trusted
+	servers[1].AddMilestone(&milestone.Milestone{StartBlock: big.NewInt(16), EndBlock: big.NewInt(31), Hash: common.Hash{0x03}})
+
+	if _, err := c.FetchMilestone(context.Background()); !errors.Is(err, ErrCrossCheckFailed) {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrCrossCheckFailed)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	c, servers := newTestClient(t, 3, false)
+
+	for i, server := range servers {
+		for n := 0; n < 3; n++ {
+			if i > 0 || n == 0 {
+				server.AddCheckpoint(&checkpoint.Checkpoint{StartBlock: big.NewInt(int64(n) * 256), EndBlock: big.NewInt(int64(n+1)*256 - 1)})
+			}
+		}
+	}
+
+	// The endpoints lagging behind are stale
+	c.checkHealth()
+
+	for _, status := range c.Endpoints() {
+		if healthy := status.URL != servers[0].URL(); status.Healthy != healthy {
+			t.Errorf("endpoint %s health mismatch: have %v, want %v", status.URL, status.Healthy, healthy)
+		}
+
+		if status.Latency == 0 {
+			t.Errorf("endpoint %s latency not measured", status.URL)
+		}
+	}
+
+	if have := c.Current(); have == servers[0].URL() {
+		t.Errorf("still using the stale endpoint")
+	}
+
+	// And healthy again once caught up
+	servers[0].AddCheckpoint(&checkpoint.Checkpoint{StartBlock: big.NewInt(256), EndBlock: big.NewInt(511)})
+	c.checkHealth()
+
+	for _, status := range c.Endpoints() {
+		if !status.Healthy {
+			t.Errorf("endpoint %s still unhealthy", status.URL)
+		}
+	}
+}
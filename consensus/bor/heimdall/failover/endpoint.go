@@ -0,0 +1,144 @@
+package failover
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// latencyWeight is the weight of the latest latency in the moving average.
+const latencyWeight = 0.2
+
+// endpoint is the state of a Heimdall endpoint, guarded by the client lock.
+type endpoint struct {
+	url    string
+	client bor.IHeimdallClient
+
+	latency     time.Duration // Moving average of the response times
+	failures    int           // Consecutive failures
+	stale       bool          // Lagging behind the other endpoints
+	faultyUntil time.Time     // Disagreed with the other endpoints until then
+}
+
+// healthy returns whether the endpoint can be relied on.
+func (e *endpoint) healthy() bool {
+	return e.failures < maxFailures && !e.stale && time.Now().After(e.faultyUntil)
+}
+
+// score rates the endpoint out of its latency in milliseconds, multiplied by
+// its consecutive failures. The lower the better.
+func (e *endpoint) score() float64 {
+	return (float64(e.latency)/float64(time.Millisecond) + 1) * float64(1+e.failures)
+}
+
+func (e *endpoint) succeeded(latency time.Duration) {
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(e.latency))
+	}
+
+	e.failures = 0
+}
+
+// responseKey returns the key identifying equal responses.
+func responseKey(res interface{}) string {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(data)
+}
+
+// healthLoop checks the endpoints until the client is closed.
+func (c *Client) healthLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkHealth()
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// checkHealth fetches the checkpoint count from all the endpoints, to measure
+// their latency and find those lagging behind, and switches to the best
+// endpoint if the current one is unhealthy or much slower.
+func (c *Client) checkHealth() {
+	var (
+		counts = make([]int64, len(c.endpoints))
+		errs   = make([]error, len(c.endpoints))
+		wg     sync.WaitGroup
+	)
+
+	for i, e := range c.endpoints {
+		wg.Add(1)
+
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			counts[i], errs[i] = e.client.FetchCheckpointCount(ctx)
+
+			if isAnswer(errs[i]) {
+				c.succeeded(e, time.Since(start))
+			} else {
+				c.failed(e)
+				log.Debug("Heimdall endpoint health check failed", "endpoint", e.url, "err", errs[i])
+			}
+		}(i, e)
+	}
+
+	wg.Wait()
+
+	var highest int64
+
+	for i, count := range counts {
+		if errs[i] == nil && count > highest {
+			highest = count
+		}
+	}
+
+	c.mu.Lock()
+
+	for i, e := range c.endpoints {
+		if errs[i] != nil {
+			continue
+		}
+
+		if stale := counts[i]+maxCheckpointLag < highest; stale != e.stale {
+			log.Warn("Heimdall endpoint staleness changed", "endpoint", e.url, "stale", stale, "checkpoints", counts[i], "highest", highest)
+			e.stale = stale
+		}
+	}
+
+	var best *endpoint
+
+	for _, e := range c.endpoints {
+		if e.healthy() && (best == nil || e.score() < best.score()) {
+			best = e
+		}
+	}
+
+	switchable := best != nil && best != c.current && (!c.current.healthy() || best.score()*switchFactor < c.current.score())
+
+	c.mu.Unlock()
+
+	if switchable {
+		c.use(best)
+	}
+}
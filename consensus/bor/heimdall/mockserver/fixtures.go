@@ -0,0 +1,167 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// fixture is a recorded Heimdall response.
+type fixture struct {
+	URI    string          `json:"uri"` // Path and query of the request
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// serve replays the response.
+func (f *fixture) serve(w http.ResponseWriter) {
+	if len(f.Body) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	w.WriteHeader(f.Status)
+
+	if _, err := w.Write(f.Body); err != nil {
+		log.Warn("Failed to write mock Heimdall fixture", "uri", f.URI, "err", err)
+	}
+}
+
+// fixtureFile returns the name of the file the response to the request with
+// the given URI is recorded to.
+func fixtureFile(uri string) string {
+	return strings.TrimPrefix(url.PathEscape(uri), "%2F") + ".json"
+}
+
+// recorder forwards requests to a real Heimdall and saves the responses.
+type recorder struct {
+	upstream *url.URL
+	dir      string
+	client   http.Client
+}
+
+// Record switches the server to record mode: the requests it has no fixture
+// for are forwarded to the Heimdall at upstream, and the successful responses
+// are saved to dir, to be loaded later with LoadFixtures.
+func (s *Server) Record(upstream string, dir string) error {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recorder = &recorder{upstream: u, dir: dir}
+
+	return nil
+}
+
+// LoadFixtures loads the responses recorded to dir, which are then replayed
+// for the requests with the same path and query.
+func (s *Server) LoadFixtures(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	fixtures := make([]*fixture, 0, len(files))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		fix := new(fixture)
+		if err := json.Unmarshal(data, fix); err != nil {
+			return fmt.Errorf("invalid fixture %s: %w", file, err)
+		}
+
+		fixtures = append(fixtures, fix)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, fix := range fixtures {
+		s.fixtures[fix.URI] = fix
+	}
+
+	return nil
+}
+
+// serve forwards the request upstream, and saves the response if successful
+// before writing it back.
+func (rec *recorder) serve(w http.ResponseWriter, r *http.Request) {
+	u := *rec.upstream
+	u.Path = strings.TrimSuffix(u.Path, "/") + r.URL.Path
+	u.RawQuery = r.URL.RawQuery
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res, err := rec.client.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	fix := &fixture{URI: r.URL.RequestURI(), Status: res.StatusCode}
+
+	// Compact the body, so that the fixtures are stable across recordings
+	if len(body) > 0 {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, body); err == nil {
+			fix.Body = buf.Bytes()
+		}
+	}
+
+	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNoContent {
+		if err := rec.save(fix); err != nil {
+			log.Warn("Failed to record Heimdall response", "uri", fix.URI, "err", err)
+		}
+	}
+
+	if len(fix.Body) > 0 {
+		fix.serve(w)
+		return
+	}
+
+	w.WriteHeader(res.StatusCode)
+
+	if _, err := w.Write(body); err != nil {
+		log.Warn("Failed to forward Heimdall response", "uri", fix.URI, "err", err)
+	}
+}
+
+// save writes fix to the fixture directory.
+func (rec *recorder) save(fix *fixture) error {
+	data, err := json.MarshalIndent(fix, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(rec.dir, fixtureFile(fix.URI)), data, 0644)
+}
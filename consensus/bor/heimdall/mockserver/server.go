@@ -0,0 +1,342 @@
+// Package mockserver implements an in-process Heimdall serving the REST
+// endpoints used by heimdall.HeimdallClient, so that tests and devnets can run
+// without a real Heimdall while still going through the real client.
+package mockserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Server is a scriptable Heimdall. The responses are looked up in order among
+// the scripted failures, the loaded fixtures and the scripted state, and the
+// requests which can't be served are forwarded upstream in record mode.
+type Server struct {
+	srv *httptest.Server
+
+	spans        map[uint64]*span.HeimdallSpan
+	events       []*clerk.EventRecordWithTime // Sorted by ID
+	checkpoints  []*checkpoint.Checkpoint     // Checkpoint n at index n-1
+	milestones   []*milestone.Milestone
+	noAcks       map[string]bool
+	lastNoAck    string
+	milestoneIDs map[string]bool
+
+	failures map[string]*failure
+	requests map[string]int
+
+	fixtures map[string]*fixture // Keyed by the request URI
+	recorder *recorder
+
+	mu sync.Mutex
+}
+
+// Prefixes of the endpoints taking a parameter as last path element.
+const (
+	spanPath           = "/bor/span/"
+	checkpointPath     = "/checkpoints/"
+	noAckMilestonePath = "/milestone/noAck/"
+	milestoneIDPath    = "/milestone/ID/"
+)
+
+// failure is a scripted failure of an endpoint.
+type failure struct {
+	status int
+	times  int // Remaining failures, negative to fail forever
+}
+
+// New starts a server with no state scripted.
+func New() *Server {
+	s := &Server{
+		spans:        make(map[uint64]*span.HeimdallSpan),
+		noAcks:       make(map[string]bool),
+		milestoneIDs: make(map[string]bool),
+		failures:     make(map[string]*failure),
+		requests:     make(map[string]int),
+		fixtures:     make(map[string]*fixture),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(spanPath, s.handleSpan)
+	mux.HandleFunc("/clerk/event-record/list", s.handleStateSyncEvents)
+	mux.HandleFunc("/checkpoints/count", s.handleCheckpointCount)
+	mux.HandleFunc(checkpointPath, s.handleCheckpoint)
+	mux.HandleFunc("/milestone/latest", s.handleMilestone)
+	mux.HandleFunc("/milestone/count", s.handleMilestoneCount)
+	mux.HandleFunc("/milestone/lastNoAck", s.handleLastNoAckMilestone)
+	mux.HandleFunc(noAckMilestonePath, s.handleNoAckMilestone)
+	mux.HandleFunc(milestoneIDPath, s.handleMilestoneID)
+
+	s.srv = httptest.NewServer(s.intercept(mux))
+
+	return s
+}
+
+// URL returns the URL to configure heimdall.HeimdallClient with.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// AddSpan scripts a span.
+func (s *Server) AddSpan(sp *span.HeimdallSpan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spans[sp.ID] = sp
+}
+
+// AddStateSyncEvents scripts state sync events.
+func (s *Server) AddStateSyncEvents(events ...*clerk.EventRecordWithTime) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, events...)
+
+	sort.SliceStable(s.events, func(i, j int) bool {
+		return s.events[i].ID < s.events[j].ID
+	})
+}
+
+// AddCheckpoint scripts the next checkpoint, which becomes the latest one.
+func (s *Server) AddCheckpoint(cp *checkpoint.Checkpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints = append(s.checkpoints, cp)
+}
+
+// AddMilestone scripts the next milestone, which becomes the latest one.
+func (s *Server) AddMilestone(m *milestone.Milestone) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.milestones = append(s.milestones, m)
+}
+
+// SetNoAckMilestone scripts whether the milestone with the given ID was
+// rejected, which makes it the last rejected one if so.
+func (s *Server) SetNoAckMilestone(id string, noAck bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.noAcks[id] = noAck
+
+	if noAck {
+		s.lastNoAck = id
+	}
+}
+
+// SetMilestoneID scripts whether the milestone with the given ID is in process.
+func (s *Server) SetMilestoneID(id string, inProcess bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.milestoneIDs[id] = inProcess
+}
+
+// Fail makes the requests to the given path, like "/bor/span/1", fail with the
+// given status the next times times, or forever if times is negative.
+func (s *Server) Fail(path string, status int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[path] = &failure{status: status, times: times}
+}
+
+// Requests returns the number of requests received for the given path.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[path]
+}
+
+// intercept serves the scripted failures and the fixtures, and records the
+// responses of upstream in record mode, before falling back to next.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+
+		s.requests[r.URL.Path]++
+
+		if f, ok := s.failures[r.URL.Path]; ok && f.times != 0 {
+			if f.times > 0 {
+				f.times--
+			}
+
+			s.mu.Unlock()
+			w.WriteHeader(f.status)
+
+			return
+		}
+
+		fix, recorder := s.fixtures[r.URL.RequestURI()], s.recorder
+
+		s.mu.Unlock()
+
+		switch {
+		case fix != nil:
+			fix.serve(w)
+		case recorder != nil:
+			recorder.serve(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// respond writes result wrapped the way Heimdall does.
+func respond(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(struct {
+		Height string      `json:"height"`
+		Result interface{} `json:"result"`
+	}{"0", result})
+	if err != nil {
+		log.Warn("Failed to write mock Heimdall response", "err", err)
+	}
+}
+
+func (s *Server) handleSpan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, spanPath), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	sp, ok := s.spans[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	respond(w, sp)
+}
+
+func (s *Server) handleStateSyncEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		query = r.URL.Query()
+		from  uint64
+		to    int64
+		limit int
+		err   error
+	)
+
+	if from, err = strconv.ParseUint(query.Get("from-id"), 10, 64); err == nil {
+		if to, err = strconv.ParseInt(query.Get("to-time"), 10, 64); err == nil {
+			limit, err = strconv.Atoi(query.Get("limit"))
+		}
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]*clerk.EventRecordWithTime, 0, limit)
+
+	for _, event := range s.events {
+		if len(events) == limit {
+			break
+		}
+
+		if event.ID >= from && event.Time.Before(time.Unix(to, 0)) {
+			events = append(events, event)
+		}
+	}
+
+	respond(w, events)
+}
+
+func (s *Server) handleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	number := len(s.checkpoints)
+
+	if param := strings.TrimPrefix(r.URL.Path, checkpointPath); param != "latest" {
+		var err error
+		if number, err = strconv.Atoi(param); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if number < 1 || number > len(s.checkpoints) {
+		http.NotFound(w, r)
+		return
+	}
+
+	respond(w, s.checkpoints[number-1])
+}
+
+func (s *Server) handleCheckpointCount(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	respond(w, checkpoint.CheckpointCount{Result: int64(len(s.checkpoints))})
+}
+
+func (s *Server) handleMilestone(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.milestones) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	respond(w, s.milestones[len(s.milestones)-1])
+}
+
+func (s *Server) handleMilestoneCount(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	respond(w, milestone.MilestoneCount{Count: int64(len(s.milestones))})
+}
+
+func (s *Server) handleLastNoAckMilestone(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	respond(w, milestone.MilestoneLastNoAck{Result: s.lastNoAck})
+}
+
+func (s *Server) handleNoAckMilestone(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	respond(w, milestone.MilestoneNoAck{Result: s.noAcks[strings.TrimPrefix(r.URL.Path, noAckMilestonePath)]})
+}
+
+func (s *Server) handleMilestoneID(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	respond(w, milestone.MilestoneID{Result: s.milestoneIDs[strings.TrimPrefix(r.URL.Path, milestoneIDPath)]})
+}
@@ -0,0 +1,199 @@
+package mockserver
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+)
+
+// newScriptedServer creates a server scripted with a span, 60 state sync
+// events one second apart, two checkpoints and a milestone.
+func newScriptedServer(t *testing.T) *Server {
+	t.Helper()
+
+	s := New()
+	t.Cleanup(s.Close)
+
+	validator := &valset.Validator{ID: 1, Address: common.Address{0x01}, VotingPower: 10}
+
+	s.AddSpan(&span.HeimdallSpan{
+		Span:              span.Span{ID: 1, StartBlock: 256, EndBlock: 6655},
+		ValidatorSet:      valset.ValidatorSet{Validators: []*valset.Validator{validator}, Proposer: validator},
+		SelectedProducers: []valset.Validator{*validator},
+		ChainID:           "15001",
+	})
+
+	for i := 1; i <= 60; i++ {
+		s.AddStateSyncEvents(&clerk.EventRecordWithTime{
+			EventRecord: clerk.EventRecord{ID: uint64(i), Contract: common.Address{0x02}, Data: []byte{byte(i)}, ChainID: "15001"},
+			Time:        time.Unix(int64(i), 0).UTC(),
+		})
+	}
+
+	s.AddCheckpoint(&checkpoint.Checkpoint{StartBlock: big.NewInt(0), EndBlock: big.NewInt(255), RootHash: common.Hash{0x03}, BorChainID: "15001"})
+	s.AddCheckpoint(&checkpoint.Checkpoint{StartBlock: big.NewInt(256), EndBlock: big.NewInt(511), RootHash: common.Hash{0x04}, BorChainID: "15001"})
+	s.AddMilestone(&milestone.Milestone{StartBlock: big.NewInt(0), EndBlock: big.NewInt(15), Hash: common.Hash{0x05}, BorChainID: "15001"})
+	s.SetNoAckMilestone("rejected", true)
+	s.SetMilestoneID("pending", true)
+
+	return s
+}
+
+// checkServer fetches the state scripted by newScriptedServer through the real
+// client connected to url.
+func checkServer(t *testing.T, url string) {
+	t.Helper()
+
+	var (
+		ctx    = context.Background()
+		client = heimdall.NewHeimdallClient(url)
+	)
+	defer client.Close()
+
+	sp, err := client.Span(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to fetch span: %v", err)
+	}
+
+	if sp.ID != 1 || sp.EndBlock != 6655 || len(sp.SelectedProducers) != 1 || sp.ValidatorSet.Proposer.Address != (common.Address{0x01}) {
+		t.Errorf("span mismatch: %+v", sp)
+	}
+
+	// More events than a page, up to the given time
+	events, err := client.StateSyncEvents(ctx, 1, 56)
+	if err != nil {
+		t.Fatalf("failed to fetch state sync events: %v", err)
+	}
+
+	if len(events) != 55 || events[0].ID != 1 || events[54].ID != 55 || events[54].Data[0] != 55 {
+		t.Errorf("state sync events mismatch: have %d events", len(events))
+	}
+
+	cp, err := client.FetchCheckpoint(ctx, -1)
+	if err != nil || cp.EndBlock.Uint64() != 511 {
+		t.Errorf("latest checkpoint mismatch: have %+v (err %v)", cp, err)
+	}
+
+	if cp, err := client.FetchCheckpoint(ctx, 1); err != nil || cp.RootHash != (common.Hash{0x03}) {
+		t.Errorf("checkpoint mismatch: have %+v (err %v)", cp, err)
+	}
+
+	if count, err := client.FetchCheckpointCount(ctx); err != nil || count != 2 {
+		t.Errorf("checkpoint count mismatch: have %d (err %v), want 2", count, err)
+	}
+
+	if m, err := client.FetchMilestone(ctx); err != nil || m.Hash != (common.Hash{0x05}) {
+		t.Errorf("milestone mismatch: have %+v (err %v)", m, err)
+	}
+
+	if count, err := client.FetchMilestoneCount(ctx); err != nil || count != 1 {
+		t.Errorf("milestone count mismatch: have %d (err %v), want 1", count, err)
+	}
+
+	if id, err := client.FetchLastNoAckMilestone(ctx); err != nil || id != "rejected" {
+		t.Errorf("last no-ack milestone mismatch: have %q (err %v)", id, err)
+	}
+
+	if err := client.FetchNoAckMilestone(ctx, "rejected"); err != nil {
+		t.Errorf("rejected milestone not reported: %v", err)
+	}
+
+	if err := client.FetchNoAckMilestone(ctx, "accepted"); !errors.Is(err, heimdall.ErrNotInRejectedList) {
+		t.Errorf("accepted milestone error mismatch: have %v, want %v", err, heimdall.ErrNotInRejectedList)
+	}
+
+	if err := client.FetchMilestoneID(ctx, "pending"); err != nil {
+		t.Errorf("pending milestone not reported: %v", err)
+	}
+
+	if err := client.FetchMilestoneID(ctx, "unknown"); !errors.Is(err, heimdall.ErrNotInMilestoneList) {
+		t.Errorf("unknown milestone error mismatch: have %v, want %v", err, heimdall.ErrNotInMilestoneList)
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	s := newScriptedServer(t)
+	checkServer(t, s.URL())
+
+	if have := s.Requests("/clerk/event-record/list"); have != 2 {
+		t.Errorf("state sync request count mismatch: have %d, want 2", have)
+	}
+}
+
+func TestServerFailures(t *testing.T) {
+	t.Parallel()
+
+	s := newScriptedServer(t)
+
+	client := heimdall.NewHeimdallClient(s.URL())
+	defer client.Close()
+
+	// Unavailable endpoints aren't retried
+	s.Fail("/milestone/latest", http.StatusServiceUnavailable, 1)
+
+	if _, err := client.FetchMilestone(context.Background()); !errors.Is(err, heimdall.ErrServiceUnavailable) {
+		t.Fatalf("error mismatch: have %v, want %v", err, heimdall.ErrServiceUnavailable)
+	}
+
+	if _, err := client.FetchMilestone(context.Background()); err != nil {
+		t.Fatalf("failed to fetch milestone after the failure: %v", err)
+	}
+
+	// Other failures are retried until the context is done
+	s.Fail("/bor/span/1", http.StatusInternalServerError, -1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Span(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error mismatch: have %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if have := s.Requests("/bor/span/1"); have != 1 {
+		t.Errorf("span request count mismatch: have %d, want 1", have)
+	}
+}
+
+func TestServerRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		upstream = newScriptedServer(t)
+		dir      = t.TempDir()
+	)
+
+	// Record the responses of the scripted server through a proxying one
+	recording := New()
+	defer recording.Close()
+
+	if err := recording.Record(upstream.URL(), dir); err != nil {
+		t.Fatalf("failed to start recording: %v", err)
+	}
+
+	checkServer(t, recording.URL())
+
+	// Replay them without the upstream server
+	upstream.Close()
+
+	replay := New()
+	defer replay.Close()
+
+	if err := replay.LoadFixtures(dir); err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+
+	checkServer(t, replay.URL())
+}
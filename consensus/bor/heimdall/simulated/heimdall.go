@@ -0,0 +1,334 @@
+// Package simulated implements an in-process Heimdall for developer chains. It
+// generates the spans out of a configured validator set, emits scripted state
+// sync events, and produces the milestones and checkpoints out of the local
+// chain, so that bor consensus runs on a single machine.
+package simulated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrNotProduced is returned when fetching a milestone or checkpoint which
+	// the local chain isn't long enough for yet.
+	ErrNotProduced = errors.New("not produced yet")
+
+	// errNoChain is returned when fetching a milestone or checkpoint before the
+	// local chain is set.
+	errNoChain = errors.New("no chain to produce from")
+)
+
+// Defaults of the config.
+const (
+	DefaultFirstSpanEnd     = 255
+	DefaultSpanLength       = 256
+	DefaultMilestoneLength  = 16
+	DefaultCheckpointLength = 256
+	DefaultConfirmations    = 16
+)
+
+// Config is the scripted behaviour of the simulated Heimdall.
+type Config struct {
+	ChainID      string
+	Validators   []*valset.Validator // Validator set of the spans
+	FirstSpanEnd uint64              // Last block of the first span, hardcoded in the genesis validator set contract
+	SpanLength   uint64              // Blocks of the spans after the first one
+
+	StateSyncs []*clerk.EventRecordWithTime `toml:",omitempty"` // Sorted by ID
+
+	MilestoneLength  uint64 // Blocks of the milestones
+	CheckpointLength uint64 // Blocks of the checkpoints
+	Confirmations    uint64 // Blocks the milestones and checkpoints lag behind the head
+}
+
+// Chain is the local chain the milestones and checkpoints are made of, which is
+// implemented by the eth API backend.
+type Chain interface {
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
+	GetRootHash(ctx context.Context, start uint64, end uint64) (string, error)
+}
+
+// Heimdall is an IHeimdallClient simulating Heimdall.
+type Heimdall struct {
+	config Config
+	chain  Chain
+
+	mu sync.RWMutex
+}
+
+// New creates a simulated Heimdall. The milestones and checkpoints are only
+// produced once the local chain is set.
+func New(config Config) *Heimdall {
+	if config.FirstSpanEnd == 0 {
+		config.FirstSpanEnd = DefaultFirstSpanEnd
+	}
+
+	if config.SpanLength == 0 {
+		config.SpanLength = DefaultSpanLength
+	}
+
+	if config.MilestoneLength == 0 {
+		config.MilestoneLength = DefaultMilestoneLength
+	}
+
+	if config.CheckpointLength == 0 {
+		config.CheckpointLength = DefaultCheckpointLength
+	}
+
+	return &Heimdall{config: config}
+}
+
+// SetChain sets the local chain to produce the milestones and checkpoints of.
+func (h *Heimdall) SetChain(chain Chain) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.chain = chain
+}
+
+// LoadStateSyncs reads the state sync events from a JSON file, in the format
+// Heimdall serves them. The events are numbered in order if they have no ID,
+// and get chainID if they have no chain ID. The events without a time can be
+// committed right away.
+func LoadStateSyncs(path string, chainID string) ([]*clerk.EventRecordWithTime, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*clerk.EventRecordWithTime
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("invalid state sync events %s: %w", path, err)
+	}
+
+	for i, event := range events {
+		if event.ID == 0 {
+			event.ID = uint64(i + 1)
+		}
+
+		if event.ChainID == "" {
+			event.ChainID = chainID
+		}
+
+		if i > 0 && event.ID != events[i-1].ID+1 {
+			return nil, fmt.Errorf("state sync event %d out of sequence after %d", event.ID, events[i-1].ID)
+		}
+	}
+
+	return events, nil
+}
+
+// StateSyncEvents returns the scripted events from fromID, up to the ones
+// recorded at to.
+func (h *Heimdall) StateSyncEvents(_ context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	events := make([]*clerk.EventRecordWithTime, 0)
+
+	for _, event := range h.config.StateSyncs {
+		if event.ID >= fromID && event.Time.Before(time.Unix(to, 0)) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// Span returns the span with the given ID, all the spans having the configured
+// validators as validator set and producers.
+func (h *Heimdall) Span(_ context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	sp := span.Span{ID: spanID, StartBlock: 0, EndBlock: h.config.FirstSpanEnd}
+
+	if spanID > 0 {
+		sp.StartBlock = h.config.FirstSpanEnd + 1 + (spanID-1)*h.config.SpanLength
+		sp.EndBlock = sp.StartBlock + h.config.SpanLength - 1
+	}
+
+	validators := make([]*valset.Validator, len(h.config.Validators))
+	producers := make([]valset.Validator, len(h.config.Validators))
+
+	for i, validator := range h.config.Validators {
+		validators[i] = validator.Copy()
+		producers[i] = *validator.Copy()
+	}
+
+	return &span.HeimdallSpan{
+		Span:              sp,
+		ValidatorSet:      *valset.NewValidatorSet(validators),
+		SelectedProducers: producers,
+		ChainID:           h.config.ChainID,
+	}, nil
+}
+
+// FetchCheckpoint returns the checkpoint with the given number, or the latest
+// one if -1.
+func (h *Heimdall) FetchCheckpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	count, err := h.FetchCheckpointCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if number == -1 {
+		number = count
+	}
+
+	if number < 1 || number > count {
+		return nil, fmt.Errorf("%w: checkpoint %d", ErrNotProduced, number)
+	}
+
+	start := uint64(number-1) * h.config.CheckpointLength
+	end := start + h.config.CheckpointLength - 1
+
+	header, err := h.header(ctx, rpc.BlockNumber(end))
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := h.getChain()
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := chain.GetRootHash(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkpoint.Checkpoint{
+		Proposer:   h.proposer(),
+		StartBlock: new(big.Int).SetUint64(start),
+		EndBlock:   new(big.Int).SetUint64(end),
+		RootHash:   common.HexToHash(root),
+		BorChainID: h.config.ChainID,
+		Timestamp:  header.Time,
+	}, nil
+}
+
+// FetchCheckpointCount returns the number of checkpoints the confirmed part of
+// the local chain is long enough for.
+func (h *Heimdall) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	return h.count(ctx, h.config.CheckpointLength)
+}
+
+// FetchMilestone returns the latest milestone.
+func (h *Heimdall) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	count, err := h.FetchMilestoneCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, fmt.Errorf("%w: milestone", ErrNotProduced)
+	}
+
+	start := uint64(count-1) * h.config.MilestoneLength
+	end := start + h.config.MilestoneLength - 1
+
+	header, err := h.header(ctx, rpc.BlockNumber(end))
+	if err != nil {
+		return nil, err
+	}
+
+	return &milestone.Milestone{
+		Proposer:   h.proposer(),
+		StartBlock: new(big.Int).SetUint64(start),
+		EndBlock:   new(big.Int).SetUint64(end),
+		Hash:       header.Hash(),
+		BorChainID: h.config.ChainID,
+		Timestamp:  header.Time,
+	}, nil
+}
+
+// FetchMilestoneCount returns the number of milestones the confirmed part of
+// the local chain is long enough for.
+func (h *Heimdall) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	return h.count(ctx, h.config.MilestoneLength)
+}
+
+// FetchNoAckMilestone reports no milestone as rejected.
+func (h *Heimdall) FetchNoAckMilestone(_ context.Context, milestoneID string) error {
+	return fmt.Errorf("%w: milestoneID %q", heimdall.ErrNotInRejectedList, milestoneID)
+}
+
+// FetchLastNoAckMilestone reports no milestone as rejected.
+func (h *Heimdall) FetchLastNoAckMilestone(_ context.Context) (string, error) {
+	return "", nil
+}
+
+// FetchMilestoneID reports no milestone as in process, as the milestones are
+// produced without voting.
+func (h *Heimdall) FetchMilestoneID(_ context.Context, milestoneID string) error {
+	return fmt.Errorf("%w: milestoneID %q", heimdall.ErrNotInMilestoneList, milestoneID)
+}
+
+func (h *Heimdall) Close() {}
+
+// count returns the number of ranges of length blocks in the confirmed part of
+// the local chain.
+func (h *Heimdall) count(ctx context.Context, length uint64) (int64, error) {
+	head, err := h.header(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	if head.Number.Uint64() < h.config.Confirmations {
+		return 0, nil
+	}
+
+	return int64((head.Number.Uint64() - h.config.Confirmations + 1) / length), nil
+}
+
+// getChain returns the local chain, once set.
+func (h *Heimdall) getChain() (Chain, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.chain == nil {
+		return nil, errNoChain
+	}
+
+	return h.chain, nil
+}
+
+// header returns the header of the local chain with the given number.
+func (h *Heimdall) header(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	chain, err := h.getChain()
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := chain.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if header == nil {
+		return nil, fmt.Errorf("%w: block %d", ErrNotProduced, number)
+	}
+
+	return header, nil
+}
+
+// proposer returns the proposer of the milestones and checkpoints.
+func (h *Heimdall) proposer() common.Address {
+	if len(h.config.Validators) == 0 {
+		return common.Address{}
+	}
+
+	return h.config.Validators[0].Address
+}
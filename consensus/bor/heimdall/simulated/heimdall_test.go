@@ -0,0 +1,227 @@
+package simulated
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testChain is a local chain of headers numbered from zero.
+type testChain []*types.Header
+
+func newTestChain(length int) testChain {
+	chain := make(testChain, length)
+
+	for i := range chain {
+		chain[i] = &types.Header{Number: big.NewInt(int64(i)), Time: uint64(i)}
+	}
+
+	return chain
+}
+
+func (c testChain) HeaderByNumber(_ context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		return c[len(c)-1], nil
+	}
+
+	if int(number) >= len(c) {
+		return nil, nil
+	}
+
+	return c[number], nil
+}
+
+func (c testChain) GetRootHash(_ context.Context, start uint64, end uint64) (string, error) {
+	return common.BigToHash(new(big.Int).SetUint64(start<<32 | end)).Hex(), nil
+}
+
+func newTestHeimdall() *Heimdall {
+	return New(Config{
+		ChainID: "1337",
+		Validators: []*valset.Validator{
+			{ID: 1, Address: common.Address{0x01}, VotingPower: 10},
+			{ID: 2, Address: common.Address{0x02}, VotingPower: 20},
+		},
+		FirstSpanEnd:  7,
+		SpanLength:    64,
+		Confirmations: 4,
+	})
+}
+
+func TestSpan(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHeimdall()
+
+	for _, tt := range []struct {
+		id         uint64
+		start, end uint64
+	}{
+		{0, 0, 7},
+		{1, 8, 71},
+		{2, 72, 135},
+	} {
+		sp, err := h.Span(context.Background(), tt.id)
+		if err != nil {
+			t.Fatalf("failed to fetch span %d: %v", tt.id, err)
+		}
+
+		if sp.ID != tt.id || sp.StartBlock != tt.start || sp.EndBlock != tt.end || sp.ChainID != "1337" {
+			t.Errorf("span %d mismatch: have %+v", tt.id, sp.Span)
+		}
+
+		if len(sp.SelectedProducers) != 2 || len(sp.ValidatorSet.Validators) != 2 {
+			t.Errorf("span %d validators mismatch: have %d producers, %d validators", tt.id, len(sp.SelectedProducers), len(sp.ValidatorSet.Validators))
+		}
+	}
+
+	// The spans don't share the validators with the config
+	sp, _ := h.Span(context.Background(), 1)
+	sp.ValidatorSet.Validators[0].VotingPower = 0
+
+	if h.config.Validators[0].VotingPower != 10 || h.config.Validators[1].VotingPower != 20 {
+		t.Errorf("configured validators modified by the span")
+	}
+}
+
+func TestStateSyncs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "statesyncs.json")
+
+	data := `[
+		{"contract": "0x0000000000000000000000000000000000001234", "data": "0x01"},
+		{"contract": "0x0000000000000000000000000000000000001234", "data": "0x02", "record_time": "1970-01-01T00:01:40Z"}
+	]`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := LoadStateSyncs(path, "1337")
+	if err != nil {
+		t.Fatalf("failed to load state syncs: %v", err)
+	}
+
+	if len(events) != 2 || events[0].ID != 1 || events[1].ID != 2 || events[1].ChainID != "1337" {
+		t.Fatalf("state syncs mismatch: have %+v", events)
+	}
+
+	h := New(Config{ChainID: "1337", StateSyncs: events})
+
+	for _, tt := range []struct {
+		fromID uint64
+		to     int64
+		want   int
+	}{
+		{1, 50, 1},
+		{1, 101, 2},
+		{2, 50, 0},
+		{2, 101, 1},
+		{3, 101, 0},
+	} {
+		events, err := h.StateSyncEvents(context.Background(), tt.fromID, tt.to)
+		if err != nil {
+			t.Fatalf("failed to fetch state syncs: %v", err)
+		}
+
+		if len(events) != tt.want {
+			t.Errorf("state syncs from %d to %d mismatch: have %d, want %d", tt.fromID, tt.to, len(events), tt.want)
+		}
+	}
+
+	// Events out of sequence are rejected
+	data = `[{"id": 1, "data": "0x01"}, {"id": 3, "data": "0x03"}]`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadStateSyncs(path, "1337"); err == nil {
+		t.Errorf("state syncs out of sequence accepted")
+	}
+}
+
+func TestMilestonesAndCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		h     = newTestHeimdall()
+		chain = newTestChain(300)
+	)
+
+	h.config.MilestoneLength = 16
+	h.config.CheckpointLength = 64
+
+	// Nothing is produced without the local chain
+	if _, err := h.FetchMilestoneCount(ctx); !errors.Is(err, errNoChain) {
+		t.Fatalf("error mismatch: have %v, want %v", err, errNoChain)
+	}
+
+	h.SetChain(chain)
+
+	// 296 blocks are confirmed out of 300
+	if count, err := h.FetchMilestoneCount(ctx); err != nil || count != 18 {
+		t.Errorf("milestone count mismatch: have %d (err %v), want 18", count, err)
+	}
+
+	m, err := h.FetchMilestone(ctx)
+	if err != nil {
+		t.Fatalf("failed to fetch milestone: %v", err)
+	}
+
+	if m.StartBlock.Uint64() != 272 || m.EndBlock.Uint64() != 287 || m.Hash != chain[287].Hash() || m.Proposer != (common.Address{0x01}) {
+		t.Errorf("milestone mismatch: have %+v", m)
+	}
+
+	if count, err := h.FetchCheckpointCount(ctx); err != nil || count != 4 {
+		t.Errorf("checkpoint count mismatch: have %d (err %v), want 4", count, err)
+	}
+
+	cp, err := h.FetchCheckpoint(ctx, -1)
+	if err != nil {
+		t.Fatalf("failed to fetch latest checkpoint: %v", err)
+	}
+
+	if cp.StartBlock.Uint64() != 192 || cp.EndBlock.Uint64() != 255 || cp.Timestamp != 255 {
+		t.Errorf("latest checkpoint mismatch: have %+v", cp)
+	}
+
+	cp, err = h.FetchCheckpoint(ctx, 2)
+	if err != nil {
+		t.Fatalf("failed to fetch checkpoint: %v", err)
+	}
+
+	root, _ := chain.GetRootHash(ctx, 64, 127)
+	if cp.RootHash != common.HexToHash(root) {
+		t.Errorf("checkpoint root hash mismatch: have %x, want %s", cp.RootHash, root)
+	}
+
+	if _, err := h.FetchCheckpoint(ctx, 5); !errors.Is(err, ErrNotProduced) {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrNotProduced)
+	}
+
+	// The milestones are neither voted on nor rejected
+	if err := h.FetchMilestoneID(ctx, "id"); !errors.Is(err, heimdall.ErrNotInMilestoneList) {
+		t.Errorf("error mismatch: have %v, want %v", err, heimdall.ErrNotInMilestoneList)
+	}
+
+	if err := h.FetchNoAckMilestone(ctx, "id"); !errors.Is(err, heimdall.ErrNotInRejectedList) {
+		t.Errorf("error mismatch: have %v, want %v", err, heimdall.ErrNotInRejectedList)
+	}
+
+	// Nothing is produced before the confirmations
+	h.SetChain(newTestChain(4))
+
+	if _, err := h.FetchMilestone(ctx); !errors.Is(err, ErrNotProduced) {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrNotProduced)
+	}
+}
@@ -567,7 +567,9 @@ func (bc *BlockChain) ProcessBlock(block *types.Block, parent *types.Header) (ty
 
 	result := <-resultChan
 
-	if _, ok := result.err.(blockstm.ParallelExecFailedError); ok {
+	var badTxDependency *BadTxDependencyError
+
+	if _, ok := result.err.(blockstm.ParallelExecFailedError); ok || errors.As(result.err, &badTxDependency) {
 		log.Warn("Parallel state processor failed", "err", result.err)
 
 		// If the parallel processor failed, we will fallback to the serial processor if enabled
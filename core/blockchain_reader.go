@@ -479,6 +479,36 @@ func (bc *BlockChain) GetStateSync() []*types.StateSyncData {
 	return bc.stateSyncData
 }
 
+// StateSyncCollector wraps the chain to finalize blocks which aren't imported
+// through it, such as built or validated ones: the state sync data they commit
+// is kept aside instead of replacing the one of the chain.
+type StateSyncCollector struct {
+	*BlockChain
+
+	stateData []*types.StateSyncData
+}
+
+// NewStateSyncCollector creates a state sync collector on top of the chain.
+func NewStateSyncCollector(bc *BlockChain) *StateSyncCollector {
+	return &StateSyncCollector{BlockChain: bc}
+}
+
+// Processor returns a state processor finalizing blocks through the collector,
+// which keeps the state syncs they commit aside from the chain.
+func (c *StateSyncCollector) Processor() Processor {
+	return &StateProcessor{config: c.chainConfig, bc: c, engine: c.engine}
+}
+
+// SetStateSync keeps the state sync data aside from the chain.
+func (c *StateSyncCollector) SetStateSync(stateData []*types.StateSyncData) {
+	c.stateData = stateData
+}
+
+// GetStateSync returns the state sync data of the last finalized block.
+func (c *StateSyncCollector) GetStateSync() []*types.StateSyncData {
+	return c.stateData
+}
+
 // SubscribeStateSyncEvent registers a subscription of StateSyncEvent.
 func (bc *BlockChain) SubscribeStateSyncEvent(ch chan<- StateSyncEvent) event.Subscription {
 	return bc.scope.Track(bc.stateSyncFeed.Subscribe(ch))
@@ -40,7 +40,9 @@ func BuildDAG(deps TxnInputOutput) (d DAG) {
 	d = DAG{dag.NewDAG()}
 	ids := make(map[int]string)
 
-	for i := len(deps.inputs) - 1; i > 0; i-- {
+	// The first transaction is a vertex too, even if none depends on it, as the
+	// longest path and the parallelizability are computed over all vertices
+	for i := len(deps.inputs) - 1; i >= 0; i-- {
 		txTo := deps.inputs[i]
 
 		var txToId string
@@ -75,6 +77,25 @@ func BuildDAG(deps TxnInputOutput) (d DAG) {
 	return
 }
 
+// NewDAG returns the DAG of the given dependencies of each transaction on
+// earlier ones, as recorded in block extra data.
+func NewDAG(deps [][]uint64) DAG {
+	d := DAG{dag.NewDAG()}
+	ids := make([]string, len(deps))
+
+	for i := range deps {
+		ids[i], _ = d.AddVertex(i)
+
+		for _, dep := range deps[i] {
+			if err := d.AddEdge(ids[dep], ids[i]); err != nil {
+				log.Warn("Failed to add edge", "from", ids[dep], "to", ids[i], "err", err)
+			}
+		}
+	}
+
+	return d
+}
+
 func depsHelper(dependencies map[int]map[int]bool, txFrom TxnOutput, txTo TxnInput, i int, j int) map[int]map[int]bool {
 	if HasReadDep(txFrom, txTo) {
 		dependencies[i][j] = true
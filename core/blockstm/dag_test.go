@@ -0,0 +1,41 @@
+package blockstm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBuildDAG(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = NewAddressKey(common.Address{0x0a})
+		b = NewAddressKey(common.Address{0x0b})
+	)
+
+	// No transaction reads what tx 0 writes, tx 2 reads b written by tx 1
+	txio := MakeTxnInputOutput(3)
+	txio.recordAllWrite(0, []WriteDescriptor{{Path: a}})
+	txio.recordAllWrite(1, []WriteDescriptor{{Path: b}})
+	txio.recordRead(2, []ReadDescriptor{{Path: b}})
+
+	stats := map[int]ExecutionStat{
+		0: {TxIdx: 0, Start: 0, End: 10},
+		1: {TxIdx: 1, Start: 0, End: 10},
+		2: {TxIdx: 2, Start: 10, End: 20},
+	}
+
+	// Every transaction is a vertex, even the first one without dependents,
+	// or the transactions after it are left out of the longest path
+	dag := BuildDAG(*txio)
+	if have := len(dag.GetVertices()); have != 3 {
+		t.Fatalf("vertices mismatch: have %d, want 3", have)
+	}
+
+	path, weight := dag.LongestPath(stats)
+	if want := []int{1, 2}; !reflect.DeepEqual(path, want) || weight != 20 {
+		t.Errorf("longest path mismatch: have %v (weight %d), want %v (weight 20)", path, weight, want)
+	}
+}
@@ -164,6 +164,10 @@ type ParallelExecutionResult struct {
 	Stats   *map[int]ExecutionStat
 	Deps    *DAG
 	AllDeps map[int]map[int]bool
+
+	// Number of incarnations and aborts of each transaction, only set when profiling
+	Incarnations []int
+	Aborts       []int
 }
 
 const numGoProcs = 1
@@ -562,12 +566,21 @@ func (pe *ParallelExecutor) Step(res *ExecResult) (result ParallelExecutionResul
 
 		var deps DAG
 
+		var incarnations, aborts []int
+
 		if pe.profile {
 			allDeps = GetDep(*pe.lastTxIO)
 			deps = BuildDAG(*pe.lastTxIO)
+
+			incarnations = make([]int, len(pe.txIncarnations))
+			for i, incarnation := range pe.txIncarnations {
+				incarnations[i] = incarnation + 1
+			}
+
+			aborts = pe.diagExecAbort
 		}
 
-		return ParallelExecutionResult{pe.lastTxIO, &pe.stats, &deps, allDeps}, err
+		return ParallelExecutionResult{pe.lastTxIO, &pe.stats, &deps, allDeps, incarnations, aborts}, err
 	}
 
 	// Send the next immediate pending transaction to be executed
@@ -603,7 +616,7 @@ type PropertyCheck func(*ParallelExecutor) error
 
 func executeParallelWithCheck(tasks []ExecTask, profile bool, check PropertyCheck, metadata bool, numProcs int, interruptCtx context.Context) (result ParallelExecutionResult, err error) {
 	if len(tasks) == 0 {
-		return ParallelExecutionResult{MakeTxnInputOutput(len(tasks)), nil, nil, nil}, nil
+		return ParallelExecutionResult{MakeTxnInputOutput(len(tasks)), nil, nil, nil, nil, nil}, nil
 	}
 
 	pe := NewParallelExecutor(tasks, profile, metadata, numProcs)
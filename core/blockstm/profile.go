@@ -0,0 +1,144 @@
+package blockstm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TxProfile is the profiled execution of a transaction. Its start and end are
+// the ones of its last incarnation, relative to the start of the execution.
+type TxProfile struct {
+	Index        int           `json:"index"`
+	Incarnations int           `json:"incarnations"`
+	Aborts       int           `json:"aborts"`
+	Worker       int           `json:"worker"`
+	Start        time.Duration `json:"start"`
+	End          time.Duration `json:"end"`
+
+	// Earlier transactions it reads the writes of, without those it depends on
+	// through other transactions
+	Dependencies []int `json:"dependencies"`
+}
+
+// ExecutionProfile is the profile of the parallel execution of a block.
+type ExecutionProfile struct {
+	Txs []TxProfile `json:"txs"`
+
+	// Longest chain of dependent transactions, and its execution time
+	LongestPath     []int         `json:"longestPath"`
+	LongestPathTime time.Duration `json:"longestPathTime"`
+
+	// Execution time of the transactions one after the other, and the one of
+	// the parallel execution
+	SerialTime   time.Duration `json:"serialTime"`
+	ParallelTime time.Duration `json:"parallelTime"`
+
+	// Speedup over the serial execution with unlimited workers, only bounded by
+	// the longest path, and the one achieved
+	TheoreticalSpeedup float64 `json:"theoreticalSpeedup"`
+	ActualSpeedup      float64 `json:"actualSpeedup"`
+}
+
+// NewExecutionProfile returns the profile of a parallel execution run with
+// profiling enabled.
+func NewExecutionProfile(result ParallelExecutionResult) *ExecutionProfile {
+	profile := &ExecutionProfile{}
+
+	if result.Stats == nil || result.Deps == nil {
+		return profile
+	}
+
+	stats := *result.Stats
+
+	profile.Txs = make([]TxProfile, len(result.Incarnations))
+
+	for i := range profile.Txs {
+		stat := stats[i]
+
+		deps := make([]int, 0, len(result.AllDeps[i]))
+		for dep := range result.AllDeps[i] {
+			deps = append(deps, dep)
+		}
+
+		sort.Ints(deps)
+
+		profile.Txs[i] = TxProfile{
+			Index:        i,
+			Incarnations: result.Incarnations[i],
+			Aborts:       result.Aborts[i],
+			Worker:       stat.Worker,
+			Start:        time.Duration(stat.Start),
+			End:          time.Duration(stat.End),
+			Dependencies: deps,
+		}
+
+		profile.SerialTime += time.Duration(stat.End - stat.Start)
+
+		if end := time.Duration(stat.End); end > profile.ParallelTime {
+			profile.ParallelTime = end
+		}
+	}
+
+	if len(profile.Txs) == 0 {
+		return profile
+	}
+
+	path, weight := result.Deps.LongestPath(stats)
+
+	profile.LongestPath, profile.LongestPathTime = path, time.Duration(weight)
+
+	if profile.LongestPathTime > 0 {
+		profile.TheoreticalSpeedup = float64(profile.SerialTime) / float64(profile.LongestPathTime)
+	}
+
+	if profile.ParallelTime > 0 {
+		profile.ActualSpeedup = float64(profile.SerialTime) / float64(profile.ParallelTime)
+	}
+
+	return profile
+}
+
+// Dot returns the dependency graph of the transactions in the Graphviz format,
+// highlighting the longest path.
+func (p *ExecutionProfile) Dot() string {
+	onPath := make(map[int]bool, len(p.LongestPath))
+	pathPrev := make(map[int]int, len(p.LongestPath))
+
+	for i, tx := range p.LongestPath {
+		onPath[tx] = true
+
+		if i > 0 {
+			pathPrev[tx] = p.LongestPath[i-1]
+		}
+	}
+
+	var b strings.Builder
+
+	b.WriteString("digraph execution {\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	for _, tx := range p.Txs {
+		attrs := fmt.Sprintf("label=\"tx %d\\n%v\\n%d incarnations, %d aborts\"", tx.Index, tx.End-tx.Start, tx.Incarnations, tx.Aborts)
+		if onPath[tx.Index] {
+			attrs += ", color=red"
+		}
+
+		fmt.Fprintf(&b, "\t%d [%s];\n", tx.Index, attrs)
+	}
+
+	for _, tx := range p.Txs {
+		for _, dep := range tx.Dependencies {
+			if prev, ok := pathPrev[tx.Index]; ok && prev == dep {
+				fmt.Fprintf(&b, "\t%d -> %d [color=red];\n", dep, tx.Index)
+			} else {
+				fmt.Fprintf(&b, "\t%d -> %d;\n", dep, tx.Index)
+			}
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
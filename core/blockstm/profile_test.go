@@ -0,0 +1,74 @@
+package blockstm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestExecutionProfile(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = NewAddressKey(common.Address{0x0a})
+		b = NewAddressKey(common.Address{0x0b})
+	)
+
+	// Tx 1 reads a written by tx 0, tx 3 reads a and b written by tx 1 and 2
+	txio := MakeTxnInputOutput(4)
+	txio.recordAllWrite(0, []WriteDescriptor{{Path: a}})
+	txio.recordRead(1, []ReadDescriptor{{Path: a}})
+	txio.recordAllWrite(1, []WriteDescriptor{{Path: a}})
+	txio.recordAllWrite(2, []WriteDescriptor{{Path: b}})
+	txio.recordRead(3, []ReadDescriptor{{Path: a}, {Path: b}})
+
+	stats := map[int]ExecutionStat{
+		0: {TxIdx: 0, Start: 0, End: 10},
+		1: {TxIdx: 1, Incarnation: 1, Start: 10, End: 30},
+		2: {TxIdx: 2, Start: 0, End: 5},
+		3: {TxIdx: 3, Start: 30, End: 40},
+	}
+
+	deps := BuildDAG(*txio)
+
+	profile := NewExecutionProfile(ParallelExecutionResult{
+		TxIO:         txio,
+		Stats:        &stats,
+		Deps:         &deps,
+		AllDeps:      GetDep(*txio),
+		Incarnations: []int{1, 2, 1, 1},
+		Aborts:       []int{0, 1, 0, 0},
+	})
+
+	if want := []int{0, 1, 3}; !reflect.DeepEqual(profile.LongestPath, want) {
+		t.Errorf("longest path mismatch: have %v, want %v", profile.LongestPath, want)
+	}
+
+	if profile.LongestPathTime != 40 || profile.SerialTime != 45 || profile.ParallelTime != 40 {
+		t.Errorf("times mismatch: have %v longest path, %v serial, %v parallel", profile.LongestPathTime, profile.SerialTime, profile.ParallelTime)
+	}
+
+	if profile.TheoreticalSpeedup != 45.0/40 || profile.ActualSpeedup != 45.0/40 {
+		t.Errorf("speedups mismatch: have %v theoretical, %v actual", profile.TheoreticalSpeedup, profile.ActualSpeedup)
+	}
+
+	want := TxProfile{Index: 3, Incarnations: 1, Start: 30, End: 40, Dependencies: []int{1, 2}}
+	if have := profile.Txs[3]; !reflect.DeepEqual(have, want) {
+		t.Errorf("tx profile mismatch: have %+v, want %+v", have, want)
+	}
+
+	if have := profile.Txs[1]; have.Incarnations != 2 || have.Aborts != 1 || have.End-have.Start != 20*time.Nanosecond {
+		t.Errorf("tx profile mismatch: have %+v", have)
+	}
+
+	dot := profile.Dot()
+
+	for _, line := range []string{"\t0 -> 1 [color=red];", "\t1 -> 3 [color=red];", "\t2 -> 3;", "\t3 [label=\"tx 3\\n10ns\\n1 incarnations, 0 aborts\", color=red];"} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("dependency graph misses %q:\n%s", line, dot)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+// Package bundlepool implements a pool of transaction bundles submitted by
+// searchers, which the miner includes atomically in the block they target.
+package bundlepool
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// ErrEmptyBundle is returned if a bundle contains no transactions.
+	ErrEmptyBundle = errors.New("bundle contains no transactions")
+
+	// ErrBundleTooLarge is returned if a bundle contains more transactions than
+	// allowed by the pool configuration.
+	ErrBundleTooLarge = errors.New("bundle too large")
+
+	// ErrStaleBundle is returned if a bundle targets a block which has already
+	// been mined.
+	ErrStaleBundle = errors.New("bundle targets a past block")
+
+	// ErrFutureBundle is returned if a bundle targets a block too far ahead of
+	// the current head.
+	ErrFutureBundle = errors.New("bundle targets a block too far in the future")
+
+	// ErrInvalidTimestamps is returned if the minimum timestamp of a bundle is
+	// above its maximum one.
+	ErrInvalidTimestamps = errors.New("bundle min timestamp above max timestamp")
+
+	// ErrAlreadyKnown is returned if the bundle is already in the pool.
+	ErrAlreadyKnown = errors.New("bundle already known")
+
+	// ErrPoolFull is returned if the pool holds the maximum number of bundles.
+	ErrPoolFull = errors.New("bundle pool full")
+
+	// ErrInvalidSender is returned if the sender of a bundle transaction can't
+	// be recovered from its signature.
+	ErrInvalidSender = errors.New("invalid bundle transaction sender")
+
+	// ErrSenderLimit is returned if a sender of a bundle transaction already has
+	// transactions in the maximum number of bundles.
+	ErrSenderLimit = errors.New("too many bundles from sender")
+)
+
+var (
+	bundleGauge   = metrics.NewRegisteredGauge("bundlepool/bundles", nil)
+	addedMeter    = metrics.NewRegisteredMeter("bundlepool/added", nil)
+	rejectedMeter = metrics.NewRegisteredMeter("bundlepool/rejected", nil)
+)
+
+// Bundle is an ordered list of transactions which must be included together,
+// in order and at the top of each other, in the target block or not at all.
+type Bundle struct {
+	Txs               types.Transactions
+	BlockNumber       uint64        // Block the bundle must be included in
+	MinTimestamp      uint64        // Minimum block timestamp, 0 if unbounded
+	MaxTimestamp      uint64        // Maximum block timestamp, 0 if unbounded
+	RevertingTxHashes []common.Hash // Transactions allowed to revert without invalidating the bundle
+
+	hash    common.Hash
+	senders []common.Address // Distinct senders of the transactions, recovered when added to the pool
+}
+
+// Hash returns the bundle hash, the keccak256 of its transaction hashes.
+func (b *Bundle) Hash() common.Hash {
+	if b.hash == (common.Hash{}) {
+		hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+		for _, tx := range b.Txs {
+			hashes = append(hashes, tx.Hash().Bytes()...)
+		}
+
+		b.hash = crypto.Keccak256Hash(hashes)
+	}
+
+	return b.hash
+}
+
+// CanRevert reports whether the transaction with the given hash may revert
+// without invalidating the bundle.
+func (b *Bundle) CanRevert(hash common.Hash) bool {
+	for _, h := range b.RevertingTxHashes {
+		if h == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches reports whether the bundle may be included in a block with the given
+// number and timestamp.
+func (b *Bundle) Matches(number, timestamp uint64) bool {
+	if b.BlockNumber != number {
+		return false
+	}
+
+	if b.MinTimestamp != 0 && timestamp < b.MinTimestamp {
+		return false
+	}
+
+	if b.MaxTimestamp != 0 && timestamp > b.MaxTimestamp {
+		return false
+	}
+
+	return true
+}
+
+// Config are the configuration parameters of the bundle pool.
+type Config struct {
+	MaxBundles       int    // Maximum number of bundles kept in the pool
+	MaxBundleTxs     int    // Maximum number of transactions in a single bundle
+	MaxBlocksAhead   uint64 // Maximum distance between the head and the target block of a bundle
+	MaxSenderBundles int    // Maximum number of bundles holding transactions of a single sender
+}
+
+// DefaultConfig contains the default configurations for the bundle pool.
+var DefaultConfig = Config{
+	MaxBundles:       1024,
+	MaxBundleTxs:     64,
+	MaxBlocksAhead:   128,
+	MaxSenderBundles: 16,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+
+	if conf.MaxBundles < 1 {
+		log.Warn("Sanitizing invalid bundlepool max bundles", "provided", conf.MaxBundles, "updated", DefaultConfig.MaxBundles)
+		conf.MaxBundles = DefaultConfig.MaxBundles
+	}
+
+	if conf.MaxBundleTxs < 1 {
+		log.Warn("Sanitizing invalid bundlepool max bundle txs", "provided", conf.MaxBundleTxs, "updated", DefaultConfig.MaxBundleTxs)
+		conf.MaxBundleTxs = DefaultConfig.MaxBundleTxs
+	}
+
+	if conf.MaxBlocksAhead < 1 {
+		log.Warn("Sanitizing invalid bundlepool max blocks ahead", "provided", conf.MaxBlocksAhead, "updated", DefaultConfig.MaxBlocksAhead)
+		conf.MaxBlocksAhead = DefaultConfig.MaxBlocksAhead
+	}
+
+	if conf.MaxSenderBundles < 1 {
+		log.Warn("Sanitizing invalid bundlepool max sender bundles", "provided", conf.MaxSenderBundles, "updated", DefaultConfig.MaxSenderBundles)
+		conf.MaxSenderBundles = DefaultConfig.MaxSenderBundles
+	}
+
+	return conf
+}
+
+// blockChain provides the state of blockchain and current gas limit to do
+// some pre checks in bundle pool.
+type blockChain interface {
+	Config() *params.ChainConfig
+	CurrentBlock() *types.Header
+}
+
+// BundlePool keeps the bundles submitted for upcoming blocks, indexed by their
+// target block number. Bundles are dropped once their target block is mined.
+// Each sender can only have transactions in a limited number of bundles, so
+// that a single searcher can't take the whole pool.
+type BundlePool struct {
+	config Config
+	chain  blockChain
+	signer types.Signer
+
+	mu      sync.RWMutex
+	bundles map[uint64][]*Bundle // Bundles by target block number
+	known   map[common.Hash]uint64
+	senders map[common.Address]int // Number of bundles holding transactions of each sender
+}
+
+// New creates a new bundle pool.
+func New(config Config, chain blockChain) *BundlePool {
+	return &BundlePool{
+		config:  config.sanitize(),
+		chain:   chain,
+		signer:  types.LatestSigner(chain.Config()),
+		bundles: make(map[uint64][]*Bundle),
+		known:   make(map[common.Hash]uint64),
+		senders: make(map[common.Address]int),
+	}
+}
+
+// Add validates a bundle and inserts it into the pool.
+func (p *BundlePool) Add(bundle *Bundle) error {
+	if err := p.add(bundle); err != nil {
+		rejectedMeter.Mark(1)
+		return err
+	}
+
+	addedMeter.Mark(1)
+
+	return nil
+}
+
+func (p *BundlePool) add(bundle *Bundle) error {
+	if len(bundle.Txs) == 0 {
+		return ErrEmptyBundle
+	}
+
+	if len(bundle.Txs) > p.config.MaxBundleTxs {
+		return fmt.Errorf("%w: %d txs, max %d", ErrBundleTooLarge, len(bundle.Txs), p.config.MaxBundleTxs)
+	}
+
+	if bundle.MaxTimestamp != 0 && bundle.MinTimestamp > bundle.MaxTimestamp {
+		return ErrInvalidTimestamps
+	}
+
+	head := p.chain.CurrentBlock().Number.Uint64()
+	if bundle.BlockNumber <= head {
+		return fmt.Errorf("%w: target %d, head %d", ErrStaleBundle, bundle.BlockNumber, head)
+	}
+
+	if bundle.BlockNumber > head+p.config.MaxBlocksAhead {
+		return fmt.Errorf("%w: target %d, head %d", ErrFutureBundle, bundle.BlockNumber, head)
+	}
+
+	senders, err := p.recoverSenders(bundle)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prune(head)
+
+	hash := bundle.Hash()
+	if _, ok := p.known[hash]; ok {
+		return ErrAlreadyKnown
+	}
+
+	if len(p.known) >= p.config.MaxBundles {
+		return ErrPoolFull
+	}
+
+	for _, sender := range senders {
+		if p.senders[sender] >= p.config.MaxSenderBundles {
+			return fmt.Errorf("%w: %s", ErrSenderLimit, sender)
+		}
+	}
+
+	bundle.senders = senders
+
+	for _, sender := range senders {
+		p.senders[sender]++
+	}
+
+	p.bundles[bundle.BlockNumber] = append(p.bundles[bundle.BlockNumber], bundle)
+	p.known[hash] = bundle.BlockNumber
+
+	bundleGauge.Update(int64(len(p.known)))
+
+	return nil
+}
+
+// recoverSenders returns the distinct senders of the bundle transactions.
+func (p *BundlePool) recoverSenders(bundle *Bundle) ([]common.Address, error) {
+	senders := make([]common.Address, 0, len(bundle.Txs))
+
+	for i, tx := range bundle.Txs {
+		sender, err := types.Sender(p.signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: transaction %d: %v", ErrInvalidSender, i, err)
+		}
+
+		if !slices.Contains(senders, sender) {
+			senders = append(senders, sender)
+		}
+	}
+
+	return senders, nil
+}
+
+// Bundles returns the bundles which may be included in a block with the given
+// number and timestamp, in submission order.
+func (p *BundlePool) Bundles(number, timestamp uint64) []*Bundle {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var bundles []*Bundle
+
+	for _, bundle := range p.bundles[number] {
+		if bundle.Matches(number, timestamp) {
+			bundles = append(bundles, bundle)
+		}
+	}
+
+	return bundles
+}
+
+// Len returns the number of bundles in the pool.
+func (p *BundlePool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return len(p.known)
+}
+
+// prune drops all bundles targeting blocks up to and including head. The lock
+// must be held by the caller.
+func (p *BundlePool) prune(head uint64) {
+	for number, bundles := range p.bundles {
+		if number > head {
+			continue
+		}
+
+		for _, bundle := range bundles {
+			delete(p.known, bundle.Hash())
+
+			for _, sender := range bundle.senders {
+				if p.senders[sender]--; p.senders[sender] == 0 {
+					delete(p.senders, sender)
+				}
+			}
+		}
+
+		delete(p.bundles, number)
+	}
+}
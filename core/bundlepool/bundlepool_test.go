@@ -0,0 +1,184 @@
+package bundlepool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type testBlockChain struct {
+	head *types.Header
+}
+
+func (bc *testBlockChain) Config() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+func (bc *testBlockChain) CurrentBlock() *types.Header {
+	return bc.head
+}
+
+func newTestBundle(t *testing.T, number uint64, nonces ...uint64) *Bundle {
+	t.Helper()
+
+	key, _ := crypto.GenerateKey()
+
+	return newTestSenderBundle(t, key, number, nonces...)
+}
+
+func newTestSenderBundle(t *testing.T, key *ecdsa.PrivateKey, number uint64, nonces ...uint64) *Bundle {
+	t.Helper()
+
+	signer := types.LatestSigner(params.TestChainConfig)
+
+	bundle := &Bundle{BlockNumber: number}
+	for _, nonce := range nonces {
+		bundle.Txs = append(bundle.Txs, types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.GWei),
+		}))
+	}
+
+	return bundle
+}
+
+func TestBundlePoolAdd(t *testing.T) {
+	t.Parallel()
+
+	chain := &testBlockChain{head: &types.Header{Number: big.NewInt(10)}}
+	pool := New(Config{MaxBundles: 2, MaxBundleTxs: 2, MaxBlocksAhead: 5}, chain)
+
+	tests := []struct {
+		bundle *Bundle
+		err    error
+	}{
+		{newTestBundle(t, 11), ErrEmptyBundle},
+		{newTestBundle(t, 11, 0, 1, 2), ErrBundleTooLarge},
+		{newTestBundle(t, 10, 0), ErrStaleBundle},
+		{newTestBundle(t, 16, 0), ErrFutureBundle},
+		{&Bundle{Txs: newTestBundle(t, 11, 0).Txs, BlockNumber: 11, MinTimestamp: 2, MaxTimestamp: 1}, ErrInvalidTimestamps},
+		{&Bundle{Txs: types.Transactions{types.NewTx(&types.LegacyTx{Gas: params.TxGas})}, BlockNumber: 11}, ErrInvalidSender},
+	}
+
+	for i, tt := range tests {
+		if err := pool.Add(tt.bundle); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+
+	bundle := newTestBundle(t, 11, 0, 1)
+	if err := pool.Add(bundle); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+
+	if err := pool.Add(bundle); !errors.Is(err, ErrAlreadyKnown) {
+		t.Fatalf("duplicate error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+
+	if err := pool.Add(newTestBundle(t, 12, 0)); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+
+	if err := pool.Add(newTestBundle(t, 12, 1)); !errors.Is(err, ErrPoolFull) {
+		t.Fatalf("full pool error mismatch: have %v, want %v", err, ErrPoolFull)
+	}
+
+	// Mining the target block of the first bundle frees up its slot
+	chain.head = &types.Header{Number: big.NewInt(11)}
+
+	if err := pool.Add(newTestBundle(t, 12, 1)); err != nil {
+		t.Fatalf("failed to add bundle after pruning: %v", err)
+	}
+
+	if have := pool.Len(); have != 2 {
+		t.Fatalf("pool size mismatch: have %d, want 2", have)
+	}
+}
+
+func TestBundlePoolBundles(t *testing.T) {
+	t.Parallel()
+
+	pool := New(DefaultConfig, &testBlockChain{head: &types.Header{Number: big.NewInt(10)}})
+
+	var (
+		always = newTestBundle(t, 11, 0)
+		early  = &Bundle{Txs: newTestBundle(t, 11, 0).Txs, BlockNumber: 11, MaxTimestamp: 100}
+		late   = &Bundle{Txs: newTestBundle(t, 11, 0).Txs, BlockNumber: 11, MinTimestamp: 200}
+		future = newTestBundle(t, 12, 0)
+	)
+
+	for _, bundle := range []*Bundle{always, early, late, future} {
+		if err := pool.Add(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+
+	tests := []struct {
+		number, timestamp uint64
+		want              []*Bundle
+	}{
+		{11, 50, []*Bundle{always, early}},
+		{11, 150, []*Bundle{always}},
+		{11, 250, []*Bundle{always, late}},
+		{12, 250, []*Bundle{future}},
+		{13, 250, nil},
+	}
+
+	for i, tt := range tests {
+		have := pool.Bundles(tt.number, tt.timestamp)
+		if len(have) != len(tt.want) {
+			t.Errorf("test %d: bundle count mismatch: have %d, want %d", i, len(have), len(tt.want))
+			continue
+		}
+
+		for j := range have {
+			if have[j].Hash() != tt.want[j].Hash() {
+				t.Errorf("test %d: bundle %d mismatch: have %s, want %s", i, j, have[j].Hash(), tt.want[j].Hash())
+			}
+		}
+	}
+}
+
+func TestBundlePoolSenderLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain    = &testBlockChain{head: &types.Header{Number: big.NewInt(10)}}
+		pool     = New(Config{MaxBundles: 10, MaxBundleTxs: 2, MaxBlocksAhead: 5, MaxSenderBundles: 2}, chain)
+		key, _   = crypto.GenerateKey()
+		other, _ = crypto.GenerateKey()
+	)
+
+	for _, bundle := range []*Bundle{newTestSenderBundle(t, key, 11, 0), newTestSenderBundle(t, key, 12, 0, 1)} {
+		if err := pool.Add(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+
+	// The sender is out of bundles, even when sharing one with another sender
+	shared := newTestSenderBundle(t, other, 12, 0)
+	shared.Txs = append(shared.Txs, newTestSenderBundle(t, key, 12, 2).Txs...)
+
+	for _, bundle := range []*Bundle{newTestSenderBundle(t, key, 13, 2), shared} {
+		if err := pool.Add(bundle); !errors.Is(err, ErrSenderLimit) {
+			t.Fatalf("sender limit error mismatch: have %v, want %v", err, ErrSenderLimit)
+		}
+	}
+
+	if err := pool.Add(newTestSenderBundle(t, other, 13, 0)); err != nil {
+		t.Fatalf("failed to add bundle of other sender: %v", err)
+	}
+
+	// Mining the target block of a bundle gives the sender its slot back
+	chain.head = &types.Header{Number: big.NewInt(11)}
+
+	if err := pool.Add(newTestSenderBundle(t, key, 13, 2)); err != nil {
+		t.Fatalf("failed to add bundle after pruning: %v", err)
+	}
+}
@@ -261,8 +261,24 @@ var parallelizabilityTimer = metrics.NewRegisteredTimer("block/parallelizability
 // Process returns the receipts and logs accumulated during the process and
 // returns the amount of gas that was used in the process. If any of the
 // transactions failed to execute due to insufficient gas it will return an error.
-// nolint:gocognit
 func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config, interruptCtx context.Context) (types.Receipts, []*types.Log, uint64, error) {
+	receipts, logs, usedGas, _, err := p.process(block, statedb, cfg, interruptCtx, false)
+	return receipts, logs, usedGas, err
+}
+
+// Profile processes the block like Process, with the profiling of its parallel
+// execution enabled, and returns the profile.
+func (p *ParallelStateProcessor) Profile(block *types.Block, statedb *state.StateDB, cfg vm.Config) (*blockstm.ExecutionProfile, error) {
+	_, _, _, result, err := p.process(block, statedb, cfg, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockstm.NewExecutionProfile(result), nil
+}
+
+// nolint:gocognit
+func (p *ParallelStateProcessor) process(block *types.Block, statedb *state.StateDB, cfg vm.Config, interruptCtx context.Context, profile bool) (types.Receipts, []*types.Log, uint64, blockstm.ParallelExecutionResult, error) {
 	var (
 		receipts    types.Receipts
 		header      = block.Header()
@@ -273,25 +289,42 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 		metadata    bool
 	)
 
-	// Mutate the block and state according to any hard-fork specs
-	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
-		misc.ApplyDAOHardFork(statedb)
-	}
-
 	tasks := make([]blockstm.ExecTask, 0, len(block.Transactions()))
 
 	shouldDelayFeeCal := true
 
 	coinbase, _ := p.bc.Engine().Author(header)
 
+	// Blocks without dependencies between their transactions carry an empty
+	// list, the declared ones are only relied on if well formed
 	blockTxDependency := block.GetTxDependency()
 
-	deps := GetDeps(blockTxDependency)
+	// The dependencies may not cover every transaction of the block, like the
+	// state sync ones, in which case it's processed sequentially
+	if len(blockTxDependency) > 0 && len(blockTxDependency) != len(block.Transactions()) {
+		log.Debug("Processing block sequentially", "number", blockNumber, "hash", blockHash, "deps", len(blockTxDependency), "txs", len(block.Transactions()))
+
+		txReceipts, logs, gas, err := NewStateProcessor(p.config, p.bc, p.engine).Process(block, statedb, cfg, interruptCtx)
+
+		return txReceipts, logs, gas, blockstm.ParallelExecutionResult{}, err
+	}
+
+	if len(blockTxDependency) > 0 {
+		if err := ValidateTxDependency(blockTxDependency, len(block.Transactions())); err != nil {
+			reportBadTxDependency(block, coinbase, err)
+			return nil, nil, 0, blockstm.ParallelExecutionResult{}, err
+		}
 
-	if blockTxDependency != nil {
 		metadata = true
 	}
 
+	// Mutate the block and state according to any hard-fork specs
+	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
+		misc.ApplyDAOHardFork(statedb)
+	}
+
+	deps := GetDeps(blockTxDependency)
+
 	blockContext := NewEVMBlockContext(header, p.bc, nil)
 
 	// Iterate over and process the individual transactions
@@ -299,7 +332,7 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 		msg, err := TransactionToMessage(tx, types.MakeSigner(p.config, header.Number), header.BaseFee)
 		if err != nil {
 			log.Error("error creating message", "err", err)
-			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			return nil, nil, 0, blockstm.ParallelExecutionResult{}, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 		}
 
 		cleansdb := statedb.Copy()
@@ -308,62 +341,34 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 			shouldDelayFeeCal = false
 		}
 
-		if len(blockTxDependency) != len(block.Transactions()) {
-			task := &ExecutionTask{
-				msg:               *msg,
-				config:            p.config,
-				gasLimit:          block.GasLimit(),
-				blockNumber:       blockNumber,
-				blockHash:         blockHash,
-				tx:                tx,
-				index:             i,
-				cleanStateDB:      cleansdb,
-				finalStateDB:      statedb,
-				blockChain:        p.bc,
-				header:            header,
-				evmConfig:         cfg,
-				shouldDelayFeeCal: &shouldDelayFeeCal,
-				sender:            msg.From,
-				totalUsedGas:      usedGas,
-				receipts:          &receipts,
-				allLogs:           &allLogs,
-				dependencies:      deps[i],
-				coinbase:          coinbase,
-				blockContext:      blockContext,
-			}
-
-			tasks = append(tasks, task)
-		} else {
-			task := &ExecutionTask{
-				msg:               *msg,
-				config:            p.config,
-				gasLimit:          block.GasLimit(),
-				blockNumber:       blockNumber,
-				blockHash:         blockHash,
-				tx:                tx,
-				index:             i,
-				cleanStateDB:      cleansdb,
-				finalStateDB:      statedb,
-				blockChain:        p.bc,
-				header:            header,
-				evmConfig:         cfg,
-				shouldDelayFeeCal: &shouldDelayFeeCal,
-				sender:            msg.From,
-				totalUsedGas:      usedGas,
-				receipts:          &receipts,
-				allLogs:           &allLogs,
-				dependencies:      nil,
-				coinbase:          coinbase,
-				blockContext:      blockContext,
-			}
-
-			tasks = append(tasks, task)
+		task := &ExecutionTask{
+			msg:               *msg,
+			config:            p.config,
+			gasLimit:          block.GasLimit(),
+			blockNumber:       blockNumber,
+			blockHash:         blockHash,
+			tx:                tx,
+			index:             i,
+			cleanStateDB:      cleansdb,
+			finalStateDB:      statedb,
+			blockChain:        p.bc,
+			header:            header,
+			evmConfig:         cfg,
+			shouldDelayFeeCal: &shouldDelayFeeCal,
+			sender:            msg.From,
+			totalUsedGas:      usedGas,
+			receipts:          &receipts,
+			allLogs:           &allLogs,
+			dependencies:      deps[i],
+			coinbase:          coinbase,
+			blockContext:      blockContext,
 		}
+
+		tasks = append(tasks, task)
 	}
 
 	backupStateDB := statedb.Copy()
 
-	profile := false
 	result, err := blockstm.ExecuteParallel(tasks, profile, metadata, cfg.ParallelSpeculativeProcesses, interruptCtx)
 
 	if err == nil && profile && result.Deps != nil {
@@ -398,20 +403,32 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 				t.totalUsedGas = usedGas
 			}
 
-			_, err = blockstm.ExecuteParallel(tasks, false, metadata, cfg.ParallelSpeculativeProcesses, interruptCtx)
+			result, err = blockstm.ExecuteParallel(tasks, profile, metadata, cfg.ParallelSpeculativeProcesses, interruptCtx)
 
 			break
 		}
 	}
 
 	if err != nil {
-		return nil, nil, 0, err
+		return nil, nil, 0, blockstm.ParallelExecutionResult{}, err
+	}
+
+	// The transactions read the writes of earlier ones regardless of the declared
+	// dependencies, which only schedule them. Producers declaring too few are
+	// rejected rather than relied on.
+	if metadata {
+		if violations := checkTxDependency(blockTxDependency, result.TxIO); len(violations) > 0 {
+			err := &BadTxDependencyError{Violations: violations}
+			reportBadTxDependency(block, coinbase, err)
+
+			return nil, nil, 0, blockstm.ParallelExecutionResult{}, err
+		}
 	}
 
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
 	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles(), nil)
 
-	return receipts, allLogs, *usedGas, nil
+	return receipts, allLogs, *usedGas, result, nil
 }
 
 func GetDeps(txDependency [][]uint64) map[int][]int {
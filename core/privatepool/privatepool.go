@@ -0,0 +1,330 @@
+// Package privatepool implements a pool of private transactions, which are
+// never announced to peers and may only be included by the local miner until
+// their maximum block number.
+package privatepool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// ErrStaleTx is returned if the maximum block number of a transaction has
+	// already been mined.
+	ErrStaleTx = errors.New("max block number already mined")
+
+	// ErrFutureTx is returned if the maximum block number of a transaction is
+	// too far ahead of the current head.
+	ErrFutureTx = errors.New("max block number too far in the future")
+
+	// ErrNonceTaken is returned if the pool already holds a transaction of the
+	// same sender with the same nonce.
+	ErrNonceTaken = errors.New("nonce already taken by a private transaction")
+
+	// ErrAlreadyKnown is returned if the transaction is already in the pool.
+	ErrAlreadyKnown = errors.New("private transaction already known")
+
+	// ErrPoolFull is returned if the pool holds the maximum number of
+	// transactions.
+	ErrPoolFull = errors.New("private pool full")
+
+	// ErrSenderLimit is returned if the pool already holds the maximum number of
+	// transactions of the sender.
+	ErrSenderLimit = errors.New("too many private transactions from sender")
+)
+
+var (
+	txGauge        = metrics.NewRegisteredGauge("privatepool/txs", nil)
+	addedMeter     = metrics.NewRegisteredMeter("privatepool/added", nil)
+	rejectedMeter  = metrics.NewRegisteredMeter("privatepool/rejected", nil)
+	cancelledMeter = metrics.NewRegisteredMeter("privatepool/cancelled", nil)
+	includedMeter  = metrics.NewRegisteredMeter("privatepool/included", nil)
+	expiredMeter   = metrics.NewRegisteredMeter("privatepool/expired", nil)
+	replacedMeter  = metrics.NewRegisteredMeter("privatepool/replaced", nil)
+)
+
+// Config are the configuration parameters of the private transaction pool.
+type Config struct {
+	MaxTxs         int    // Maximum number of transactions kept in the pool
+	MaxSenderTxs   int    // Maximum number of transactions kept per sender
+	MaxBlocksAhead uint64 // Maximum distance between the head and the max block number of a transaction
+	PriceLimit     uint64 // Minimum gas tip to enforce for acceptance into the pool
+}
+
+// DefaultConfig contains the default configurations for the private pool.
+var DefaultConfig = Config{
+	MaxTxs:         4096,
+	MaxSenderTxs:   16,
+	MaxBlocksAhead: 128,
+	PriceLimit:     1,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+
+	if conf.MaxTxs < 1 {
+		log.Warn("Sanitizing invalid privatepool max txs", "provided", conf.MaxTxs, "updated", DefaultConfig.MaxTxs)
+		conf.MaxTxs = DefaultConfig.MaxTxs
+	}
+
+	if conf.MaxSenderTxs < 1 {
+		log.Warn("Sanitizing invalid privatepool max sender txs", "provided", conf.MaxSenderTxs, "updated", DefaultConfig.MaxSenderTxs)
+		conf.MaxSenderTxs = DefaultConfig.MaxSenderTxs
+	}
+
+	if conf.MaxBlocksAhead < 1 {
+		log.Warn("Sanitizing invalid privatepool max blocks ahead", "provided", conf.MaxBlocksAhead, "updated", DefaultConfig.MaxBlocksAhead)
+		conf.MaxBlocksAhead = DefaultConfig.MaxBlocksAhead
+	}
+
+	if conf.PriceLimit < 1 {
+		log.Warn("Sanitizing invalid privatepool price limit", "provided", conf.PriceLimit, "updated", DefaultConfig.PriceLimit)
+		conf.PriceLimit = DefaultConfig.PriceLimit
+	}
+
+	return conf
+}
+
+// blockChain provides the head state and the transaction index the pool uses
+// to tell included transactions from dropped ones.
+type blockChain interface {
+	CurrentBlock() *types.Header
+	StateAt(root common.Hash) (*state.StateDB, error)
+	GetTransactionLookup(hash common.Hash) *rawdb.LegacyTxLookupEntry
+}
+
+// privateTx is a transaction held by the pool along with its expiry.
+type privateTx struct {
+	tx       *types.Transaction
+	sender   common.Address
+	maxBlock uint64 // Last block the transaction may be included in
+}
+
+// PrivatePool keeps transactions submitted privately until they're included,
+// cancelled or their maximum block number is mined. The pool is pruned lazily
+// whenever it's accessed after a new head.
+type PrivatePool struct {
+	config      Config
+	chainConfig *params.ChainConfig
+	chain       blockChain
+	signer      types.Signer
+
+	mu   sync.Mutex
+	txs  map[common.Hash]*privateTx
+	head common.Hash // Head the pool was last pruned at
+}
+
+// New creates a new private transaction pool.
+func New(config Config, chainConfig *params.ChainConfig, chain blockChain) *PrivatePool {
+	return &PrivatePool{
+		config:      config.sanitize(),
+		chainConfig: chainConfig,
+		chain:       chain,
+		signer:      types.LatestSigner(chainConfig),
+		txs:         make(map[common.Hash]*privateTx),
+	}
+}
+
+// Add validates a private transaction and inserts it into the pool, to be
+// included in a block up to and including maxBlock. A zero maxBlock keeps the
+// transaction for the maximum number of blocks allowed.
+func (p *PrivatePool) Add(tx *types.Transaction, maxBlock uint64) error {
+	if err := p.add(tx, maxBlock); err != nil {
+		rejectedMeter.Mark(1)
+		return err
+	}
+
+	addedMeter.Mark(1)
+
+	return nil
+}
+
+func (p *PrivatePool) add(tx *types.Transaction, maxBlock uint64) error {
+	sender, err := types.Sender(p.signer, tx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", txpool.ErrInvalidSender, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	head := p.chain.CurrentBlock()
+
+	statedb, err := p.prune(head)
+	if err != nil {
+		return err
+	}
+
+	number := head.Number.Uint64()
+
+	switch {
+	case maxBlock == 0:
+		maxBlock = number + p.config.MaxBlocksAhead
+	case maxBlock <= number:
+		return fmt.Errorf("%w: max block %d, head %d", ErrStaleTx, maxBlock, number)
+	case maxBlock > number+p.config.MaxBlocksAhead:
+		return fmt.Errorf("%w: max block %d, head %d", ErrFutureTx, maxBlock, number)
+	}
+
+	// The transactions are held to the rules of the public pool, as they take
+	// up room in the pool whether they can be included or not
+	if err := txpool.ValidateTransaction(tx, txpool.NewValidationOptions(p.chainConfig, head)); err != nil {
+		return err
+	}
+
+	if tx.GasTipCapIntCmp(new(big.Int).SetUint64(p.config.PriceLimit)) < 0 {
+		return fmt.Errorf("%w: tip %v, limit %d", txpool.ErrUnderpriced, tx.GasTipCap(), p.config.PriceLimit)
+	}
+
+	if nonce := statedb.GetNonce(sender); tx.Nonce() < nonce {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", core.ErrNonceTooLow, sender, tx.Nonce(), nonce)
+	}
+
+	if _, ok := p.txs[tx.Hash()]; ok {
+		return ErrAlreadyKnown
+	}
+
+	var (
+		senderTxs int
+		cost      = tx.Cost()
+	)
+
+	for _, ptx := range p.txs {
+		if ptx.sender != sender {
+			continue
+		}
+
+		if ptx.tx.Nonce() == tx.Nonce() {
+			return fmt.Errorf("%w: address %v, nonce %d", ErrNonceTaken, sender, tx.Nonce())
+		}
+
+		senderTxs++
+
+		cost.Add(cost, ptx.tx.Cost())
+	}
+
+	if senderTxs >= p.config.MaxSenderTxs {
+		return fmt.Errorf("%w: %s", ErrSenderLimit, sender)
+	}
+
+	// The sender must afford all of its private transactions
+	if balance := statedb.GetBalance(sender); balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", core.ErrInsufficientFunds, sender, balance, cost)
+	}
+
+	if len(p.txs) >= p.config.MaxTxs {
+		return ErrPoolFull
+	}
+
+	p.txs[tx.Hash()] = &privateTx{tx: tx, sender: sender, maxBlock: maxBlock}
+
+	txGauge.Update(int64(len(p.txs)))
+
+	return nil
+}
+
+// Cancel drops the private transaction with the given hash if it was sent by
+// the given sender, and reports whether it was dropped.
+func (p *PrivatePool) Cancel(hash common.Hash, sender common.Address) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ptx, ok := p.txs[hash]; !ok || ptx.sender != sender {
+		return false
+	}
+
+	delete(p.txs, hash)
+
+	cancelledMeter.Mark(1)
+	txGauge.Update(int64(len(p.txs)))
+
+	return true
+}
+
+// Pending returns the private transactions which may be included in the block
+// with the given number, grouped by sender and sorted by nonce.
+func (p *PrivatePool) Pending(number uint64) map[common.Address]types.Transactions {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.prune(p.chain.CurrentBlock()); err != nil {
+		log.Debug("Failed to prune private pool", "err", err)
+	}
+
+	pending := make(map[common.Address]types.Transactions)
+
+	for _, ptx := range p.txs {
+		if ptx.maxBlock >= number {
+			pending[ptx.sender] = append(pending[ptx.sender], ptx.tx)
+		}
+	}
+
+	for _, txs := range pending {
+		sort.Sort(types.TxByNonce(txs))
+	}
+
+	return pending
+}
+
+// Len returns the number of transactions in the pool.
+func (p *PrivatePool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.txs)
+}
+
+// prune drops the transactions which can no longer be included on top of head:
+// those whose nonce was used, either by themselves or by another transaction,
+// and those whose maximum block number was reached. It returns the state of
+// head. The lock must be held by the caller.
+func (p *PrivatePool) prune(head *types.Header) (*state.StateDB, error) {
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	if head.Hash() == p.head {
+		return statedb, nil
+	}
+
+	p.head = head.Hash()
+
+	number := head.Number.Uint64()
+
+	for hash, ptx := range p.txs {
+		switch {
+		case ptx.tx.Nonce() < statedb.GetNonce(ptx.sender):
+			if p.chain.GetTransactionLookup(hash) != nil {
+				includedMeter.Mark(1)
+			} else {
+				replacedMeter.Mark(1)
+			}
+		case ptx.maxBlock <= number:
+			expiredMeter.Mark(1)
+		default:
+			continue
+		}
+
+		delete(p.txs, hash)
+	}
+
+	txGauge.Update(int64(len(p.txs)))
+
+	return statedb, nil
+}
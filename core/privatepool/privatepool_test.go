@@ -0,0 +1,212 @@
+package privatepool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type testBlockChain struct {
+	head     *types.Header
+	statedb  *state.StateDB
+	included map[common.Hash]bool
+}
+
+func newTestBlockChain(number int64) *testBlockChain {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	return &testBlockChain{
+		head:     &types.Header{Number: big.NewInt(number), GasLimit: 30_000_000},
+		statedb:  statedb,
+		included: make(map[common.Hash]bool),
+	}
+}
+
+func (bc *testBlockChain) CurrentBlock() *types.Header {
+	return bc.head
+}
+
+func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return bc.statedb.Copy(), nil
+}
+
+func (bc *testBlockChain) GetTransactionLookup(hash common.Hash) *rawdb.LegacyTxLookupEntry {
+	if !bc.included[hash] {
+		return nil
+	}
+
+	return &rawdb.LegacyTxLookupEntry{}
+}
+
+// mine advances the head by one block including the given transactions.
+func (bc *testBlockChain) mine(txs ...*types.Transaction) {
+	for _, tx := range txs {
+		sender, _ := types.Sender(types.LatestSigner(params.TestChainConfig), tx)
+		bc.statedb.SetNonce(sender, tx.Nonce()+1)
+		bc.included[tx.Hash()] = true
+	}
+
+	bc.head = &types.Header{Number: new(big.Int).Add(bc.head.Number, common.Big1), GasLimit: bc.head.GasLimit}
+}
+
+func newTestTx(key *ecdsa.PrivateKey, nonce uint64, gas uint64) *types.Transaction {
+	return newPricedTestTx(key, nonce, gas, big.NewInt(params.GWei))
+}
+
+func newPricedTestTx(key *ecdsa.PrivateKey, nonce uint64, gas uint64, price *big.Int) *types.Transaction {
+	return types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    nonce,
+		Gas:      gas,
+		GasPrice: price,
+		To:       &common.Address{0x01},
+	})
+}
+
+func TestPrivatePoolAdd(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		chain  = newTestBlockChain(10)
+		pool   = New(Config{MaxTxs: 2, MaxBlocksAhead: 5}, params.TestChainConfig, chain)
+	)
+
+	chain.statedb.SetNonce(crypto.PubkeyToAddress(key.PublicKey), 1)
+	chain.statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+
+	tests := []struct {
+		tx       *types.Transaction
+		maxBlock uint64
+		err      error
+	}{
+		{newTestTx(key, 1, params.TxGas), 10, ErrStaleTx},
+		{newTestTx(key, 1, params.TxGas), 16, ErrFutureTx},
+		{newTestTx(key, 1, params.TxGas-1), 11, core.ErrIntrinsicGas},
+		{newTestTx(key, 1, 30_000_001), 11, txpool.ErrGasLimit},
+		{newPricedTestTx(key, 1, params.TxGas, new(big.Int)), 11, txpool.ErrUnderpriced},
+		{newPricedTestTx(key, 1, params.TxGas, big.NewInt(params.Ether)), 11, core.ErrInsufficientFunds},
+		{newTestTx(key, 0, params.TxGas), 11, core.ErrNonceTooLow},
+		{newTestTx(key, 1, params.TxGas), 11, nil},
+		{newTestTx(key, 1, params.TxGas+1), 11, ErrNonceTaken},
+		{newTestTx(key, 2, params.TxGas), 0, nil},
+		{newTestTx(key, 3, params.TxGas), 0, ErrPoolFull},
+	}
+
+	for i, tt := range tests {
+		if err := pool.Add(tt.tx, tt.maxBlock); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+
+	if err := pool.Add(tests[7].tx, 11); !errors.Is(err, ErrAlreadyKnown) {
+		t.Errorf("duplicate error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+
+	if pending := pool.Pending(11); len(pending) != 1 || len(pending[crypto.PubkeyToAddress(key.PublicKey)]) != 2 {
+		t.Fatalf("pending mismatch: have %v", pending)
+	}
+
+	// The transaction without max block number is kept for MaxBlocksAhead blocks
+	if pending := pool.Pending(15); len(pending[crypto.PubkeyToAddress(key.PublicKey)]) != 1 {
+		t.Fatalf("pending mismatch for block 15: have %v", pending)
+	}
+
+	// Only the sender of a transaction may cancel it
+	if pool.Cancel(tests[9].tx.Hash(), common.Address{}) {
+		t.Fatalf("cancelled by another sender")
+	}
+
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	if !pool.Cancel(tests[9].tx.Hash(), sender) || pool.Cancel(tests[9].tx.Hash(), sender) {
+		t.Fatalf("cancel mismatch")
+	}
+
+	if pool.Len() != 1 {
+		t.Fatalf("pool size mismatch: have %d, want 1", pool.Len())
+	}
+}
+
+func TestPrivatePoolPrune(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		chain  = newTestBlockChain(10)
+		pool   = New(DefaultConfig, params.TestChainConfig, chain)
+
+		included = newTestTx(key, 0, params.TxGas)
+		replaced = newTestTx(key, 1, params.TxGas)
+		expiring = newTestTx(key, 2, params.TxGas)
+		pending  = newTestTx(key, 3, params.TxGas)
+	)
+
+	chain.statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+
+	for _, tx := range []*types.Transaction{included, replaced, expiring, pending} {
+		maxBlock := uint64(20)
+		if tx == expiring {
+			maxBlock = 11
+		}
+
+		if err := pool.Add(tx, maxBlock); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", tx.Nonce(), err)
+		}
+	}
+
+	// Mine the first transaction along with a public one reusing the second nonce
+	chain.mine(included, newTestTx(key, 1, params.TxGas+1))
+
+	txs := pool.Pending(12)[crypto.PubkeyToAddress(key.PublicKey)]
+	if len(txs) != 1 || txs[0].Hash() != pending.Hash() {
+		t.Fatalf("pending mismatch: have %v, want %s", txs, pending.Hash())
+	}
+
+	if pool.Len() != 1 {
+		t.Fatalf("pool size mismatch: have %d, want 1", pool.Len())
+	}
+}
+
+func TestPrivatePoolSenderLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		chain  = newTestBlockChain(10)
+		pool   = New(Config{MaxTxs: 16, MaxSenderTxs: 2, MaxBlocksAhead: 5}, params.TestChainConfig, chain)
+	)
+
+	// Enough for two transfers, but not three
+	chain.statedb.AddBalance(sender, new(big.Int).SetUint64(3*params.TxGas*params.GWei-1))
+
+	second := newTestTx(key, 1, params.TxGas)
+
+	for _, tx := range []*types.Transaction{newTestTx(key, 0, params.TxGas), second} {
+		if err := pool.Add(tx, 0); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", tx.Nonce(), err)
+		}
+	}
+
+	if err := pool.Add(newTestTx(key, 2, params.TxGas), 0); !errors.Is(err, ErrSenderLimit) {
+		t.Fatalf("sender limit error mismatch: have %v, want %v", err, ErrSenderLimit)
+	}
+
+	// The cost of the pending transactions counts against the balance
+	if !pool.Cancel(second.Hash(), sender) {
+		t.Fatalf("failed to cancel second transaction")
+	}
+
+	if err := pool.Add(newPricedTestTx(key, 1, params.TxGas, big.NewInt(2*params.GWei)), 0); !errors.Is(err, core.ErrInsufficientFunds) {
+		t.Fatalf("balance error mismatch: have %v, want %v", err, core.ErrInsufficientFunds)
+	}
+}
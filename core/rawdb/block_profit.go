@@ -0,0 +1,46 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// blockProfitPrefix + hash -> revenue breakdown of a block built by the node
+var blockProfitPrefix = []byte("matic-block-profit-")
+
+// blockProfitKey = blockProfitPrefix + hash
+func blockProfitKey(hash common.Hash) []byte {
+	return append(blockProfitPrefix, hash.Bytes()...)
+}
+
+// ReadBlockProfit retrieves the revenue breakdown of the block with the given
+// hash, nil if the block wasn't built by the node.
+func ReadBlockProfit(db ethdb.KeyValueReader, hash common.Hash) *types.BlockProfit {
+	data, _ := db.Get(blockProfitKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+
+	profit := new(types.BlockProfit)
+	if err := rlp.DecodeBytes(data, profit); err != nil {
+		log.Error("Invalid block profit RLP", "hash", hash, "err", err)
+		return nil
+	}
+
+	return profit
+}
+
+// WriteBlockProfit stores the revenue breakdown of a block built by the node.
+func WriteBlockProfit(db ethdb.KeyValueWriter, profit *types.BlockProfit) {
+	data, err := rlp.EncodeToBytes(profit)
+	if err != nil {
+		log.Crit("Failed to encode block profit", "err", err)
+	}
+
+	if err := db.Put(blockProfitKey(profit.Hash), data); err != nil {
+		log.Crit("Failed to store block profit", "err", err)
+	}
+}
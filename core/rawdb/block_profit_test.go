@@ -0,0 +1,49 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBlockProfitStorage(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+
+	if profit := ReadBlockProfit(db, common.Hash{0x01}); profit != nil {
+		t.Fatalf("non existent profit returned: %v", profit)
+	}
+
+	profit := &types.BlockProfit{
+		Number:        10,
+		Hash:          common.Hash{0x01},
+		Coinbase:      common.Address{0x02},
+		BurntContract: common.Address{0x03},
+		Txs: []*types.TxProfit{
+			{Hash: common.Hash{0x04}, GasUsed: 21000, Tip: big.NewInt(5), Transfer: big.NewInt(0), Burnt: big.NewInt(7)},
+			{Hash: common.Hash{0x05}, GasUsed: 50000, Tip: big.NewInt(1), Transfer: big.NewInt(100), Burnt: big.NewInt(9)},
+		},
+		StateSyncs:   2,
+		StateSyncGas: 80000,
+	}
+
+	WriteBlockProfit(db, profit)
+
+	have := ReadBlockProfit(db, profit.Hash)
+	if have == nil {
+		t.Fatalf("stored profit not found")
+	}
+
+	if have.Number != profit.Number || have.Coinbase != profit.Coinbase || have.BurntContract != profit.BurntContract ||
+		have.StateSyncs != profit.StateSyncs || have.StateSyncGas != profit.StateSyncGas || len(have.Txs) != 2 {
+		t.Fatalf("profit mismatch: have %+v, want %+v", have, profit)
+	}
+
+	if have.Tips().Cmp(big.NewInt(6)) != 0 || have.Transfers().Cmp(big.NewInt(100)) != 0 ||
+		have.Burnt().Cmp(big.NewInt(16)) != 0 || have.Value().Cmp(big.NewInt(106)) != 0 {
+		t.Fatalf("totals mismatch: tips %v, transfers %v, burnt %v, value %v", have.Tips(), have.Transfers(), have.Burnt(), have.Value())
+	}
+}
@@ -0,0 +1,110 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// builderAuctionPrefix + num (uint64 big endian) + parent hash -> builder auction run for the block
+var builderAuctionPrefix = []byte("matic-builder-auction-")
+
+// builderAuctionNumberKey = builderAuctionPrefix + num (uint64 big endian)
+func builderAuctionNumberKey(number uint64) []byte {
+	return append(append([]byte{}, builderAuctionPrefix...), encodeBlockNumber(number)...)
+}
+
+// builderAuctionKey = builderAuctionPrefix + num (uint64 big endian) + parent hash
+func builderAuctionKey(number uint64, parent common.Hash) []byte {
+	return append(builderAuctionNumberKey(number), parent.Bytes()...)
+}
+
+// ReadBuilderAuction retrieves the builder auction run for the block with the
+// given number on top of the given parent.
+func ReadBuilderAuction(db ethdb.KeyValueReader, number uint64, parent common.Hash) *types.BuilderAuction {
+	data, _ := db.Get(builderAuctionKey(number, parent))
+	if len(data) == 0 {
+		return nil
+	}
+
+	return decodeBuilderAuction(data, number)
+}
+
+// ReadBuilderAuctions retrieves the builder auctions run for the block with
+// the given number, one for each parent it was proposed on.
+func ReadBuilderAuctions(db ethdb.Iteratee, number uint64) []*types.BuilderAuction {
+	var (
+		prefix   = builderAuctionNumberKey(number)
+		auctions []*types.BuilderAuction
+	)
+
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+common.HashLength {
+			continue
+		}
+
+		if auction := decodeBuilderAuction(it.Value(), number); auction != nil {
+			auctions = append(auctions, auction)
+		}
+	}
+
+	return auctions
+}
+
+func decodeBuilderAuction(data []byte, number uint64) *types.BuilderAuction {
+	auction := new(types.BuilderAuction)
+	if err := rlp.DecodeBytes(data, auction); err != nil {
+		log.Error("Invalid builder auction RLP", "number", number, "err", err)
+		return nil
+	}
+
+	return auction
+}
+
+// WriteBuilderAuction stores the builder auction run for a block, replacing
+// the one already stored for the same number and parent.
+func WriteBuilderAuction(db ethdb.KeyValueWriter, auction *types.BuilderAuction) {
+	data, err := rlp.EncodeToBytes(auction)
+	if err != nil {
+		log.Crit("Failed to encode builder auction", "err", err)
+	}
+
+	if err := db.Put(builderAuctionKey(auction.Number, auction.ParentHash), data); err != nil {
+		log.Crit("Failed to store builder auction", "err", err)
+	}
+}
+
+// DeleteBuilderAuctionsBefore removes the builder auctions run for the blocks
+// below the given number.
+func DeleteBuilderAuctionsBefore(db ethdb.KeyValueStore, number uint64) {
+	it := db.NewIterator(builderAuctionPrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) < len(builderAuctionPrefix)+8 {
+			continue
+		}
+
+		if binary.BigEndian.Uint64(key[len(builderAuctionPrefix):]) >= number {
+			break
+		}
+
+		if err := batch.Delete(key); err != nil {
+			log.Crit("Failed to delete builder auction", "err", err)
+		}
+	}
+
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete builder auctions", "err", err)
+	}
+}
@@ -0,0 +1,77 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBuilderAuctionStorage(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+
+	if auctions := ReadBuilderAuctions(db, 10); auctions != nil {
+		t.Fatalf("non existent auctions returned: %v", auctions)
+	}
+
+	auction := &types.BuilderAuction{
+		Number:     10,
+		ParentHash: common.Hash{0x01},
+		Deadline:   1000,
+		Bids: []*types.BuilderAuctionBid{
+			{Time: 900, Builder: common.Address{0x02}, Value: big.NewInt(5), TxHash: common.Hash{0x03}, BidHash: common.Hash{0x04}},
+			{Time: 950, Builder: common.Address{0x05}, Value: big.NewInt(7), TxHash: common.Hash{0x06}, BidHash: common.Hash{0x07}},
+		},
+		Winner: common.Hash{0x07},
+	}
+
+	WriteBuilderAuction(db, &types.BuilderAuction{Number: 10, ParentHash: auction.ParentHash, Reason: "no bids"})
+	WriteBuilderAuction(db, auction)
+	WriteBuilderAuction(db, &types.BuilderAuction{Number: 10, ParentHash: common.Hash{0x02}, Reason: "no bids"})
+
+	// Auctions on the same parent replace each other
+	have := ReadBuilderAuction(db, 10, auction.ParentHash)
+	if have == nil || have.Deadline != auction.Deadline || len(have.Bids) != 2 || have.Reason != "" {
+		t.Fatalf("auction mismatch: have %+v, want %+v", have, auction)
+	}
+
+	if bid := have.WinningBid(); bid == nil || bid.Builder != (common.Address{0x05}) || bid.Value.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("winning bid mismatch: %+v", bid)
+	}
+
+	auctions := ReadBuilderAuctions(db, 10)
+	if len(auctions) != 2 {
+		t.Fatalf("auction count mismatch: have %d, want 2", len(auctions))
+	}
+
+	if auctions[1].ParentHash != (common.Hash{0x02}) || auctions[1].WinningBid() != nil {
+		t.Fatalf("second auction mismatch: %+v", auctions[1])
+	}
+}
+
+func TestBuilderAuctionPruning(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+
+	for number := uint64(1); number <= 5; number++ {
+		WriteBuilderAuction(db, &types.BuilderAuction{Number: number, ParentHash: common.Hash{0x01}})
+		WriteBuilderAuction(db, &types.BuilderAuction{Number: number, ParentHash: common.Hash{0x02}})
+	}
+
+	DeleteBuilderAuctionsBefore(db, 4)
+
+	for number := uint64(1); number <= 5; number++ {
+		want := 2
+		if number < 4 {
+			want = 0
+		}
+
+		if have := len(ReadBuilderAuctions(db, number)); have != want {
+			t.Errorf("block %d: auction count mismatch: have %d, want %d", number, have, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var blockExecutionBadTxDependencyCounter = metrics.NewRegisteredCounter("chain/execution/badTxDependency", nil)
+
+// TxDependencyViolation is a read of a transaction from the writes of an
+// earlier one, which its declared dependencies don't lead to.
+type TxDependencyViolation struct {
+	Tx         int `json:"tx"`
+	Dependency int `json:"dependency"`
+}
+
+// BadTxDependencyError is returned by the parallel state processor for blocks
+// declaring dependencies between their transactions it can't rely on. The
+// block is then processed sequentially.
+type BadTxDependencyError struct {
+	Reason     string
+	Violations []TxDependencyViolation
+}
+
+func (e *BadTxDependencyError) Error() string {
+	if e.Reason != "" {
+		return "bad tx dependency: " + e.Reason
+	}
+
+	return fmt.Sprintf("bad tx dependency: %d undeclared dependencies, first tx %d on tx %d", len(e.Violations), e.Violations[0].Tx, e.Violations[0].Dependency)
+}
+
+// ValidateTxDependency checks the dependencies declared for a block of txs
+// transactions are well formed: one list per transaction, of distinct earlier
+// transactions.
+func ValidateTxDependency(deps [][]uint64, txs int) error {
+	if len(deps) != txs {
+		return &BadTxDependencyError{Reason: fmt.Sprintf("%d dependency lists for %d transactions", len(deps), txs)}
+	}
+
+	for i, txDeps := range deps {
+		seen := make(map[uint64]struct{}, len(txDeps))
+
+		for _, dep := range txDeps {
+			if dep >= uint64(i) {
+				return &BadTxDependencyError{Reason: fmt.Sprintf("tx %d depends on later tx %d", i, dep)}
+			}
+
+			if _, ok := seen[dep]; ok {
+				return &BadTxDependencyError{Reason: fmt.Sprintf("tx %d depends on tx %d twice", i, dep)}
+			}
+
+			seen[dep] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// CheckTxDependency returns the reads of the transactions from the writes of
+// earlier ones their declared dependencies don't lead to, directly or through
+// other transactions. The dependencies must be well formed.
+func CheckTxDependency(deps [][]uint64, reads [][]blockstm.ReadDescriptor, writes [][]blockstm.WriteDescriptor) []TxDependencyViolation {
+	var (
+		violations []TxDependencyViolation
+		reachable  = make([][]bool, len(deps))
+		writers    = make(map[blockstm.Key]int) // Last transaction writing each path
+	)
+
+	for i := range deps {
+		reachable[i] = make([]bool, i)
+
+		for _, dep := range deps[i] {
+			reachable[i][dep] = true
+
+			for j, ok := range reachable[dep] {
+				reachable[i][j] = reachable[i][j] || ok
+			}
+		}
+
+		if i < len(reads) {
+			missing := make(map[int]struct{})
+
+			for _, read := range reads[i] {
+				if writer, ok := writers[read.Path]; ok && !reachable[i][writer] {
+					missing[writer] = struct{}{}
+				}
+			}
+
+			txViolations := make([]TxDependencyViolation, 0, len(missing))
+			for writer := range missing {
+				txViolations = append(txViolations, TxDependencyViolation{Tx: i, Dependency: writer})
+			}
+
+			sort.Slice(txViolations, func(x, y int) bool { return txViolations[x].Dependency < txViolations[y].Dependency })
+
+			violations = append(violations, txViolations...)
+		}
+
+		if i < len(writes) {
+			for _, write := range writes[i] {
+				writers[write.Path] = i
+			}
+		}
+	}
+
+	return violations
+}
+
+// checkTxDependency checks the dependencies declared for a block against the
+// reads and writes of its parallel execution.
+func checkTxDependency(deps [][]uint64, txio *blockstm.TxnInputOutput) []TxDependencyViolation {
+	var (
+		reads  = make([][]blockstm.ReadDescriptor, len(deps))
+		writes = make([][]blockstm.WriteDescriptor, len(deps))
+	)
+
+	for i := range deps {
+		reads[i], writes[i] = txio.ReadSet(i), txio.AllWriteSet(i)
+	}
+
+	return CheckTxDependency(deps, reads, writes)
+}
+
+// reportBadTxDependency accounts for a block whose declared dependencies were
+// rejected, logging the block producer and the peer the block was received
+// from, if any.
+func reportBadTxDependency(block *types.Block, producer common.Address, err error) {
+	blockExecutionBadTxDependencyCounter.Inc(1)
+
+	var peer string
+	if from, ok := block.ReceivedFrom.(interface{ ID() string }); ok {
+		peer = from.ID()
+	}
+
+	log.Warn("Rejected block transaction dependencies", "number", block.Number(), "hash", block.Hash(), "producer", producer, "peer", peer, "err", err)
+}
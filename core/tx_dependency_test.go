@@ -0,0 +1,170 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestValidateTxDependency(t *testing.T) {
+	t.Parallel()
+
+	for i, tt := range []struct {
+		deps  [][]uint64
+		txs   int
+		valid bool
+	}{
+		{[][]uint64{{}, {0}, {0, 1}}, 3, true},
+		{[][]uint64{{}, {0}}, 3, false},
+		{[][]uint64{{}, {1}, {}}, 3, false},
+		{[][]uint64{{}, {0}, {2}}, 3, false},
+		{[][]uint64{{}, {0, 0}}, 2, false},
+	} {
+		err := ValidateTxDependency(tt.deps, tt.txs)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("test %d: validity mismatch: have %v (err %v), want %v", i, valid, err, tt.valid)
+		}
+	}
+}
+
+func TestCheckTxDependency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = blockstm.NewAddressKey(common.Address{0x0a})
+		b = blockstm.NewAddressKey(common.Address{0x0b})
+		c = blockstm.NewAddressKey(common.Address{0x0c})
+	)
+
+	// Tx 1 and 2 write a and b, tx 3 reads both, and tx 4 reads c it writes
+	// itself and a written by tx 3
+	var (
+		reads = [][]blockstm.ReadDescriptor{
+			{},
+			{{Path: a}},
+			{},
+			{{Path: a}, {Path: b}},
+			{{Path: a}, {Path: c}},
+		}
+		writes = [][]blockstm.WriteDescriptor{
+			{{Path: a}},
+			{{Path: a}},
+			{{Path: b}},
+			{{Path: a}},
+			{{Path: c}},
+		}
+	)
+
+	for i, tt := range []struct {
+		deps [][]uint64
+		want []TxDependencyViolation
+	}{
+		// Transitive dependencies are enough
+		{[][]uint64{{}, {0}, {}, {1, 2}, {3}}, nil},
+		{[][]uint64{{}, {0}, {1}, {2}, {3}}, nil},
+		// Declared dependencies on other transactions don't count
+		{[][]uint64{{}, {}, {}, {0, 2}, {2}}, []TxDependencyViolation{{1, 0}, {3, 1}, {4, 3}}},
+	} {
+		if have := CheckTxDependency(tt.deps, reads, writes); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: violations mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestParallelProcessorBadTxDependency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+
+	// Transfers of a single sender, each depending on the previous one
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, b *BlockGen) {
+		for nonce := uint64(0); nonce < 3; nonce++ {
+			tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x01}, big.NewInt(1), params.TxGas, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+		}
+	})
+
+	chain, err := NewParallelBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfig, gspec, nil, ethash.NewFaker(), vm.Config{ParallelEnable: true, ParallelSpeculativeProcesses: 4}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	withTxDependency := func(deps [][]uint64) *types.Block {
+		data, err := rlp.EncodeToBytes(types.BlockExtraData{TxDependency: deps})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		header := blocks[0].Header()
+		header.Extra = append(append(make([]byte, types.ExtraVanityLength), data...), make([]byte, types.ExtraSealLength)...)
+
+		return blocks[0].WithSeal(header)
+	}
+
+	process := func(block *types.Block) (types.Receipts, uint64, error) {
+		statedb, err := state.New(chain.Genesis().Root(), chain.stateCache, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		receipts, _, usedGas, err := chain.parallelProcessor.Process(block, statedb, chain.vmConfig, nil)
+
+		return receipts, usedGas, err
+	}
+
+	for i, tt := range []struct {
+		deps [][]uint64
+		want *BadTxDependencyError
+	}{
+		{[][]uint64{{}, {0}, {1}}, nil},
+		{[][]uint64{{}, {}, {0}}, &BadTxDependencyError{Violations: []TxDependencyViolation{{1, 0}, {2, 1}}}},
+		{[][]uint64{{}, {0}, {2}}, &BadTxDependencyError{Reason: "tx 2 depends on later tx 2"}},
+		// Dependencies not covering every transaction, like the state sync ones,
+		// are processed sequentially by the parallel processor itself
+		{[][]uint64{{}, {0}}, nil},
+	} {
+		block := withTxDependency(tt.deps)
+
+		var have *BadTxDependencyError
+
+		receipts, usedGas, err := process(block)
+		if err != nil && !errors.As(err, &have) {
+			t.Fatalf("test %d: failed to process block: %v", i, err)
+		}
+
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, have, tt.want)
+		}
+
+		if tt.want == nil && (len(receipts) != 3 || usedGas != 3*params.TxGas) {
+			t.Errorf("test %d: parallel processing mismatch: have %d receipts, %d gas", i, len(receipts), usedGas)
+		}
+
+		// The block is processed sequentially instead
+		receipts, _, usedGas, _, err = chain.ProcessBlock(block, chain.Genesis().Header())
+		if err != nil || len(receipts) != 3 || usedGas != 3*params.TxGas {
+			t.Errorf("test %d: processing mismatch: have %d receipts, %d gas (err %v)", i, len(receipts), usedGas, err)
+		}
+	}
+}
@@ -43,6 +43,7 @@ func (*devNull) Close() error                      { return nil }
 
 // journal is a rotating log of transactions with the aim of storing locally
 // created transactions to allow non-executed ones to survive node restarts.
+// Conditional transactions are stored along with their options.
 type journal struct {
 	path   string         // Filesystem path to store the transactions at
 	writer io.WriteCloser // Output stream to write new transactions into
@@ -100,7 +101,13 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 	for {
 		// Parse the next transaction and terminate on error
 		tx := new(types.Transaction)
-		if err = stream.Decode(tx); err != nil {
+
+		raw, err := stream.Raw()
+		if err == nil {
+			err = tx.DecodeConditionalRLP(raw)
+		}
+
+		if err != nil {
 			if err != io.EOF {
 				failure = err
 			}
@@ -130,7 +137,7 @@ func (journal *journal) insert(tx *types.Transaction) error {
 		return errNoActiveJournal
 	}
 
-	if err := rlp.Encode(journal.writer, tx); err != nil {
+	if err := tx.EncodeConditionalRLP(journal.writer); err != nil {
 		return err
 	}
 
@@ -158,7 +165,7 @@ func (journal *journal) rotate(all map[common.Address]types.Transactions) error
 
 	for _, txs := range all {
 		for _, tx := range txs {
-			if err = rlp.Encode(replacement, tx); err != nil {
+			if err = tx.EncodeConditionalRLP(replacement); err != nil {
 				replacement.Close()
 				return err
 			}
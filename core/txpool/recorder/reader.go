@@ -0,0 +1,137 @@
+package recorder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/golang/snappy"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// errHeadNotRecorded is returned if a recording doesn't cover the arrival of
+// the requested head.
+var errHeadNotRecorded = errors.New("head not recorded")
+
+// Reader reads the records of a recording in order.
+type Reader struct {
+	file   *os.File
+	stream *rlp.Stream
+}
+
+// Open opens the recording at path for reading.
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		file:   file,
+		stream: rlp.NewStream(snappy.NewReader(file), 0),
+	}, nil
+}
+
+// Next returns the next record, io.EOF once all of them were read.
+func (r *Reader) Next() (*Record, error) {
+	var dec recordRLP
+	if err := r.stream.Decode(&dec); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			// A recording cut short by a crash ends with a partial record
+			return nil, io.EOF
+		}
+
+		return nil, err
+	}
+
+	rec := &Record{
+		Kind:   dec.Kind,
+		Time:   time.UnixMilli(int64(dec.Time)),
+		Txs:    make(types.Transactions, 0, len(dec.Txs)),
+		Hashes: dec.Hashes,
+		Reason: dec.Reason,
+		Number: dec.Number,
+		Hash:   dec.Hash,
+	}
+
+	for i, enc := range dec.Txs {
+		tx := new(types.Transaction)
+		if err := tx.DecodeConditionalRLP(enc); err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+
+		rec.Txs = append(rec.Txs, tx)
+	}
+
+	return rec, nil
+}
+
+// Close closes the recording.
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
+
+// Mempool replays the recording at path up to the arrival of the head with
+// the given number, and returns the transactions which were pending at that
+// point, i.e. the ones available to build the block with that number.
+func Mempool(path string, number uint64) (types.Transactions, error) {
+	r, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var (
+		pending = make(map[common.Hash]*types.Transaction)
+		order   []common.Hash
+	)
+
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: block %d", errHeadNotRecorded, number)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch rec.Kind {
+		case KindAdd:
+			for _, tx := range rec.Txs {
+				if _, ok := pending[tx.Hash()]; !ok {
+					order = append(order, tx.Hash())
+				}
+
+				pending[tx.Hash()] = tx
+			}
+
+		case KindDrop:
+			for _, hash := range rec.Hashes {
+				delete(pending, hash)
+			}
+
+		case KindHead:
+			if rec.Number < number {
+				continue
+			}
+
+			txs := make(types.Transactions, 0, len(pending))
+
+			// Transactions dropped and added again are in order more than once
+			for _, hash := range order {
+				if tx, ok := pending[hash]; ok {
+					txs = append(txs, tx)
+					delete(pending, hash)
+				}
+			}
+
+			return txs, nil
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Package recorder records the transaction pool events and chain heads seen by
+// a node, so that the blocks it could have built can be replayed offline.
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Kinds of the recorded events.
+const (
+	KindAdd  = iota // Transactions entered the pending pool
+	KindDrop        // Transactions were dropped from the pool
+	KindHead        // A new chain head was imported
+)
+
+var (
+	recordedMeter = metrics.NewRegisteredMeter("txpool/recorder/records", nil)
+	failedMeter   = metrics.NewRegisteredMeter("txpool/recorder/failed", nil)
+)
+
+// txPool defines the methods needed from a transaction pool to record it.
+type txPool interface {
+	SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription
+	SubscribeDropTxsEvent(ch chan<- core.DropTxsEvent) event.Subscription
+}
+
+// blockChain defines the methods needed from a chain to record its heads.
+type blockChain interface {
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Record is a single recorded event.
+type Record struct {
+	Kind   uint8
+	Time   time.Time
+	Txs    types.Transactions // Added transactions
+	Hashes []common.Hash      // Dropped transactions
+	Reason string             // Drop reason
+	Number uint64             // Head number
+	Hash   common.Hash        // Head hash
+}
+
+// recordRLP is the encoding of a record. Added transactions are encoded along
+// with their conditional options, dropped ones by hash only.
+type recordRLP struct {
+	Kind   uint8
+	Time   uint64 // Unix milliseconds
+	Txs    []rlp.RawValue
+	Hashes []common.Hash
+	Reason string
+	Number uint64
+	Hash   common.Hash
+}
+
+// Recorder appends the pending transactions, the drops and the chain heads to
+// a snappy compressed stream of RLP records.
+type Recorder struct {
+	path  string
+	pool  txPool
+	chain blockChain
+
+	file   *os.File
+	writer *snappy.Writer
+
+	// The channels are unbuffered, so that the events sent by the same goroutine,
+	// like the pool announcing a replacement and the drop of the replaced
+	// transaction, are recorded in order.
+	txsCh   chan core.NewTxsEvent
+	dropCh  chan core.DropTxsEvent
+	headCh  chan core.ChainHeadEvent
+	txsSub  event.Subscription
+	dropSub event.Subscription
+	headSub event.Subscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a recorder appending to the file at path, which is created if it
+// doesn't exist yet.
+func New(path string, pool txPool, chain blockChain) *Recorder {
+	return &Recorder{
+		path:   path,
+		pool:   pool,
+		chain:  chain,
+		txsCh:  make(chan core.NewTxsEvent),
+		dropCh: make(chan core.DropTxsEvent),
+		headCh: make(chan core.ChainHeadEvent),
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start opens the recording and starts recording, implementing node.Lifecycle.
+func (r *Recorder) Start() error {
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open mempool recording: %w", err)
+	}
+
+	r.file, r.writer = file, snappy.NewBufferedWriter(file)
+
+	r.txsSub = r.pool.SubscribeNewTxsEvent(r.txsCh)
+	r.dropSub = r.pool.SubscribeDropTxsEvent(r.dropCh)
+	r.headSub = r.chain.SubscribeChainHeadEvent(r.headCh)
+
+	r.wg.Add(1)
+
+	go r.loop()
+
+	log.Info("Recording the transaction pool", "path", r.path)
+
+	return nil
+}
+
+// Stop stops recording and closes the recording, implementing node.Lifecycle.
+func (r *Recorder) Stop() error {
+	close(r.quit)
+	r.wg.Wait()
+
+	if err := r.writer.Close(); err != nil {
+		r.file.Close()
+		return err
+	}
+
+	return r.file.Close()
+}
+
+func (r *Recorder) loop() {
+	defer r.wg.Done()
+
+	defer r.txsSub.Unsubscribe()
+	defer r.dropSub.Unsubscribe()
+	defer r.headSub.Unsubscribe()
+
+	for {
+		var rec *Record
+
+		select {
+		case ev := <-r.txsCh:
+			rec = &Record{Kind: KindAdd, Txs: ev.Txs}
+
+		case ev := <-r.dropCh:
+			hashes := make([]common.Hash, len(ev.Txs))
+			for i, tx := range ev.Txs {
+				hashes[i] = tx.Hash()
+			}
+
+			rec = &Record{Kind: KindDrop, Hashes: hashes, Reason: ev.Reason}
+
+		case ev := <-r.headCh:
+			rec = &Record{Kind: KindHead, Number: ev.Block.NumberU64(), Hash: ev.Block.Hash()}
+
+		case <-r.txsSub.Err():
+			return
+		case <-r.dropSub.Err():
+			return
+		case <-r.headSub.Err():
+			return
+		case <-r.quit:
+			return
+		}
+
+		rec.Time = time.Now()
+
+		if err := r.write(rec); err != nil {
+			failedMeter.Mark(1)
+			log.Warn("Failed to record transaction pool event", "kind", rec.Kind, "err", err)
+
+			continue
+		}
+
+		recordedMeter.Mark(1)
+
+		// Flush on every head, so that at most a block worth of events is lost
+		// if the node crashes
+		if rec.Kind == KindHead {
+			if err := r.writer.Flush(); err != nil {
+				log.Warn("Failed to flush mempool recording", "err", err)
+			}
+		}
+	}
+}
+
+// write appends rec to the recording.
+func (r *Recorder) write(rec *Record) error {
+	enc := recordRLP{
+		Kind:   rec.Kind,
+		Time:   uint64(rec.Time.UnixMilli()),
+		Txs:    make([]rlp.RawValue, 0, len(rec.Txs)),
+		Hashes: rec.Hashes,
+		Reason: rec.Reason,
+		Number: rec.Number,
+		Hash:   rec.Hash,
+	}
+
+	for _, tx := range rec.Txs {
+		var buf bytes.Buffer
+		if err := tx.EncodeConditionalRLP(&buf); err != nil {
+			return err
+		}
+
+		enc.Txs = append(enc.Txs, buf.Bytes())
+	}
+
+	return rlp.Encode(r.writer, &enc)
+}
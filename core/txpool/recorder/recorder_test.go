@@ -0,0 +1,106 @@
+package recorder
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+type testPool struct {
+	txFeed   event.Feed
+	dropFeed event.Feed
+}
+
+func (p *testPool) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return p.txFeed.Subscribe(ch)
+}
+
+func (p *testPool) SubscribeDropTxsEvent(ch chan<- core.DropTxsEvent) event.Subscription {
+	return p.dropFeed.Subscribe(ch)
+}
+
+type testChain struct {
+	headFeed event.Feed
+}
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.headFeed.Subscribe(ch)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path  = filepath.Join(t.TempDir(), "mempool.rec")
+		pool  = new(testPool)
+		chain = new(testChain)
+		txs   = make(types.Transactions, 4)
+	)
+
+	for i := range txs {
+		txs[i] = types.NewTx(&types.LegacyTx{Nonce: uint64(i), Gas: 21000, GasPrice: big.NewInt(1)})
+	}
+
+	r := New(path, pool, chain)
+	if err := r.Start(); err != nil {
+		t.Fatalf("failed to start recorder: %v", err)
+	}
+	defer r.Stop()
+
+	head := func(number int64) {
+		chain.headFeed.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number)})})
+	}
+
+	pool.txFeed.Send(core.NewTxsEvent{Txs: txs[:2]})
+	pool.dropFeed.Send(core.DropTxsEvent{Txs: txs[:1], Reason: "replaced"})
+	head(1)
+	pool.txFeed.Send(core.NewTxsEvent{Txs: txs[2:]})
+	pool.dropFeed.Send(core.DropTxsEvent{Txs: txs[1:2], Reason: "stale"})
+	head(2)
+
+	// The recording is flushed on every head, wait until the last one is in
+	var err error
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err = Mempool(path, 2); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		t.Fatalf("failed to replay recording: %v", err)
+	}
+
+	tests := []struct {
+		number uint64
+		want   types.Transactions
+		err    error
+	}{
+		{1, txs[1:2], nil},
+		{2, txs[2:], nil},
+		{3, nil, errHeadNotRecorded},
+	}
+
+	for _, tt := range tests {
+		pending, err := Mempool(path, tt.number)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("block %d: error mismatch: have %v, want %v", tt.number, err, tt.err)
+		}
+
+		if len(pending) != len(tt.want) {
+			t.Fatalf("block %d: pending count mismatch: have %d, want %d", tt.number, len(pending), len(tt.want))
+		}
+
+		for i, tx := range pending {
+			if tx.Hash() != tt.want[i].Hash() {
+				t.Errorf("block %d: transaction %d mismatch: have %s, want %s", tt.number, i, tx.Hash(), tt.want[i].Hash())
+			}
+		}
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/common/tracing"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core"
@@ -59,6 +60,10 @@ const (
 	// more expensive to propagate; larger transactions also take more resources
 	// to validate whether they fit into the pool or not.
 	txMaxSize = 4 * txSlotSize // 128KB
+
+	// conditionalsCacheSize is the number of conditional transactions the pool
+	// remembers the options of after they leave it, to restore them on reorgs.
+	conditionalsCacheSize = 4096
 )
 
 var (
@@ -101,6 +106,22 @@ var (
 	// ErrOverdraft is returned if a transaction would cause the senders balance to go negative
 	// thus invalidating a potential large number of transactions.
 	ErrOverdraft = errors.New("transaction would cause overdraft")
+
+	// ErrConditionalOptions is returned if the options of a conditional transaction
+	// are not satisfied by the current head and state.
+	ErrConditionalOptions = errors.New("conditional options not satisfied")
+)
+
+// Reasons transactions are dropped from the pool for, as announced in the
+// DropTxsEvent.
+const (
+	DropStale       = "stale"       // Nonce already used on chain
+	DropUnpayable   = "unpayable"   // Too costly for the sender balance or the block gas limit
+	DropUnderpriced = "underpriced" // Below the pool price threshold, or evicted by a better paying transaction
+	DropReplaced    = "replaced"    // Replaced by a better paying transaction with the same nonce
+	DropExpired     = "expired"     // Queued for longer than the lifetime
+	DropOverflow    = "overflow"    // Over the account or global slot limits
+	DropConditional = "conditional" // Options not satisfied anymore
 )
 
 var (
@@ -270,6 +291,7 @@ type TxPool struct {
 	gasPriceUint *uint256.Int
 	gasPriceMu   sync.RWMutex
 	txFeed       event.Feed
+	dropFeed     event.Feed
 	scope        event.SubscriptionScope
 	signer       types.Signer
 	mu           sync.RWMutex
@@ -279,10 +301,13 @@ type TxPool struct {
 	eip1559  atomic.Bool // Fork indicator whether we are using EIP-1559 type transactions.
 	shanghai atomic.Bool // Fork indicator whether we are in the Shanghai stage.
 
-	currentState      *state.StateDB // Current state in the blockchain head
-	currentStateMutex sync.Mutex     // Mutex to protect currentState
-	pendingNonces     *noncer        // Pending state tracking virtual nonces
-	currentMaxGas     atomic.Uint64  // Current gas limit for transaction caps
+	currentState      *state.StateDB               // Current state in the blockchain head
+	currentStateMutex sync.Mutex                   // Mutex to protect currentState
+	pendingNonces     *noncer                      // Pending state tracking virtual nonces
+	currentMaxGas     atomic.Uint64                // Current gas limit for transaction caps
+	currentHead       atomic.Pointer[types.Header] // Current head of the blockchain
+
+	conditionals *lru.Cache[common.Hash, *types.OptionsAA4337] // Options of recent conditional transactions, restored on reorgs
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *journal    // Journal of local transaction to back up to disk
@@ -307,6 +332,8 @@ type TxPool struct {
 
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
 
+	drops []core.DropTxsEvent // Drops to announce once the pool lock is released
+
 	promoteTxCh chan struct{} // should be used only for tests
 }
 
@@ -339,6 +366,7 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain,
 		initDoneCh:      make(chan struct{}),
 		gasPrice:        new(big.Int).SetUint64(config.PriceLimit),
 		gasPriceUint:    uint256.NewInt(config.PriceLimit),
+		conditionals:    lru.NewCache[common.Hash, *types.OptionsAA4337](conditionalsCacheSize),
 	}
 
 	pool.locals = newAccountSet(pool.signer)
@@ -435,8 +463,7 @@ func (pool *TxPool) loop() {
 
 			var (
 				list     types.Transactions
-				tx       *types.Transaction
-				toRemove []common.Hash
+				toRemove types.Transactions
 			)
 
 			pool.mu.RLock()
@@ -449,9 +476,7 @@ func (pool *TxPool) loop() {
 				// Any non-locals old enough should be removed
 				if now.Sub(pool.beats[addr]) > pool.config.Lifetime {
 					list = pool.queue[addr].Flatten()
-					for _, tx = range list {
-						toRemove = append(toRemove, tx.Hash())
-					}
+					toRemove = append(toRemove, list...)
 
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
@@ -462,13 +487,16 @@ func (pool *TxPool) loop() {
 			if len(toRemove) > 0 {
 				pool.mu.Lock()
 
-				var hash common.Hash
-
-				for _, hash = range toRemove {
-					pool.removeTx(hash, true)
+				for _, tx := range toRemove {
+					pool.removeTx(tx.Hash(), true)
 				}
 
+				pool.queueDropEvent(DropExpired, toRemove)
+				drops := pool.takeDropEvents()
+
 				pool.mu.Unlock()
+
+				pool.sendDropEvents(drops)
 			}
 
 		// Handle local transaction journal rotation
@@ -506,6 +534,41 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subsc
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeDropTxsEvent registers a subscription of DropTxsEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeDropTxsEvent(ch chan<- core.DropTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
+// queueDropEvent queues the announcement of transactions dropped for reason.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) queueDropEvent(reason string, txs types.Transactions) {
+	if len(txs) == 0 {
+		return
+	}
+
+	pool.drops = append(pool.drops, core.DropTxsEvent{Txs: txs, Reason: reason})
+}
+
+// takeDropEvents returns the queued drop announcements and clears them.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) takeDropEvents() []core.DropTxsEvent {
+	drops := pool.drops
+	pool.drops = nil
+
+	return drops
+}
+
+// sendDropEvents announces the given drops. It must be called without holding
+// the pool lock, as it blocks until all subscribers take the events.
+func (pool *TxPool) sendDropEvents(drops []core.DropTxsEvent) {
+	for _, drop := range drops {
+		pool.dropFeed.Send(drop)
+	}
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.gasPriceMu.RLock()
@@ -539,7 +602,6 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 	// if the min miner fee increased, remove transactions below the new threshold
 	if price.Cmp(old) > 0 {
 		pool.mu.Lock()
-		defer pool.mu.Unlock()
 
 		// pool.priced is sorted by GasFeeCap, so we have to iterate through pool.all instead
 		drop := pool.all.RemotesBelowTip(price)
@@ -548,6 +610,13 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 		}
 
 		pool.priced.Removed(len(drop))
+
+		pool.queueDropEvent(DropUnderpriced, drop)
+		drops := pool.takeDropEvents()
+
+		pool.mu.Unlock()
+
+		pool.sendDropEvents(drops)
 	}
 
 	log.Info("Transaction pool price threshold updated", "price", price)
@@ -749,51 +818,16 @@ func (pool *TxPool) validateTxBasics(tx *types.Transaction, local bool) error {
 	pool.currentStateMutex.Lock()
 	defer pool.currentStateMutex.Unlock()
 
-	// Accept only legacy transactions until EIP-2718/2930 activates.
-	if !pool.eip2718.Load() && tx.Type() != types.LegacyTxType {
-		return core.ErrTxTypeNotSupported
-	}
-
-	// Reject dynamic fee transactions until EIP-1559 activates.
-	if !pool.eip1559.Load() && tx.Type() == types.DynamicFeeTxType {
-		return core.ErrTxTypeNotSupported
-	}
-
-	// Reject transactions over defined size to prevent DOS attacks
-	if tx.Size() > txMaxSize {
-		return ErrOversizedData
-	}
-	// Check whether the init code size has been exceeded.
-	if pool.shanghai.Load() && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
-		return fmt.Errorf("%w: code size %v limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
-	}
-	// Transactions can't be negative. This may never happen using RLP decoded
-	// transactions but may occur if you create a transaction using the RPC.
-	if tx.Value().Sign() < 0 {
-		return ErrNegativeValue
-	}
-
-	// Ensure the transaction doesn't exceed the current block limit gas.
-	if pool.currentMaxGas.Load() < tx.Gas() {
-		return ErrGasLimit
-	}
-
-	// Sanity check for extremely large numbers
-	gasFeeCap := tx.GasFeeCapRef()
-	if gasFeeCap.BitLen() > 256 {
-		return core.ErrFeeCapVeryHigh
-	}
-
-	// do NOT use uint256 here. results vs *big.Int are different
-	gasTipCap := tx.GasTipCapRef()
-	if gasTipCap.BitLen() > 256 {
-		return core.ErrTipVeryHigh
+	opts := &ValidationOptions{
+		EIP2718:  pool.eip2718.Load(),
+		EIP1559:  pool.eip1559.Load(),
+		Istanbul: pool.istanbul.Load(),
+		Shanghai: pool.shanghai.Load(),
+		MaxGas:   pool.currentMaxGas.Load(),
 	}
 
-	// Ensure gasFeeCap is greater than or equal to gasTipCap.
-	gasTipCapU, _ := uint256.FromBig(gasTipCap)
-	if tx.GasFeeCapUIntLt(gasTipCapU) {
-		return core.ErrTipAboveFeeCap
+	if err := ValidateTransaction(tx, opts); err != nil {
+		return err
 	}
 
 	// Make sure the transaction is signed properly.
@@ -841,15 +875,6 @@ func (pool *TxPool) validateTxBasics(tx *types.Transaction, local bool) error {
 			return ErrOverdraft
 		}
 	}
-	// Ensure the transaction has more gas than the basic tx fee.
-	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, pool.istanbul.Load(), pool.shanghai.Load())
-	if err != nil {
-		return err
-	}
-
-	if tx.Gas() < intrGas {
-		return core.ErrIntrinsicGas
-	}
 
 	return nil
 }
@@ -887,10 +912,32 @@ func (pool *TxPool) validateTx(tx *types.Transaction, _ bool) error {
 			return ErrOverdraft
 		}
 	}
+	// Ensure conditional transactions may still be included on top of the head
+	if options := tx.GetOptions(); options != nil {
+		if err := validateOptions(pool.currentHead.Load(), pool.currentState, options); err != nil {
+			return fmt.Errorf("%w: %v", ErrConditionalOptions, err)
+		}
+	}
 
 	return nil
 }
 
+// validateOptions checks the options of a conditional transaction against the
+// given head and its state.
+func validateOptions(head *types.Header, statedb *state.StateDB, options *types.OptionsAA4337) error {
+	if head != nil {
+		if err := head.ValidateBlockNumberOptions4337(options.BlockNumberMin, options.BlockNumberMax); err != nil {
+			return err
+		}
+
+		if err := head.ValidateTimestampOptions4337(options.TimestampMin, options.TimestampMax); err != nil {
+			return err
+		}
+	}
+
+	return statedb.ValidateKnownAccounts(options.KnownAccounts)
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -919,6 +966,10 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		return false, err
 	}
 
+	if options := tx.GetOptions(); options != nil {
+		pool.conditionals.Add(hash, options)
+	}
+
 	// already validated by this point
 	from, _ := types.Sender(pool.signer, tx)
 
@@ -983,6 +1034,8 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			dropped := pool.removeTx(tx.Hash(), false)
 			pool.changesSinceReorg += dropped
 		}
+
+		pool.queueDropEvent(DropUnderpriced, drop)
 	}
 
 	// Try to replace an existing transaction in the pending pool
@@ -1004,6 +1057,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.queueDropEvent(DropReplaced, types.Transactions{old})
 		}
 
 		pool.all.Add(tx, isLocal)
@@ -1079,6 +1133,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.queueDropEvent(DropReplaced, types.Transactions{old})
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -1147,6 +1202,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.queueDropEvent(DropReplaced, types.Transactions{tx})
 
 		return false
 	}
@@ -1156,6 +1212,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.queueDropEvent(DropReplaced, types.Transactions{old})
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -1770,8 +1827,12 @@ func (pool *TxPool) runReorg(ctx context.Context, done chan struct{}, reset *txp
 		dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 		pool.changesSinceReorg = 0 // Reset change counter
 
+		drops := pool.takeDropEvents()
+
 		pool.mu.Unlock()
 
+		pool.sendDropEvents(drops)
+
 		// Notify subsystems for newly added transactions
 		tracing.ElapsedTime(ctx, span, "13 notify about new transactions", func(_ context.Context, _ trace.Span) {
 			for _, tx := range promoted {
@@ -1799,6 +1860,33 @@ func (pool *TxPool) runReorg(ctx context.Context, done chan struct{}, reset *txp
 	})
 }
 
+// restoreOptions puts back the options of the conditional transactions among
+// txs. Transactions taken from blocks are shared with the chain, so they're
+// copied before being given their options.
+func (pool *TxPool) restoreOptions(txs types.Transactions) types.Transactions {
+	for i, tx := range txs {
+		options, ok := pool.conditionals.Get(tx.Hash())
+		if !ok {
+			continue
+		}
+
+		enc, err := tx.MarshalBinary()
+		if err != nil {
+			continue
+		}
+
+		cpy := new(types.Transaction)
+		if err := cpy.UnmarshalBinary(enc); err != nil {
+			continue
+		}
+
+		cpy.PutOptions(options)
+		txs[i] = cpy
+	}
+
+	return txs
+}
+
 // reset retrieves the current state of the blockchain and ensures the content
 // of the transaction pool is valid with regard to the chain state.
 func (pool *TxPool) reset(oldHead, newHead *types.Header) {
@@ -1889,9 +1977,12 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
 	pool.currentMaxGas.Store(newHead.GasLimit)
+	pool.currentHead.Store(newHead)
 
-	// Inject any transactions discarded due to reorgs
+	// Inject any transactions discarded due to reorgs, conditional ones along
+	// with their options to have them validated against the new head
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
+	reinject = pool.restoreOptions(reinject)
 	core.SenderCacher.Recover(pool.signer, reinject)
 	pool.addTxsLocked(reinject, false)
 
@@ -1945,6 +2036,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		}
 
 		log.Trace("Removed old queued transactions", "count", forwardsLen)
+		pool.queueDropEvent(DropStale, forwards)
 
 		// Drop all transactions that are too costly (low balance or out of gas)
 		balance.SetFromBig(pool.currentState.GetBalance(addr))
@@ -1959,6 +2051,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 
 		log.Trace("Removed unpayable queued transactions", "count", dropsLen)
 		queuedNofundsMeter.Mark(int64(dropsLen))
+		pool.queueDropEvent(DropUnpayable, drops)
 
 		// Gather all executable transactions and promote them
 		readies = list.Ready(pool.pendingNonces.get(addr))
@@ -1987,6 +2080,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			}
 
 			queuedRateLimitMeter.Mark(int64(capsLen))
+			pool.queueDropEvent(DropOverflow, caps)
 		}
 
 		// Mark all the items dropped as removed
@@ -2109,6 +2203,7 @@ func (pool *TxPool) truncatePending() {
 					}
 
 					pool.priced.Removed(capsLen)
+					pool.queueDropEvent(DropOverflow, caps)
 
 					pendingGauge.Dec(int64(capsLen))
 
@@ -2150,6 +2245,7 @@ func (pool *TxPool) truncatePending() {
 				}
 
 				pool.priced.Removed(capsLen)
+				pool.queueDropEvent(DropOverflow, caps)
 
 				pendingGauge.Dec(int64(capsLen))
 
@@ -2220,6 +2316,8 @@ func (pool *TxPool) truncateQueue() {
 				pool.removeTx(tx.Hash(), true)
 			}
 
+			pool.queueDropEvent(DropOverflow, listFlatten)
+
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
 
@@ -2234,6 +2332,7 @@ func (pool *TxPool) truncateQueue() {
 		txs = listFlatten
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true)
+			pool.queueDropEvent(DropOverflow, txs[i:i+1])
 
 			drop--
 
@@ -2281,6 +2380,8 @@ func (pool *TxPool) demoteUnexecutables() {
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 
+		pool.queueDropEvent(DropStale, olds)
+
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		balance.SetFromBig(pool.currentState.GetBalance(addr))
 		drops, invalids := list.Filter(balance, pool.currentMaxGas.Load())
@@ -2296,6 +2397,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		}
 
 		pendingNofundsMeter.Mark(int64(dropsLen))
+		pool.queueDropEvent(DropUnpayable, drops)
 
 		for _, tx := range invalids {
 			hash = tx.Hash()
@@ -2315,6 +2417,8 @@ func (pool *TxPool) demoteUnexecutables() {
 			log.Trace("Removed invalid conditional transaction", "hash", hash)
 		}
 
+		pool.queueDropEvent(DropConditional, txConditionalsRemoved)
+
 		pendingGauge.Dec(int64(oldsLen + dropsLen + invalidsLen + len(txConditionalsRemoved)))
 
 		if pool.locals.contains(addr) {
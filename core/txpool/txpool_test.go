@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"math/rand"
 	"os"
@@ -2774,6 +2775,58 @@ func testJournaling(t *testing.T, nolocals bool) {
 	pool.Stop()
 }
 
+// Tests that the options of journaled conditional transactions survive restarts.
+func TestJournalingConditional(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.CreateTemp("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary journal: %v", err)
+	}
+
+	journal := file.Name()
+	defer os.Remove(journal)
+
+	file.Close()
+	os.Remove(journal)
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Journal = journal
+	config.Rejournal = time.Second
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	timestamp := uint64(math.MaxUint64)
+
+	tx := pricedTransaction(0, 100000, big.NewInt(1), key)
+	tx.PutOptions(&types.OptionsAA4337{BlockNumberMax: big.NewInt(100), TimestampMax: &timestamp})
+
+	if err := pool.AddLocal(tx); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+
+	pool.Stop()
+
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	restored := pool.Get(tx.Hash())
+	if restored == nil {
+		t.Fatalf("conditional transaction not restored")
+	}
+
+	options := restored.GetOptions()
+	if options == nil || options.BlockNumberMax.Cmp(big.NewInt(100)) != 0 || options.TimestampMax == nil || *options.TimestampMax != timestamp {
+		t.Fatalf("conditional options mismatch: have %+v", options)
+	}
+}
+
 // TestStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestStatusCheck(t *testing.T) {
@@ -4891,3 +4944,58 @@ func BenchmarkMultiAccountBatchInsert(b *testing.B) {
 		pool.AddRemotesSync([]*types.Transaction{tx})
 	}
 }
+
+// Tests that transactions leaving the pool are announced along with the reason
+// they were dropped for.
+func TestDropTxsEvent(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Stop()
+
+	drops := make(chan core.DropTxsEvent, 8)
+
+	sub := pool.SubscribeDropTxsEvent(drops)
+	defer sub.Unsubscribe()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000000))
+
+	var (
+		original    = pricedTransaction(0, 100000, big.NewInt(1), key)
+		replacement = pricedTransaction(0, 100000, big.NewInt(2), key)
+	)
+
+	if err := pool.addRemoteSync(original); err != nil {
+		t.Fatalf("failed to add original transaction: %v", err)
+	}
+
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to add replacement transaction: %v", err)
+	}
+
+	// The replacement is included, which makes it stale on the next reset
+	testSetNonce(pool, account, 1)
+	<-pool.requestReset(nil, nil)
+
+	for i, want := range []struct {
+		tx     *types.Transaction
+		reason string
+	}{
+		{original, DropReplaced},
+		{replacement, DropStale},
+	} {
+		select {
+		case ev := <-drops:
+			if len(ev.Txs) != 1 || ev.Txs[0].Hash() != want.tx.Hash() {
+				t.Errorf("drop %d: transactions mismatch: have %v, want %s", i, ev.Txs, want.tx.Hash())
+			}
+
+			if ev.Reason != want.reason {
+				t.Errorf("drop %d: reason mismatch: have %s, want %s", i, ev.Reason, want.reason)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("drop %d: event not fired", i)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package txpool
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ValidationOptions are the rules in force for the block transactions are
+// validated for.
+type ValidationOptions struct {
+	EIP2718  bool   // Whether typed transactions are accepted
+	EIP1559  bool   // Whether dynamic fee transactions are accepted
+	Istanbul bool   // Whether the Istanbul calldata gas costs apply
+	Shanghai bool   // Whether the init code size is limited
+	MaxGas   uint64 // Gas limit of the block
+}
+
+// NewValidationOptions returns the rules in force for the block following head.
+func NewValidationOptions(config *params.ChainConfig, head *types.Header) *ValidationOptions {
+	next := new(big.Int).Add(head.Number, big.NewInt(1))
+
+	return &ValidationOptions{
+		EIP2718:  config.IsBerlin(next),
+		EIP1559:  config.IsLondon(next),
+		Istanbul: config.IsIstanbul(next),
+		Shanghai: config.IsShanghai(uint64(time.Now().Unix())),
+		MaxGas:   head.GasLimit,
+	}
+}
+
+// ValidateTransaction checks whether a transaction is valid according to the
+// consensus rules and the size limit of the pool, without looking at the state
+// it would be executed on. The pools other than TxPool apply it too.
+func ValidateTransaction(tx *types.Transaction, opts *ValidationOptions) error {
+	// Accept only legacy transactions until EIP-2718/2930 activates.
+	if !opts.EIP2718 && tx.Type() != types.LegacyTxType {
+		return core.ErrTxTypeNotSupported
+	}
+
+	// Reject dynamic fee transactions until EIP-1559 activates.
+	if !opts.EIP1559 && tx.Type() == types.DynamicFeeTxType {
+		return core.ErrTxTypeNotSupported
+	}
+
+	// Reject transactions over defined size to prevent DOS attacks
+	if tx.Size() > txMaxSize {
+		return ErrOversizedData
+	}
+	// Check whether the init code size has been exceeded.
+	if opts.Shanghai && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
+		return fmt.Errorf("%w: code size %v limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
+	}
+	// Transactions can't be negative. This may never happen using RLP decoded
+	// transactions but may occur if you create a transaction using the RPC.
+	if tx.Value().Sign() < 0 {
+		return ErrNegativeValue
+	}
+
+	// Ensure the transaction doesn't exceed the current block limit gas.
+	if opts.MaxGas < tx.Gas() {
+		return ErrGasLimit
+	}
+
+	// Sanity check for extremely large numbers
+	if tx.GasFeeCapRef().BitLen() > 256 {
+		return core.ErrFeeCapVeryHigh
+	}
+
+	// do NOT use uint256 here. results vs *big.Int are different
+	gasTipCap := tx.GasTipCapRef()
+	if gasTipCap.BitLen() > 256 {
+		return core.ErrTipVeryHigh
+	}
+
+	// Ensure gasFeeCap is greater than or equal to gasTipCap.
+	gasTipCapU, _ := uint256.FromBig(gasTipCap)
+	if tx.GasFeeCapUIntLt(gasTipCapU) {
+		return core.ErrTipAboveFeeCap
+	}
+
+	// Ensure the transaction has more gas than the basic tx fee.
+	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, opts.Istanbul, opts.Shanghai)
+	if err != nil {
+		return err
+	}
+
+	if tx.Gas() < intrGas {
+		return core.ErrIntrinsicGas
+	}
+
+	return nil
+}
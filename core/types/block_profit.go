@@ -0,0 +1,126 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// BlockProfit is the revenue breakdown of a block built by the node, which
+// makes its value comparable to the one of blocks offered by external builders.
+type BlockProfit struct {
+	Number        uint64
+	Hash          common.Hash
+	Coinbase      common.Address
+	BurntContract common.Address // Contract credited with the base fees, empty before London
+	Txs           []*TxProfit
+	StateSyncs    uint64 // State sync events committed by system calls
+	StateSyncGas  uint64 // Gas used by the state sync system calls, not charged to anyone
+}
+
+// TxProfit is the share of a single transaction in a block profit.
+type TxProfit struct {
+	Hash     common.Hash
+	GasUsed  uint64
+	Tip      *big.Int // Priority fee credited to the coinbase
+	Transfer *big.Int // Rest of the coinbase balance change, such as direct payments, internal calls included
+	Burnt    *big.Int // Base fee credited to the burnt contract
+}
+
+// NewBlockProfit computes the revenue breakdown to coinbase of the block with
+// the given header, out of its transactions and receipts, which have to have
+// the same order. The coinbase is the fee recipient the block was built for,
+// as bor headers don't carry it. The balances are the ones of the coinbase
+// before the first transaction and after each one: the tips are worked out of
+// the gas used and the rest of the balance changes are the transfers, so the
+// ones made by internal calls are accounted for too, except for the spending
+// of the coinbase on its own transactions. The chain config is only
+// used to resolve the burnt contract and may be nil.
+func NewBlockProfit(config *params.ChainConfig, header *Header, coinbase common.Address, txs Transactions, receipts []*Receipt, balances []*big.Int, stateSyncs []*StateSyncData) *BlockProfit {
+	profit := &BlockProfit{
+		Number:   header.Number.Uint64(),
+		Hash:     header.Hash(),
+		Coinbase: coinbase,
+		Txs:      make([]*TxProfit, len(txs)),
+	}
+
+	if header.BaseFee != nil && config != nil && config.Bor != nil && len(config.Bor.BurntContract) > 0 {
+		profit.BurntContract = common.HexToAddress(config.Bor.CalculateBurntContract(profit.Number))
+	}
+
+	for i, tx := range txs {
+		var (
+			receipt = receipts[i]
+			gasUsed = new(big.Int).SetUint64(receipt.GasUsed)
+		)
+
+		txProfit := &TxProfit{
+			Hash:     tx.Hash(),
+			GasUsed:  receipt.GasUsed,
+			Tip:      new(big.Int).Mul(tx.EffectiveGasTipValue(header.BaseFee), gasUsed),
+			Transfer: new(big.Int),
+			Burnt:    new(big.Int),
+		}
+
+		// The coinbase spending on its own transactions isn't a transfer
+		if len(balances) > i+1 && !sentBy(tx, coinbase) {
+			txProfit.Transfer.Sub(balances[i+1], balances[i])
+			txProfit.Transfer.Sub(txProfit.Transfer, txProfit.Tip)
+		}
+
+		if header.BaseFee != nil {
+			txProfit.Burnt.Mul(gasUsed, header.BaseFee)
+		}
+
+		profit.Txs[i] = txProfit
+	}
+
+	for _, stateSync := range stateSyncs {
+		profit.StateSyncs++
+		profit.StateSyncGas += stateSync.Gas
+	}
+
+	return profit
+}
+
+// sentBy reports whether the transaction is sent by the given account.
+func sentBy(tx *Transaction, account common.Address) bool {
+	from, err := Sender(LatestSignerForChainID(tx.ChainId()), tx)
+	return err == nil && from == account
+}
+
+// Tips returns the priority fees credited to the coinbase.
+func (p *BlockProfit) Tips() *big.Int {
+	tips := new(big.Int)
+	for _, tx := range p.Txs {
+		tips.Add(tips, tx.Tip)
+	}
+
+	return tips
+}
+
+// Transfers returns the value transferred to the coinbase by the transactions.
+func (p *BlockProfit) Transfers() *big.Int {
+	transfers := new(big.Int)
+	for _, tx := range p.Txs {
+		transfers.Add(transfers, tx.Transfer)
+	}
+
+	return transfers
+}
+
+// Burnt returns the base fees credited to the burnt contract.
+func (p *BlockProfit) Burnt() *big.Int {
+	burnt := new(big.Int)
+	for _, tx := range p.Txs {
+		burnt.Add(burnt, tx.Burnt)
+	}
+
+	return burnt
+}
+
+// Value returns the revenue of the coinbase: the tips and the transfers.
+func (p *BlockProfit) Value() *big.Int {
+	return new(big.Int).Add(p.Tips(), p.Transfers())
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BuilderAuction is the record of a sealed-bid auction run by the in-turn
+// proposer among external block builders, kept to audit disputes.
+type BuilderAuction struct {
+	Number     uint64
+	ParentHash common.Hash
+	Deadline   uint64               // Unix time in milliseconds the auction closed at
+	Bids       []*BuilderAuctionBid // Valid bids received before the deadline, in arrival order
+	Winner     common.Hash          // Hash of the bid whose body was used, empty if the local block was kept
+	Reason     string               // Why the best bid was rejected, if it was
+	BlockHash  common.Hash          // Hash of the sealed block, once the winning body is written to the chain
+}
+
+// BuilderAuctionBid is a single sealed bid received in a builder auction. The
+// bid commits to the transactions of the block through their root, which is
+// only revealed if the bid wins.
+type BuilderAuctionBid struct {
+	Time    uint64 // Unix time in milliseconds the bid was received at
+	Builder common.Address
+	Value   *big.Int
+	TxHash  common.Hash // Transaction root of the offered block
+	BidHash common.Hash
+}
+
+// WinningBid returns the bid which won the auction, nil if there's none.
+func (a *BuilderAuction) WinningBid() *BuilderAuctionBid {
+	if a.Winner == (common.Hash{}) {
+		return nil
+	}
+
+	for _, bid := range a.Bids {
+		if bid.BidHash == a.Winner {
+			return bid
+		}
+	}
+
+	return nil
+}
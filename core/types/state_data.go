@@ -8,4 +8,5 @@ type StateSyncData struct {
 	Contract common.Address
 	Data     string
 	TxHash   common.Hash
+	Gas      uint64 // Gas used committing the state, not charged to the block
 }
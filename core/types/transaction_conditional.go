@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 type KnownAccounts map[common.Address]*Value
@@ -144,3 +146,60 @@ func (ka KnownAccounts) ValidateLength() error {
 
 	return nil
 }
+
+// conditionalTxRLP is the encoding of a transaction along with its conditional
+// options. The options are JSON encoded as RLP can't represent known accounts.
+type conditionalTxRLP struct {
+	Tx      []byte
+	Options []byte
+}
+
+// EncodeConditionalRLP writes tx to w along with its conditional options. A
+// transaction without options is written in its regular RLP encoding.
+func (tx *Transaction) EncodeConditionalRLP(w io.Writer) error {
+	options := tx.GetOptions()
+	if options == nil {
+		return rlp.Encode(w, tx)
+	}
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	return rlp.Encode(w, &conditionalTxRLP{Tx: enc, Options: opts})
+}
+
+// DecodeConditionalRLP decodes a transaction written by EncodeConditionalRLP,
+// restoring its conditional options if it has any.
+func (tx *Transaction) DecodeConditionalRLP(b []byte) error {
+	// Legacy transactions are lists too, but of nine items
+	if kind, content, _, err := rlp.Split(b); err == nil && kind == rlp.List {
+		if n, err := rlp.CountValues(content); err == nil && n == 2 {
+			var dec conditionalTxRLP
+			if err := rlp.DecodeBytes(b, &dec); err != nil {
+				return err
+			}
+
+			if err := tx.UnmarshalBinary(dec.Tx); err != nil {
+				return err
+			}
+
+			options := new(OptionsAA4337)
+			if err := json.Unmarshal(dec.Options, options); err != nil {
+				return err
+			}
+
+			tx.PutOptions(options)
+
+			return nil
+		}
+	}
+
+	return rlp.DecodeBytes(b, tx)
+}
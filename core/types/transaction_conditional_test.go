@@ -1,12 +1,14 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestKnownAccounts(t *testing.T) {
@@ -29,3 +31,36 @@ func TestKnownAccounts(t *testing.T) {
 
 	require.Equal(t, expected, accs)
 }
+
+func TestConditionalRLP(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _    = crypto.GenerateKey()
+		signer    = LatestSignerForChainID(common.Big1)
+		timestamp = uint64(1000)
+	)
+
+	legacy := MustSignNewTx(key, signer, &LegacyTx{Nonce: 1, Gas: 21000, GasPrice: common.Big1})
+	dynamic := MustSignNewTx(key, signer, &DynamicFeeTx{ChainID: common.Big1, Nonce: 2, Gas: 21000, GasFeeCap: common.Big1, GasTipCap: common.Big1})
+
+	options := &OptionsAA4337{
+		KnownAccounts:  KnownAccounts{common.Address{0x01}: SingleFromHex("0x000000000000000000000000313aadca1750caadc7bcb26ff08175c95dcf8e38")},
+		BlockNumberMin: common.Big1,
+		TimestampMax:   &timestamp,
+	}
+
+	conditional := MustSignNewTx(key, signer, &DynamicFeeTx{ChainID: common.Big1, Nonce: 3, Gas: 21000, GasFeeCap: common.Big1, GasTipCap: common.Big1})
+	conditional.PutOptions(options)
+
+	for _, tx := range []*Transaction{legacy, dynamic, conditional} {
+		var buf bytes.Buffer
+		require.NoError(t, tx.EncodeConditionalRLP(&buf))
+
+		dec := new(Transaction)
+		require.NoError(t, dec.DecodeConditionalRLP(buf.Bytes()))
+
+		require.Equal(t, tx.Hash(), dec.Hash())
+		require.Equal(t, tx.GetOptions(), dec.GetOptions())
+	}
+}
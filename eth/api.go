@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/blockstm"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -137,6 +138,130 @@ func (api *MinerAPI) SetRecommitInterval(interval int) {
 	api.e.Miner().SetRecommitInterval(time.Duration(interval) * time.Millisecond)
 }
 
+// BuilderAuctionBid is a bid received in a builder auction.
+type BuilderAuctionBid struct {
+	Time    hexutil.Uint64 `json:"time"`
+	Builder common.Address `json:"builder"`
+	Value   *hexutil.Big   `json:"value"`
+	TxHash  common.Hash    `json:"txHash"`
+	BidHash common.Hash    `json:"bidHash"`
+}
+
+// BuilderAuction is the record of an auction run among external builders for
+// a block this node proposed. Times are Unix milliseconds.
+type BuilderAuction struct {
+	Number     hexutil.Uint64       `json:"number"`
+	ParentHash common.Hash          `json:"parentHash"`
+	Deadline   hexutil.Uint64       `json:"deadline"`
+	Bids       []*BuilderAuctionBid `json:"bids"`
+	Winner     *common.Hash         `json:"winner"`
+	Reason     string               `json:"reason,omitempty"`
+	BlockHash  *common.Hash         `json:"blockHash"`
+}
+
+// GetBuilderAuctions returns the builder auctions run for the block with the
+// given number, one for each parent the node proposed the block on. Only the
+// last auction run as the node refreshes its work is kept.
+func (api *MinerAPI) GetBuilderAuctions(number hexutil.Uint64) []*BuilderAuction {
+	records := rawdb.ReadBuilderAuctions(api.e.ChainDb(), uint64(number))
+	auctions := make([]*BuilderAuction, 0, len(records))
+
+	for _, record := range records {
+		auction := &BuilderAuction{
+			Number:     hexutil.Uint64(record.Number),
+			ParentHash: record.ParentHash,
+			Deadline:   hexutil.Uint64(record.Deadline),
+			Bids:       make([]*BuilderAuctionBid, 0, len(record.Bids)),
+			Reason:     record.Reason,
+		}
+
+		for _, bid := range record.Bids {
+			auction.Bids = append(auction.Bids, &BuilderAuctionBid{
+				Time:    hexutil.Uint64(bid.Time),
+				Builder: bid.Builder,
+				Value:   (*hexutil.Big)(bid.Value),
+				TxHash:  bid.TxHash,
+				BidHash: bid.BidHash,
+			})
+		}
+
+		if record.Winner != (common.Hash{}) {
+			winner := record.Winner
+			auction.Winner = &winner
+		}
+
+		if record.BlockHash != (common.Hash{}) {
+			hash := record.BlockHash
+			auction.BlockHash = &hash
+		}
+
+		auctions = append(auctions, auction)
+	}
+
+	return auctions
+}
+
+// TxProfit is the share of a transaction in the revenue of a block.
+type TxProfit struct {
+	Hash     common.Hash    `json:"hash"`
+	GasUsed  hexutil.Uint64 `json:"gasUsed"`
+	Tip      *hexutil.Big   `json:"tip"`
+	Transfer *hexutil.Big   `json:"transfer"`
+	Burnt    *hexutil.Big   `json:"burnt"`
+}
+
+// BlockProfit is the revenue breakdown of a block built by this node. The
+// value is the revenue of the coinbase, the tips plus the transfers, as bid
+// by external builders.
+type BlockProfit struct {
+	Number        hexutil.Uint64 `json:"number"`
+	Hash          common.Hash    `json:"hash"`
+	Coinbase      common.Address `json:"coinbase"`
+	BurntContract common.Address `json:"burntContract"`
+	Txs           []*TxProfit    `json:"transactions"`
+	Tips          *hexutil.Big   `json:"tips"`
+	Transfers     *hexutil.Big   `json:"transfers"`
+	Burnt         *hexutil.Big   `json:"burnt"`
+	Value         *hexutil.Big   `json:"value"`
+	StateSyncs    hexutil.Uint64 `json:"stateSyncs"`
+	StateSyncGas  hexutil.Uint64 `json:"stateSyncGas"`
+}
+
+// GetBlockProfit returns the revenue breakdown of the block with the given
+// hash, nil if it wasn't built by this node.
+func (api *MinerAPI) GetBlockProfit(hash common.Hash) *BlockProfit {
+	profit := rawdb.ReadBlockProfit(api.e.ChainDb(), hash)
+	if profit == nil {
+		return nil
+	}
+
+	result := &BlockProfit{
+		Number:        hexutil.Uint64(profit.Number),
+		Hash:          profit.Hash,
+		Coinbase:      profit.Coinbase,
+		BurntContract: profit.BurntContract,
+		Txs:           make([]*TxProfit, 0, len(profit.Txs)),
+		Tips:          (*hexutil.Big)(profit.Tips()),
+		Transfers:     (*hexutil.Big)(profit.Transfers()),
+		Burnt:         (*hexutil.Big)(profit.Burnt()),
+		Value:         (*hexutil.Big)(profit.Value()),
+		StateSyncs:    hexutil.Uint64(profit.StateSyncs),
+		StateSyncGas:  hexutil.Uint64(profit.StateSyncGas),
+	}
+
+	for _, tx := range profit.Txs {
+		result.Txs = append(result.Txs, &TxProfit{
+			Hash:     tx.Hash,
+			GasUsed:  hexutil.Uint64(tx.GasUsed),
+			Tip:      (*hexutil.Big)(tx.Tip),
+			Transfer: (*hexutil.Big)(tx.Transfer),
+			Burnt:    (*hexutil.Big)(tx.Burnt),
+		})
+	}
+
+	return result
+}
+
 // AdminAPI is the collection of Ethereum full node related APIs for node
 // administration.
 type AdminAPI struct {
@@ -284,7 +409,7 @@ func (api *DebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error) {
 	var header *types.Header
 	if blockNr == rpc.LatestBlockNumber {
 		header = api.eth.blockchain.CurrentBlock()
-	} else if blockNr == rpc.FinalizedBlockNumber {
+	} else if isFinalizedTag(api.eth, blockNr) {
 		finalBlockNumber, err := getFinalizedBlockNumber(api.eth)
 		if err != nil {
 			return state.Dump{}, fmt.Errorf("finalized block not found")
@@ -389,7 +514,7 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 			var header *types.Header
 			if number == rpc.LatestBlockNumber {
 				header = api.eth.blockchain.CurrentBlock()
-			} else if number == rpc.FinalizedBlockNumber {
+			} else if isFinalizedTag(api.eth, number) {
 				finalBlockNumber, err := getFinalizedBlockNumber(api.eth)
 				if err != nil {
 					return state.IteratorDump{}, fmt.Errorf("finalized block not found")
@@ -700,26 +825,46 @@ func (api *DebugAPI) SetTrieFlushInterval(interval string) error {
 	return nil
 }
 
-func getFinalizedBlockNumber(eth *Ethereum) (uint64, error) {
-	currentBlockNum := eth.BlockChain().CurrentBlock()
+// profileReexec is the number of blocks reexecuted at most to obtain the state
+// a profiled block is executed on.
+const profileReexec = 128
+
+// ParallelExecutionProfile re-executes a block in parallel with profiling enabled
+// and returns the dependencies between its transactions, their incarnations and
+// aborts, and the speedup of the execution over a sequential one.
+func (api *DebugAPI) ParallelExecutionProfile(ctx context.Context, blockHash common.Hash) (*blockstm.ExecutionProfile, error) {
+	block := api.eth.blockchain.GetBlockByHash(blockHash)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", blockHash)
+	}
 
-	doExist, number, hash := eth.Downloader().GetWhitelistedMilestone()
-	if doExist && number <= currentBlockNum.Number.Uint64() {
-		block := eth.BlockChain().GetBlockByNumber(number)
+	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
 
-		if block.Hash() == hash {
-			return number, nil
-		}
+	statedb, release, err := api.eth.StateAtBlock(ctx, parent, profileReexec, nil, true, false)
+	if err != nil {
+		return nil, err
 	}
 
-	doExist, number, hash = eth.Downloader().GetWhitelistedCheckpoint()
-	if doExist && number <= currentBlockNum.Number.Uint64() {
-		block := eth.BlockChain().GetBlockByNumber(number)
+	defer release()
 
-		if block.Hash() == hash {
-			return number, nil
-		}
+	cfg := *api.eth.blockchain.GetVMConfig()
+	if cfg.ParallelSpeculativeProcesses == 0 {
+		cfg.ParallelSpeculativeProcesses = runtime.NumCPU()
+	}
+
+	processor := core.NewParallelStateProcessor(api.eth.blockchain.Config(), api.eth.blockchain, api.eth.engine)
+
+	return processor.Profile(block, statedb, cfg)
+}
+
+func getFinalizedBlockNumber(eth *Ethereum) (uint64, error) {
+	finality := getFinality(eth.BlockChain(), eth.Downloader())
+	if finality.Finalized == nil {
+		return 0, fmt.Errorf("No finalized block")
 	}
 
-	return 0, fmt.Errorf("No finalized block")
+	return uint64(finality.Finalized.Number), nil
 }
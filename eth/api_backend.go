@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -75,7 +76,7 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 		return b.eth.blockchain.CurrentBlock(), nil
 	}
 
-	if number == rpc.FinalizedBlockNumber {
+	if isFinalizedTag(b.eth, number) {
 		finalBlockNumber, err := getFinalizedBlockNumber(b.eth)
 		if err != nil {
 			return nil, errors.New("finalized block not found")
@@ -144,7 +145,7 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 		return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
 	}
 
-	if number == rpc.FinalizedBlockNumber {
+	if isFinalizedTag(b.eth, number) {
 		finalBlocknumber, err := getFinalizedBlockNumber(b.eth)
 		if err != nil {
 			return nil, errors.New("finalized block not found")
@@ -312,6 +313,22 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 	return b.eth.BlockChain().SubscribeLogsEvent(ch)
 }
 
+func (b *EthAPIBackend) SendBundle(ctx context.Context, bundle *bundlepool.Bundle) error {
+	return b.eth.BundlePool().Add(bundle)
+}
+
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, maxBlock uint64) error {
+	if !b.eth.Miner().Mining() {
+		return errors.New("private transactions are not broadcasted therefore they are only accepted while mining")
+	}
+
+	return b.eth.PrivatePool().Add(signedTx, maxBlock)
+}
+
+func (b *EthAPIBackend) CancelPrivateTx(hash common.Hash, sender common.Address) bool {
+	return b.eth.PrivatePool().Cancel(hash, sender)
+}
+
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	if signedTx.GetOptions() != nil && !b.eth.Miner().GetWorker().IsRunning() {
 		return errors.New("bundled transactions are not broadcasted therefore they will not submitted to the transaction pool")
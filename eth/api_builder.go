@@ -0,0 +1,89 @@
+package eth
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BuilderAPI provides an API for builder sidecars to follow the blocks the
+// node builds.
+type BuilderAPI struct {
+	e *Ethereum
+}
+
+// NewBuilderAPI creates a new BuilderAPI instance.
+func NewBuilderAPI(e *Ethereum) *BuilderAPI {
+	return &BuilderAPI{e}
+}
+
+// PayloadUpdate is an improved version of a block being built.
+type PayloadUpdate struct {
+	PayloadID  *engine.PayloadID `json:"payloadId"` // Nil for the local sealing work
+	Number     hexutil.Uint64    `json:"number"`
+	ParentHash common.Hash       `json:"parentHash"`
+	BlockHash  common.Hash       `json:"blockHash"`
+	Fees       *hexutil.Big      `json:"fees"`
+	GasUsed    hexutil.Uint64    `json:"gasUsed"`
+	TxCount    hexutil.Uint64    `json:"txCount"`
+	Elapsed    hexutil.Uint64    `json:"elapsed"` // Build time of the version in milliseconds
+}
+
+// PayloadUpdates sends a notification each time the payload with the given id
+// improves. Without an id, the improvements of every payload and of the local
+// sealing work are sent.
+func (api *BuilderAPI) PayloadUpdates(ctx context.Context, payloadID *engine.PayloadID) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		updates := make(chan *miner.PayloadUpdate, 16)
+		updatesSub := api.e.Miner().SubscribePayloadUpdates(updates)
+
+		defer updatesSub.Unsubscribe()
+
+		for {
+			select {
+			case update := <-updates:
+				if payloadID != nil && update.ID != *payloadID {
+					continue
+				}
+
+				notifier.Notify(rpcSub.ID, newPayloadUpdate(update))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func newPayloadUpdate(update *miner.PayloadUpdate) *PayloadUpdate {
+	result := &PayloadUpdate{
+		Number:     hexutil.Uint64(update.Number),
+		ParentHash: update.ParentHash,
+		BlockHash:  update.BlockHash,
+		Fees:       (*hexutil.Big)(update.Fees),
+		GasUsed:    hexutil.Uint64(update.GasUsed),
+		TxCount:    hexutil.Uint64(update.TxCount),
+		Elapsed:    hexutil.Uint64(update.Elapsed.Milliseconds()),
+	}
+
+	if update.ID != (engine.PayloadID{}) {
+		id := update.ID
+		result.PayloadID = &id
+	}
+
+	return result
+}
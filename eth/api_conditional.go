@@ -0,0 +1,60 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Statuses of a transaction submitted through bor_sendRawTransactionConditional.
+const (
+	ConditionalTxPending  = "pending"  // Waiting in the transaction pool
+	ConditionalTxIncluded = "included" // Included in the canonical chain
+	ConditionalTxDropped  = "dropped"  // Removed from the pool after being left out of a block
+	ConditionalTxUnknown  = "unknown"  // Never seen or forgotten
+)
+
+// ConditionalTxAPI lets bundlers follow the transactions they submitted with
+// conditions on the block and state they're included in.
+type ConditionalTxAPI struct {
+	e *Ethereum
+}
+
+// NewConditionalTxAPI creates a new ConditionalTxAPI instance.
+func NewConditionalTxAPI(e *Ethereum) *ConditionalTxAPI {
+	return &ConditionalTxAPI{e}
+}
+
+// ConditionalTxStatus is the status of a conditional transaction. Unless the
+// transaction was included, the block number and reason are those of the last
+// block the miner left it out of.
+type ConditionalTxStatus struct {
+	Status      string          `json:"status"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+}
+
+// GetConditionalTransactionStatus returns whether the conditional transaction
+// with the given hash is pending, included or was dropped, along with the
+// reason the miner last gave for leaving it out of a block.
+func (api *ConditionalTxAPI) GetConditionalTransactionStatus(hash common.Hash) *ConditionalTxStatus {
+	if lookup := api.e.BlockChain().GetTransactionLookup(hash); lookup != nil {
+		number := hexutil.Uint64(lookup.BlockIndex)
+		return &ConditionalTxStatus{Status: ConditionalTxIncluded, BlockNumber: &number}
+	}
+
+	status := &ConditionalTxStatus{Status: ConditionalTxUnknown}
+
+	if skip, ok := api.e.Miner().ConditionalTxSkip(hash); ok {
+		number := hexutil.Uint64(skip.Number)
+
+		status.Status = ConditionalTxDropped
+		status.BlockNumber = &number
+		status.Reason = skip.Reason
+	}
+
+	if api.e.TxPool().Get(hash) != nil {
+		status.Status = ConditionalTxPending
+	}
+
+	return status
+}
@@ -0,0 +1,146 @@
+package eth
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FinalityAPI lets indexers follow the blocks finalized on Bor by the
+// milestones and checkpoints agreed on in Heimdall.
+type FinalityAPI struct {
+	e *Ethereum
+}
+
+// NewFinalityAPI creates a new FinalityAPI instance.
+func NewFinalityAPI(e *Ethereum) *FinalityAPI {
+	return &FinalityAPI{e}
+}
+
+// FinalizedBlock is a block of the canonical chain finalized by a milestone or
+// a checkpoint.
+type FinalizedBlock struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+}
+
+// Finality is the finality of the canonical chain. The milestone and the
+// checkpoint are left out until the chain reaches the block they finalize,
+// and the finalized block is the latest of both.
+type Finality struct {
+	Milestone  *FinalizedBlock `json:"milestone"`
+	Checkpoint *FinalizedBlock `json:"checkpoint"`
+	Finalized  *FinalizedBlock `json:"finalized"`
+}
+
+// GetFinality returns the latest blocks finalized by a milestone and by a
+// checkpoint.
+func (api *FinalityAPI) GetFinality() *Finality {
+	return getFinality(api.e.BlockChain(), api.e.Downloader())
+}
+
+// Finality sends a notification each time a milestone or a checkpoint
+// finalizes a block of the canonical chain.
+func (api *FinalityAPI) Finality(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		// A milestone or checkpoint finalizes a block once the canonical chain
+		// reaches it, and Bor grows every few seconds, so checking the finality
+		// at each new head is enough
+		heads := make(chan core.ChainHeadEvent, 16)
+		headsSub := api.e.BlockChain().SubscribeChainHeadEvent(heads)
+
+		defer headsSub.Unsubscribe()
+
+		last := api.GetFinality()
+
+		for {
+			select {
+			case <-heads:
+				finality := api.GetFinality()
+				if finality.equal(last) {
+					continue
+				}
+
+				notifier.Notify(rpcSub.ID, finality)
+
+				last = finality
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// finalitySource is where the latest milestone and checkpoint are read from.
+type finalitySource interface {
+	GetWhitelistedMilestone() (bool, uint64, common.Hash)
+	GetWhitelistedCheckpoint() (bool, uint64, common.Hash)
+}
+
+// getFinality returns the finality of the chain according to the latest
+// milestone and checkpoint.
+func getFinality(chain *core.BlockChain, source finalitySource) *Finality {
+	finality := &Finality{
+		Milestone:  canonicalFinalizedBlock(chain, source.GetWhitelistedMilestone),
+		Checkpoint: canonicalFinalizedBlock(chain, source.GetWhitelistedCheckpoint),
+	}
+
+	finality.Finalized = finality.Milestone
+	if finality.Finalized == nil || (finality.Checkpoint != nil && finality.Checkpoint.Number > finality.Finalized.Number) {
+		finality.Finalized = finality.Checkpoint
+	}
+
+	return finality
+}
+
+// canonicalFinalizedBlock returns the block finalized by the latest milestone
+// or checkpoint, if the canonical chain reached it.
+func canonicalFinalizedBlock(chain *core.BlockChain, whitelisted func() (bool, uint64, common.Hash)) *FinalizedBlock {
+	doExist, number, hash := whitelisted()
+	if !doExist || number > chain.CurrentBlock().Number.Uint64() {
+		return nil
+	}
+
+	if chain.GetCanonicalHash(number) != hash {
+		return nil
+	}
+
+	return &FinalizedBlock{Number: hexutil.Uint64(number), Hash: hash}
+}
+
+// isFinalizedTag returns whether the block number resolves to the finalized
+// block. On Bor, the "safe" tag does too, as the blocks are safe once
+// finalized by a milestone rather than by the beacon chain.
+func isFinalizedTag(eth *Ethereum, number rpc.BlockNumber) bool {
+	if number == rpc.SafeBlockNumber {
+		return eth.BlockChain().Config().Bor != nil
+	}
+
+	return number == rpc.FinalizedBlockNumber
+}
+
+func (f *Finality) equal(other *Finality) bool {
+	return f.Milestone.equal(other.Milestone) && f.Checkpoint.equal(other.Checkpoint)
+}
+
+func (b *FinalizedBlock) equal(other *FinalizedBlock) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+
+	return *b == *other
+}
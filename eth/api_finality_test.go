@@ -0,0 +1,55 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth/downloader/whitelist"
+)
+
+func TestGetFinality(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandlerWithBlocks(8)
+	defer handler.close()
+
+	chain := handler.chain
+	service := whitelist.NewService(rawdb.NewMemoryDatabase())
+
+	finalized := func(number uint64) *FinalizedBlock {
+		return &FinalizedBlock{Number: hexutil.Uint64(number), Hash: chain.GetCanonicalHash(number)}
+	}
+
+	if finality := getFinality(chain, service); finality.Milestone != nil || finality.Checkpoint != nil || finality.Finalized != nil {
+		t.Fatalf("finality without milestone nor checkpoint: have %+v", finality)
+	}
+
+	// The checkpoint finalizes the blocks until the milestone comes in
+	service.ProcessCheckpoint(3, chain.GetCanonicalHash(3))
+
+	if finality := getFinality(chain, service); !finality.Finalized.equal(finalized(3)) || finality.Milestone != nil {
+		t.Errorf("finality mismatch: have %+v, want checkpoint #3", finality)
+	}
+
+	service.ProcessMilestone(6, chain.GetCanonicalHash(6))
+
+	finality := getFinality(chain, service)
+	if !finality.Milestone.equal(finalized(6)) || !finality.Checkpoint.equal(finalized(3)) || !finality.Finalized.equal(finalized(6)) {
+		t.Errorf("finality mismatch: have %+v, want milestone #6 and checkpoint #3", finality)
+	}
+
+	// Milestones off the canonical chain or beyond its head finalize nothing
+	service.ProcessMilestone(7, common.Hash{0x01})
+
+	if finality := getFinality(chain, service); finality.Milestone != nil || !finality.Finalized.equal(finalized(3)) {
+		t.Errorf("finality mismatch: have %+v, want checkpoint #3", finality)
+	}
+
+	service.ProcessMilestone(10, common.Hash{0x02})
+
+	if finality := getFinality(chain, service); finality.Milestone != nil || !finality.Finalized.equal(finalized(3)) {
+		t.Errorf("finality mismatch: have %+v, want checkpoint #3", finality)
+	}
+}
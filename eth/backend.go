@@ -34,12 +34,16 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/simulated"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/privatepool"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/recorder"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
@@ -47,6 +51,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/protocols/conditional"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -75,6 +80,8 @@ type Ethereum struct {
 
 	// Handlers
 	txPool             *txpool.TxPool
+	bundlePool         *bundlepool.BundlePool
+	privatePool        *privatepool.PrivatePool
 	blockchain         *core.BlockChain
 	handler            *handler
 	ethDialCandidates  enode.Iterator
@@ -205,6 +212,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	blockChainAPI := ethapi.NewBlockChainAPI(ethereum.APIBackend)
 	engine := ethconfig.CreateConsensusEngine(stack, chainConfig, config, &ethashConfig, cliqueConfig, config.Miner.Notify, config.Miner.Noverify, chainDb, blockChainAPI)
 	ethereum.engine = engine
+
+	// The simulated Heimdall produces the milestones and checkpoints out of the local chain
+	if borEngine, ok := engine.(*bor.Bor); ok {
+		if heimdallClient, ok := borEngine.HeimdallClient.(*simulated.Heimdall); ok {
+			heimdallClient.SetChain(ethereum.APIBackend)
+		}
+	}
 	// END: Bor changes
 
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
@@ -277,6 +291,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 
 	ethereum.txPool = txpool.NewTxPool(config.TxPool, ethereum.blockchain.Config(), ethereum.blockchain)
+	ethereum.bundlePool = bundlepool.New(config.BundlePool, ethereum.blockchain)
+
+	// Private transactions are held to the price floor of the public ones
+	privatePoolConfig := config.PrivatePool
+	privatePoolConfig.PriceLimit = config.TxPool.PriceLimit
+
+	ethereum.privatePool = privatepool.New(privatePoolConfig, ethereum.blockchain.Config(), ethereum.blockchain)
 
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
@@ -307,6 +328,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	ethereum.miner = miner.New(ethereum, &config.Miner, ethereum.blockchain.Config(), ethereum.EventMux(), ethereum.engine, ethereum.isLocalBlock)
 	_ = ethereum.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
+	if config.ConditionalTxProtocol {
+		ethereum.handler.conditional = conditional.NewHandler(&conditionalHandler{handler: ethereum.handler, miner: ethereum.miner})
+	}
+
 	// Setup DNS discovery iterators.
 	dnsclient := dnsdisc.NewClient(dnsdisc.Config{})
 
@@ -328,6 +353,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	stack.RegisterProtocols(ethereum.Protocols())
 	stack.RegisterLifecycle(ethereum)
 
+	if config.MempoolRecording != "" {
+		stack.RegisterLifecycle(recorder.New(stack.ResolvePath(config.MempoolRecording), ethereum.txPool, ethereum.blockchain))
+	}
+
 	// Successful startup; push a marker and check previous unclean shutdowns.
 	ethereum.shutdownTracker.MarkStartup()
 
@@ -391,6 +420,15 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "miner",
 			Service:   NewMinerAPI(s),
+		}, {
+			Namespace: "builder",
+			Service:   NewBuilderAPI(s),
+		}, {
+			Namespace: "bor",
+			Service:   NewConditionalTxAPI(s),
+		}, {
+			Namespace: "bor",
+			Service:   NewFinalityAPI(s),
 		}, {
 			Namespace: "eth",
 			Service:   publicFilterAPI, // BOR related change
@@ -588,19 +626,21 @@ func (s *Ethereum) StopMining() {
 func (s *Ethereum) IsMining() bool      { return s.miner.Mining() }
 func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 
-func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager }
-func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
-func (s *Ethereum) TxPool() *txpool.TxPool             { return s.txPool }
-func (s *Ethereum) EventMux() *event.TypeMux           { return s.eventMux }
-func (s *Ethereum) Engine() consensus.Engine           { return s.engine }
-func (s *Ethereum) ChainDb() ethdb.Database            { return s.chainDb }
-func (s *Ethereum) IsListening() bool                  { return true } // Always listening
-func (s *Ethereum) Downloader() *downloader.Downloader { return s.handler.downloader }
-func (s *Ethereum) Synced() bool                       { return atomic.LoadUint32(&s.handler.acceptTxs) == 1 }
-func (s *Ethereum) SetSynced()                         { atomic.StoreUint32(&s.handler.acceptTxs, 1) }
-func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruning }
-func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
-func (s *Ethereum) Merger() *consensus.Merger          { return s.merger }
+func (s *Ethereum) AccountManager() *accounts.Manager     { return s.accountManager }
+func (s *Ethereum) BlockChain() *core.BlockChain          { return s.blockchain }
+func (s *Ethereum) TxPool() *txpool.TxPool                { return s.txPool }
+func (s *Ethereum) BundlePool() *bundlepool.BundlePool    { return s.bundlePool }
+func (s *Ethereum) PrivatePool() *privatepool.PrivatePool { return s.privatePool }
+func (s *Ethereum) EventMux() *event.TypeMux              { return s.eventMux }
+func (s *Ethereum) Engine() consensus.Engine              { return s.engine }
+func (s *Ethereum) ChainDb() ethdb.Database               { return s.chainDb }
+func (s *Ethereum) IsListening() bool                     { return true } // Always listening
+func (s *Ethereum) Downloader() *downloader.Downloader    { return s.handler.downloader }
+func (s *Ethereum) Synced() bool                          { return atomic.LoadUint32(&s.handler.acceptTxs) == 1 }
+func (s *Ethereum) SetSynced()                            { atomic.StoreUint32(&s.handler.acceptTxs, 1) }
+func (s *Ethereum) ArchiveMode() bool                     { return s.config.NoPruning }
+func (s *Ethereum) BloomIndexer() *core.ChainIndexer      { return s.bloomIndexer }
+func (s *Ethereum) Merger() *consensus.Merger             { return s.merger }
 func (s *Ethereum) SyncMode() downloader.SyncMode {
 	mode, _ := s.handler.chainSync.modeAndLocalHead()
 	return mode
@@ -622,6 +662,10 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler), s.snapDialCandidates)...)
 	}
 
+	if s.handler.conditional != nil {
+		protos = append(protos, s.handler.conditional.MakeProtocols()...)
+	}
+
 	return protos
 }
 
@@ -0,0 +1,214 @@
+// Package blockvalidation implements the RPC relays use to have a full node
+// check candidate blocks submitted by builders before offering them to
+// proposers.
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Names of the checks a submission goes through, in order.
+const (
+	CheckRequest   = "request"    // Submission is well formed
+	CheckParent    = "parent"     // Parent is known along with its state
+	CheckEIP1559   = "eip1559"    // Gas limit and base fee follow the parent
+	CheckBody      = "body"       // Transactions and uncles match the header
+	CheckSpan      = "span"       // Span commit due at a sprint start succeeds
+	CheckStateSync = "state-sync" // State-sync commits due at a sprint start succeed
+	CheckExecution = "execution"  // Transactions execute on the parent state
+	CheckState     = "state"      // Gas, receipts and state root match the header
+	CheckPayment   = "payment"    // Last transaction pays the proposer as claimed
+)
+
+// ValidationError is returned if a submission fails one of the checks, which
+// is reported in the error data.
+type ValidationError struct {
+	Check string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s check failed: %v", e.Check, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the JSON error code for a failed validation.
+func (e *ValidationError) ErrorCode() int {
+	return -32000
+}
+
+// ErrorData returns the failed check along with the reason.
+func (e *ValidationError) ErrorData() interface{} {
+	return map[string]string{
+		"check":  e.Check,
+		"reason": e.Err.Error(),
+	}
+}
+
+// BuilderSubmissionRequest is a candidate block together with the payment the
+// builder claims to make to the proposer through the last transaction.
+type BuilderSubmissionRequest struct {
+	Header          *types.Header   `json:"header"`
+	Transactions    []hexutil.Bytes `json:"transactions"`
+	FeeRecipient    common.Address  `json:"feeRecipient"`
+	ProposerPayment *hexutil.Big    `json:"proposerPayment"`
+}
+
+// BlockValidationAPI validates builder submissions against the local chain.
+type BlockValidationAPI struct {
+	chain *core.BlockChain
+}
+
+// NewBlockValidationAPI creates a new block validation API.
+func NewBlockValidationAPI(chain *core.BlockChain) *BlockValidationAPI {
+	return &BlockValidationAPI{chain: chain}
+}
+
+// APIs returns the block validation RPC APIs. They're only served on the
+// authenticated RPC, as each call executes a whole block.
+func APIs(chain *core.BlockChain) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace:     "flashbots",
+			Service:       NewBlockValidationAPI(chain),
+			Authenticated: true,
+		},
+	}
+}
+
+// ValidateBuilderSubmission executes the submitted block on top of its parent
+// and checks that it's valid and pays the proposer the claimed amount. A nil
+// error means the block can be offered to the proposer.
+func (api *BlockValidationAPI) ValidateBuilderSubmission(ctx context.Context, req *BuilderSubmissionRequest) error {
+	if req == nil || req.Header == nil || req.Header.Number == nil || req.ProposerPayment == nil {
+		return &ValidationError{CheckRequest, errors.New("incomplete submission")}
+	}
+
+	txs := make(types.Transactions, 0, len(req.Transactions))
+
+	for i, enc := range req.Transactions {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(enc); err != nil {
+			return &ValidationError{CheckRequest, fmt.Errorf("invalid transaction %d: %v", i, err)}
+		}
+
+		txs = append(txs, tx)
+	}
+
+	block := types.NewBlockWithHeader(req.Header).WithBody(txs, nil)
+
+	return api.validate(ctx, block, req.FeeRecipient, req.ProposerPayment.ToInt())
+}
+
+func (api *BlockValidationAPI) validate(ctx context.Context, block *types.Block, feeRecipient common.Address, payment *big.Int) error {
+	var (
+		chain  = api.chain
+		config = chain.Config()
+		header = block.Header()
+	)
+
+	parent := chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return &ValidationError{CheckParent, fmt.Errorf("unknown parent %s", block.ParentHash())}
+	}
+
+	if config.IsLondon(header.Number) {
+		if err := misc.VerifyEip1559Header(config, parent, header); err != nil {
+			return &ValidationError{CheckEIP1559, err}
+		}
+	} else if err := misc.VerifyGaslimit(parent.GasLimit, header.GasLimit); err != nil {
+		return &ValidationError{CheckEIP1559, err}
+	}
+
+	if err := chain.Validator().ValidateBody(block); err != nil {
+		return &ValidationError{CheckBody, err}
+	}
+
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		return &ValidationError{CheckParent, err}
+	}
+
+	// The span and state-sync commits are part of the block finalisation, which
+	// only logs their failures. Run them upfront to tell them apart from a bad
+	// state root.
+	if engine, ok := chain.Engine().(*bor.Bor); ok {
+		if _, err := engine.CommitSystemState(ctx, chain, header, statedb.Copy()); err != nil {
+			switch {
+			case errors.Is(err, bor.ErrSpanCommit):
+				return &ValidationError{CheckSpan, err}
+			default:
+				return &ValidationError{CheckStateSync, err}
+			}
+		}
+	}
+
+	var (
+		gasPool  = new(core.GasPool).AddGas(header.GasLimit)
+		receipts = make(types.Receipts, 0, len(block.Transactions()))
+		usedGas  uint64
+	)
+
+	for i, tx := range block.Transactions() {
+		statedb.SetTxContext(tx.Hash(), i)
+
+		receipt, err := core.ApplyTransaction(config, chain, nil, gasPool, statedb, header, tx, &usedGas, *chain.GetVMConfig(), ctx)
+		if err != nil {
+			return &ValidationError{CheckExecution, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash(), err)}
+		}
+
+		receipts = append(receipts, receipt)
+	}
+
+	// Finalize the block without handing its state syncs to the chain, which
+	// only holds the ones of its own blocks
+	chain.Engine().Finalize(core.NewStateSyncCollector(chain), types.CopyHeader(header), statedb, block.Transactions(), nil, nil)
+
+	if err := chain.Validator().ValidateState(block, statedb, receipts, usedGas); err != nil {
+		return &ValidationError{CheckState, err}
+	}
+
+	if err := checkPayment(block.Transactions(), receipts, feeRecipient, payment); err != nil {
+		return &ValidationError{CheckPayment, err}
+	}
+
+	return nil
+}
+
+// checkPayment verifies that the last transaction of the block successfully
+// transfers the claimed payment to the proposer.
+func checkPayment(txs types.Transactions, receipts types.Receipts, feeRecipient common.Address, payment *big.Int) error {
+	if len(txs) == 0 {
+		return errors.New("no proposer payment transaction")
+	}
+
+	tx := txs[len(txs)-1]
+
+	if to := tx.To(); to == nil || *to != feeRecipient {
+		return fmt.Errorf("payment to wrong recipient: have %v, want %s", to, feeRecipient)
+	}
+
+	if tx.Value().Cmp(payment) != 0 {
+		return fmt.Errorf("payment value mismatch: have %v, want %v", tx.Value(), payment)
+	}
+
+	if receipts[len(txs)-1].Status != types.ReceiptStatusSuccessful {
+		return errors.New("payment transaction failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestValidateBuilderSubmission(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		proposer = common.HexToAddress("0xb0b")
+		payment  = big.NewInt(params.GWei)
+
+		engine = ethash.NewFaker()
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 1, func(i int, gen *core.BlockGen) {
+		for nonce, to := range []common.Address{{0x01}, proposer} {
+			value := new(big.Int)
+			if to == proposer {
+				value = payment
+			}
+
+			gen.AddTx(types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   gspec.Config.ChainID,
+				Nonce:     uint64(nonce),
+				GasTipCap: big.NewInt(params.GWei),
+				GasFeeCap: new(big.Int).Add(gen.BaseFee(), big.NewInt(params.GWei)),
+				Gas:       params.TxGas,
+				To:        &to,
+				Value:     value,
+			}))
+		}
+	})
+	block := blocks[0]
+
+	newRequest := func(header *types.Header, txs types.Transactions, recipient common.Address, value *big.Int) *BuilderSubmissionRequest {
+		req := &BuilderSubmissionRequest{
+			Header:          header,
+			FeeRecipient:    recipient,
+			ProposerPayment: (*hexutil.Big)(value),
+		}
+
+		for _, tx := range txs {
+			enc, _ := tx.MarshalBinary()
+			req.Transactions = append(req.Transactions, enc)
+		}
+
+		return req
+	}
+
+	var (
+		badParent  = block.Header()
+		badBaseFee = block.Header()
+		badRoot    = block.Header()
+	)
+
+	badParent.ParentHash = common.Hash{0x01}
+	badBaseFee.BaseFee = new(big.Int).Add(badBaseFee.BaseFee, common.Big1)
+	badRoot.Root = common.Hash{0x01}
+
+	tests := []struct {
+		name  string
+		req   *BuilderSubmissionRequest
+		check string
+	}{
+		{"valid", newRequest(block.Header(), block.Transactions(), proposer, payment), ""},
+		{"incomplete", &BuilderSubmissionRequest{Header: block.Header()}, CheckRequest},
+		{"unknown parent", newRequest(badParent, block.Transactions(), proposer, payment), CheckParent},
+		{"invalid base fee", newRequest(badBaseFee, block.Transactions(), proposer, payment), CheckEIP1559},
+		{"missing transaction", newRequest(block.Header(), block.Transactions()[:1], proposer, payment), CheckBody},
+		{"invalid state root", newRequest(badRoot, block.Transactions(), proposer, payment), CheckState},
+		{"wrong recipient", newRequest(block.Header(), block.Transactions(), common.HexToAddress("0xbad"), payment), CheckPayment},
+		{"wrong payment", newRequest(block.Header(), block.Transactions(), proposer, common.Big2), CheckPayment},
+	}
+
+	api := NewBlockValidationAPI(chain)
+
+	for _, tt := range tests {
+		err := api.ValidateBuilderSubmission(context.Background(), tt.req)
+		if tt.check == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+
+			continue
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Check != tt.check {
+			t.Errorf("%s: failed check mismatch: have %v, want %s", tt.name, err, tt.check)
+		}
+	}
+}
+
+func TestAPIsAuthenticated(t *testing.T) {
+	t.Parallel()
+
+	for _, api := range APIs(nil) {
+		if !api.Authenticated {
+			t.Errorf("%s namespace served without authentication", api.Namespace)
+		}
+	}
+}
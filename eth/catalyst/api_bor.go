@@ -0,0 +1,147 @@
+package catalyst
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RegisterBor adds the Bor engine API to the full node. Unlike Register, it
+// leaves out the post-merge methods, which rely on a terminal total difficulty
+// Bor chains don't have.
+func RegisterBor(stack *node.Node, backend *eth.Ethereum) error {
+	log.Warn("Engine API enabled", "protocol", "bor")
+	stack.RegisterAPIs([]rpc.API{
+		{
+			Namespace:     "engine",
+			Service:       NewBorConsensusAPI(backend),
+			Authenticated: true,
+		},
+	})
+
+	return nil
+}
+
+// All methods provided over the Bor engine endpoint.
+var borCaps = []string{
+	"engine_forkchoiceUpdatedBor",
+	"engine_getPayloadBor",
+	"engine_exchangeCapabilities",
+}
+
+// BorConsensusAPI is the engine API external block producers drive Bor block
+// construction through.
+type BorConsensusAPI struct {
+	eth *eth.Ethereum
+
+	localBlocks *payloadQueue // Cache of local payloads generated
+
+	forkchoiceLock sync.Mutex // Lock for the ForkchoiceUpdatedBor method
+}
+
+// NewBorConsensusAPI creates a new Bor engine API for the given backend.
+func NewBorConsensusAPI(eth *eth.Ethereum) *BorConsensusAPI {
+	return &BorConsensusAPI{
+		eth:         eth,
+		localBlocks: newPayloadQueue(),
+	}
+}
+
+// ForkchoiceUpdatedBor lets an external block producer drive block construction
+// on a Bor chain. As Bor picks its canonical chain by itself, the forkchoice
+// state is only checked against the local chain and never changes it: the head
+// must be known, and the safe and finalized blocks canonical if set.
+//
+// If there are payloadAttributes, a block is assembled on top of the head the
+// way the local signer would seal it, and its payloadID is returned.
+func (api *BorConsensusAPI) ForkchoiceUpdatedBor(update engine.ForkchoiceStateV1, payloadAttributes *engine.BorPayloadAttributes) (engine.ForkChoiceResponse, error) {
+	api.forkchoiceLock.Lock()
+	defer api.forkchoiceLock.Unlock()
+
+	log.Trace("Engine API request received", "method", "ForkchoiceUpdatedBor", "head", update.HeadBlockHash, "finalized", update.FinalizedBlockHash, "safe", update.SafeBlockHash)
+
+	if _, ok := api.eth.Engine().(*bor.Bor); !ok {
+		return engine.STATUS_INVALID, engine.GenericServerError.With(errors.New("consensus engine is not bor"))
+	}
+
+	if update.HeadBlockHash == (common.Hash{}) {
+		return engine.STATUS_INVALID, nil
+	}
+
+	block := api.eth.BlockChain().GetBlockByHash(update.HeadBlockHash)
+	if block == nil {
+		log.Warn("Forkchoice requested unknown head", "hash", update.HeadBlockHash)
+		return engine.STATUS_SYNCING, nil
+	}
+
+	for _, hash := range []common.Hash{update.SafeBlockHash, update.FinalizedBlockHash} {
+		if hash == (common.Hash{}) {
+			continue
+		}
+
+		header := api.eth.BlockChain().GetHeaderByHash(hash)
+		if header == nil || rawdb.ReadCanonicalHash(api.eth.ChainDb(), header.Number.Uint64()) != hash {
+			return engine.STATUS_INVALID, engine.InvalidForkChoiceState.With(fmt.Errorf("block %s not in canonical chain", hash))
+		}
+	}
+
+	response := engine.ForkChoiceResponse{
+		PayloadStatus: engine.PayloadStatusV1{Status: engine.VALID, LatestValidHash: &update.HeadBlockHash},
+	}
+
+	if payloadAttributes == nil {
+		return response, nil
+	}
+
+	args := &miner.BorPayloadArgs{
+		Parent:       update.HeadBlockHash,
+		FeeRecipient: payloadAttributes.SuggestedFeeRecipient,
+	}
+
+	// Without a fee recipient, the fees go to the signer as for local blocks
+	if args.FeeRecipient == (common.Address{}) {
+		args.FeeRecipient, _ = api.eth.Etherbase()
+	}
+
+	id := args.Id()
+	if !api.localBlocks.has(id) {
+		payload, err := api.eth.Miner().BuildBorPayload(args)
+		if err != nil {
+			log.Error("Failed to build bor payload", "err", err)
+			return response, engine.InvalidPayloadAttributes.With(err)
+		}
+
+		api.localBlocks.put(id, payload)
+	}
+
+	response.PayloadID = &id
+
+	return response, nil
+}
+
+// GetPayloadBor returns a cached Bor payload by id.
+func (api *BorConsensusAPI) GetPayloadBor(payloadID engine.PayloadID) (*engine.BorExecutionPayloadEnvelope, error) {
+	log.Trace("Engine API request received", "method", "GetPayloadBor", "id", payloadID)
+
+	data := api.localBlocks.getBor(payloadID)
+	if data == nil {
+		return nil, engine.UnknownPayload
+	}
+
+	return data, nil
+}
+
+// ExchangeCapabilities returns the current methods provided by this node.
+func (api *BorConsensusAPI) ExchangeCapabilities([]string) []string {
+	return borCaps
+}
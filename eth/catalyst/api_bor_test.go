@@ -0,0 +1,53 @@
+package catalyst
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestBorConsensusAPIMethods(t *testing.T) {
+	t.Parallel()
+
+	server := rpc.NewServer("test", 0, 0)
+	defer server.Stop()
+
+	if err := server.RegisterName("engine", NewBorConsensusAPI(nil)); err != nil {
+		t.Fatalf("failed to register bor engine api: %v", err)
+	}
+
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	// The post-merge methods must not be reachable on Bor
+	for _, method := range []string{
+		"engine_forkchoiceUpdatedV1",
+		"engine_forkchoiceUpdatedV2",
+		"engine_newPayloadV1",
+		"engine_newPayloadV2",
+		"engine_getPayloadV1",
+		"engine_getPayloadV2",
+		"engine_exchangeTransitionConfigurationV1",
+	} {
+		err := client.Call(nil, method, nil)
+		if err == nil || !strings.Contains(err.Error(), "does not exist") {
+			t.Errorf("%s reachable: have error %v", method, err)
+		}
+	}
+
+	var caps []string
+	if err := client.Call(&caps, "engine_exchangeCapabilities", []string{}); err != nil {
+		t.Fatalf("failed to exchange capabilities: %v", err)
+	}
+
+	if strings.Join(caps, ",") != strings.Join(borCaps, ",") {
+		t.Errorf("capabilities mismatch: have %v, want %v", caps, borCaps)
+	}
+
+	err := client.Call(nil, "engine_getPayloadBor", engine.PayloadID{})
+	if err == nil || err.Error() != engine.UnknownPayload.Error() {
+		t.Errorf("error mismatch: have %v, want %v", err, engine.UnknownPayload)
+	}
+}
@@ -74,6 +74,25 @@ func (q *payloadQueue) put(id engine.PayloadID, payload *miner.Payload) {
 
 // get retrieves a previously stored payload item or nil if it does not exist.
 func (q *payloadQueue) get(id engine.PayloadID) *engine.ExecutionPayloadEnvelope {
+	if payload := q.find(id); payload != nil {
+		return payload.Resolve()
+	}
+
+	return nil
+}
+
+// getBor retrieves a previously stored Bor payload item or nil if it does not
+// exist.
+func (q *payloadQueue) getBor(id engine.PayloadID) *engine.BorExecutionPayloadEnvelope {
+	if payload := q.find(id); payload != nil {
+		return payload.ResolveBor()
+	}
+
+	return nil
+}
+
+// find looks up a previously stored payload.
+func (q *payloadQueue) find(id engine.PayloadID) *miner.Payload {
 	q.lock.RLock()
 	defer q.lock.RUnlock()
 
@@ -83,7 +102,7 @@ func (q *payloadQueue) get(id engine.PayloadID) *engine.ExecutionPayloadEnvelope
 		}
 
 		if item.id == id {
-			return item.payload.Resolve()
+			return item.payload
 		}
 	}
 
@@ -22,6 +22,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -30,12 +31,16 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/bor/contract"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall" //nolint:typecheck
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/failover"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/simulated"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdallapp"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdallgrpc"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/privatepool"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
@@ -93,6 +98,8 @@ var Defaults = Config{
 	FilterLogCacheSize:      32,
 	Miner:                   miner.DefaultConfig,
 	TxPool:                  txpool.DefaultConfig,
+	BundlePool:              bundlepool.DefaultConfig,
+	PrivatePool:             privatepool.DefaultConfig,
 	RPCGasCap:               50000000,
 	RPCReturnDataLimit:      100000,
 	RPCEVMTimeout:           5 * time.Second,
@@ -139,6 +146,10 @@ type Config struct {
 	EthDiscoveryURLs  []string
 	SnapDiscoveryURLs []string
 
+	// Whether to exchange conditional transactions along with their options
+	// with trusted peers over the `cond` protocol.
+	ConditionalTxProtocol bool
+
 	NoPruning  bool // Whether to disable pruning and flush everything to disk
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
@@ -196,6 +207,16 @@ type Config struct {
 	// Transaction pool options
 	TxPool txpool.Config
 
+	// Bundle pool options
+	BundlePool bundlepool.Config
+
+	// Private transaction pool options, the price limit is the one of TxPool
+	PrivatePool privatepool.Config
+
+	// Path of the recording of the transaction pool events and chain heads, which
+	// is disabled if empty.
+	MempoolRecording string `toml:",omitempty"`
+
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
@@ -228,15 +249,21 @@ type Config struct {
 	// OverrideShanghai (TODO: remove after the fork)
 	OverrideShanghai *uint64 `toml:",omitempty"`
 
-	// URL to connect to Heimdall node
+	// URL to connect to Heimdall node, or comma separated URLs to fail over between
 	HeimdallURL string
 
 	// No heimdall service
 	WithoutHeimdall bool
 
-	// Address to connect to Heimdall gRPC server
+	// Address to connect to Heimdall gRPC server, or comma separated addresses to fail over between
 	HeimdallgRPCAddress string
 
+	// Cross-check the spans and milestones between the Heimdall endpoints
+	HeimdallCrossCheck bool
+
+	// Simulate Heimdall in process on developer chains, instead of connecting to it
+	DevHeimdall *simulated.Config `toml:",omitempty"`
+
 	// Run heimdall service as a child process
 	RunHeimdall bool
 
@@ -278,12 +305,12 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, et
 			}
 
 			var heimdallClient bor.IHeimdallClient
-			if ethConfig.RunHeimdall && ethConfig.UseHeimdallApp {
+			if ethConfig.DevHeimdall != nil {
+				heimdallClient = simulated.New(*ethConfig.DevHeimdall)
+			} else if ethConfig.RunHeimdall && ethConfig.UseHeimdallApp {
 				heimdallClient = heimdallapp.NewHeimdallAppClient()
-			} else if ethConfig.HeimdallgRPCAddress != "" {
-				heimdallClient = heimdallgrpc.NewHeimdallGRPCClient(ethConfig.HeimdallgRPCAddress)
 			} else {
-				heimdallClient = heimdall.NewHeimdallClient(ethConfig.HeimdallURL)
+				heimdallClient = newHeimdallClient(ethConfig)
 			}
 
 			return bor.New(chainConfig, db, blockchainAPI, spanner, heimdallClient, genesisContractsClient, false)
@@ -316,3 +343,29 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, et
 
 	return beacon.New(engine)
 }
+
+// newHeimdallClient creates the client of the Heimdall gRPC servers or else of
+// the Heimdall URLs, failing over between them if several are configured.
+func newHeimdallClient(ethConfig *Config) bor.IHeimdallClient {
+	var endpoints []failover.Endpoint
+
+	if ethConfig.HeimdallgRPCAddress != "" {
+		for _, address := range strings.Split(ethConfig.HeimdallgRPCAddress, ",") {
+			address = strings.TrimSpace(address)
+			endpoints = append(endpoints, failover.Endpoint{URL: address, Client: heimdallgrpc.NewHeimdallGRPCClient(address)})
+		}
+	} else {
+		for _, url := range strings.Split(ethConfig.HeimdallURL, ",") {
+			url = strings.TrimSpace(url)
+			endpoints = append(endpoints, failover.Endpoint{URL: url, Client: heimdall.NewHeimdallClient(url)})
+		}
+	}
+
+	if len(endpoints) == 1 {
+		return endpoints[0].Client
+	}
+
+	log.Info("Failing over between Heimdall endpoints", "endpoints", len(endpoints), "crosscheck", ethConfig.HeimdallCrossCheck)
+
+	return failover.NewClient(endpoints, ethConfig.HeimdallCrossCheck)
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/fetcher"
+	"github.com/ethereum/go-ethereum/eth/protocols/conditional"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -116,6 +117,7 @@ type handler struct {
 	txFetcher    *fetcher.TxFetcher
 	peers        *peerSet
 	merger       *consensus.Merger
+	conditional  *conditional.Handler // Exchanges conditional transactions with trusted peers, nil if disabled
 
 	ethAPI *ethapi.BlockChainAPI // EthAPI to interact
 
@@ -739,6 +741,10 @@ func (h *handler) txBroadcastLoop() {
 		select {
 		case event := <-h.txsCh:
 			h.BroadcastTransactions(event.Txs)
+
+			if h.conditional != nil {
+				h.conditional.Broadcast(event.Txs)
+			}
 		case <-h.txsSub.Err():
 			return
 		}
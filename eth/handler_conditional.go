@@ -0,0 +1,45 @@
+package eth
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/miner"
+)
+
+var (
+	errNotSynced = errors.New("not synced, conditional transactions not accepted")
+	errNotMining = errors.New("not mining, conditional transactions not accepted")
+)
+
+// conditionalHandler implements the conditional.Backend interface to handle
+// the conditional transactions received from trusted peers.
+type conditionalHandler struct {
+	*handler
+
+	miner *miner.Miner
+}
+
+// AddConditionalTxs adds the conditional transactions received from a trusted
+// peer to the transaction pool, which validates their options. As for the ones
+// sent over RPC, they are only accepted while the node is mining.
+func (h *conditionalHandler) AddConditionalTxs(txs []*types.Transaction) []error {
+	var err error
+
+	switch {
+	case atomic.LoadUint32(&h.acceptTxs) == 0:
+		err = errNotSynced
+	case !h.miner.GetWorker().IsRunning():
+		err = errNotMining
+	default:
+		return h.txpool.AddRemotes(txs)
+	}
+
+	errs := make([]error, len(txs))
+	for i := range errs {
+		errs[i] = err
+	}
+
+	return errs
+}
@@ -0,0 +1,224 @@
+package conditional
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// maxKnownTxs is the maximum transactions hashes to keep in the known list
+	// before starting to randomly evict them.
+	maxKnownTxs = 32768
+
+	// maxQueuedTxs is the maximum number of transaction batches to queue up
+	// before dropping broadcasts to a peer.
+	maxQueuedTxs = 128
+)
+
+// Backend defines the data retrieval methods to serve the `cond` protocol.
+type Backend interface {
+	// AddConditionalTxs adds the conditional transactions received from a
+	// trusted peer to the transaction pool.
+	AddConditionalTxs(txs []*types.Transaction) []error
+}
+
+// Handler runs the `cond` protocol with trusted peers and broadcasts the local
+// conditional transactions to them.
+type Handler struct {
+	backend Backend
+
+	mu    sync.RWMutex
+	peers map[string]*Peer
+}
+
+// NewHandler creates a handler of the `cond` protocol.
+func NewHandler(backend Backend) *Handler {
+	return &Handler{
+		backend: backend,
+		peers:   make(map[string]*Peer),
+	}
+}
+
+// Peer is a trusted peer exchanging conditional transactions.
+type Peer struct {
+	id    string
+	rw    p2p.MsgReadWriter
+	known *lru.Cache[common.Hash, struct{}]
+	queue chan types.Transactions
+	term  chan struct{}
+}
+
+// MakeProtocols constructs the P2P protocol definitions for `cond`.
+func (h *Handler) MakeProtocols() []p2p.Protocol {
+	protocols := make([]p2p.Protocol, len(ProtocolVersions))
+
+	for i, version := range ProtocolVersions {
+		protocols[i] = p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  protocolLengths[version],
+			Run:     h.runPeer,
+		}
+	}
+
+	return protocols
+}
+
+// runPeer is the callback invoked to manage the life cycle of a `cond` peer.
+// When this function terminates, the peer is disconnected.
+func (h *Handler) runPeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+	// Untrusted peers are kept connected over the other protocols, but nothing
+	// is exchanged with them
+	if !p.Info().Network.Trusted {
+		for {
+			msg, err := rw.ReadMsg()
+			if err != nil {
+				return err
+			}
+
+			msg.Discard()
+		}
+	}
+
+	peer := &Peer{
+		id:    p.ID().String(),
+		rw:    rw,
+		known: lru.NewCache[common.Hash, struct{}](maxKnownTxs),
+		queue: make(chan types.Transactions, maxQueuedTxs),
+		term:  make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.peers[peer.id] = peer
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.peers, peer.id)
+		h.mu.Unlock()
+
+		close(peer.term)
+	}()
+
+	go peer.broadcastLoop()
+
+	for {
+		if err := h.handleMessage(peer); err != nil {
+			p.Log().Debug("Message handling failed in `cond`", "err", err)
+			return err
+		}
+	}
+}
+
+// handleMessage is invoked whenever an inbound message is received from a
+// remote peer. The remote connection is torn down upon returning any error.
+func (h *Handler) handleMessage(peer *Peer) error {
+	msg, err := peer.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+
+	if msg.Code != TransactionsMsg {
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+
+	var packet TransactionsPacket
+	if err := msg.Decode(&packet); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+
+	txs := make([]*types.Transaction, 0, len(packet))
+
+	for i, enc := range packet {
+		tx := new(types.Transaction)
+		if err := tx.DecodeConditionalRLP(enc); err != nil {
+			return fmt.Errorf("%w: transaction %d: %v", errDecode, i, err)
+		}
+
+		if tx.GetOptions() == nil {
+			return fmt.Errorf("%w: transaction %d has no options", errDecode, i)
+		}
+
+		peer.known.Add(tx.Hash(), struct{}{})
+		txs = append(txs, tx)
+	}
+
+	for i, err := range h.backend.AddConditionalTxs(txs) {
+		if err != nil {
+			log.Trace("Failed to add conditional transaction", "hash", txs[i].Hash(), "err", err)
+		}
+	}
+
+	return nil
+}
+
+// Broadcast queues the conditional transactions among txs for sending to all
+// the trusted peers not knowing about them yet.
+func (h *Handler) Broadcast(txs types.Transactions) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, peer := range h.peers {
+		var unknown types.Transactions
+
+		for _, tx := range txs {
+			if tx.GetOptions() != nil && !peer.known.Contains(tx.Hash()) {
+				unknown = append(unknown, tx)
+			}
+		}
+
+		if len(unknown) == 0 {
+			continue
+		}
+
+		select {
+		case peer.queue <- unknown:
+		default:
+			log.Debug("Dropping conditional transaction broadcast", "peer", peer.id, "count", len(unknown))
+		}
+	}
+}
+
+// broadcastLoop sends the queued transactions to the peer until it terminates.
+func (p *Peer) broadcastLoop() {
+	for {
+		select {
+		case txs := <-p.queue:
+			packet := make(TransactionsPacket, 0, len(txs))
+
+			for _, tx := range txs {
+				var buf bytes.Buffer
+				if err := tx.EncodeConditionalRLP(&buf); err != nil {
+					log.Debug("Failed to encode conditional transaction", "hash", tx.Hash(), "err", err)
+					continue
+				}
+
+				packet = append(packet, rlp.RawValue(buf.Bytes()))
+			}
+
+			if err := p2p.Send(p.rw, TransactionsMsg, packet); err != nil {
+				return
+			}
+
+			for _, tx := range txs {
+				p.known.Add(tx.Hash(), struct{}{})
+			}
+
+		case <-p.term:
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package conditional
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type testBackend struct {
+	txs []*types.Transaction
+}
+
+func (b *testBackend) AddConditionalTxs(txs []*types.Transaction) []error {
+	b.txs = append(b.txs, txs...)
+	return make([]error, len(txs))
+}
+
+func newTestTx(t *testing.T, nonce uint64, options *types.OptionsAA4337) (*types.Transaction, rlp.RawValue) {
+	t.Helper()
+
+	key, _ := crypto.GenerateKey()
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(common.Big1), &types.DynamicFeeTx{
+		ChainID:   common.Big1,
+		Nonce:     nonce,
+		Gas:       21000,
+		GasFeeCap: common.Big1,
+		GasTipCap: common.Big1,
+	})
+
+	if options != nil {
+		tx.PutOptions(options)
+	}
+
+	var buf bytes.Buffer
+	if err := tx.EncodeConditionalRLP(&buf); err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+
+	return tx, buf.Bytes()
+}
+
+func TestHandleTransactions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = new(testBackend)
+		handler = NewHandler(backend)
+	)
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	peer := &Peer{id: "test", rw: net, known: lru.NewCache[common.Hash, struct{}](maxKnownTxs)}
+
+	tx, enc := newTestTx(t, 0, &types.OptionsAA4337{BlockNumberMax: big.NewInt(10)})
+
+	go p2p.Send(app, TransactionsMsg, TransactionsPacket{enc})
+
+	if err := handler.handleMessage(peer); err != nil {
+		t.Fatalf("failed to handle transactions: %v", err)
+	}
+
+	if len(backend.txs) != 1 || backend.txs[0].Hash() != tx.Hash() {
+		t.Fatalf("transactions mismatch: have %v, want %s", backend.txs, tx.Hash())
+	}
+
+	if options := backend.txs[0].GetOptions(); options == nil || options.BlockNumberMax.Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("options mismatch: have %+v", options)
+	}
+
+	if !peer.known.Contains(tx.Hash()) {
+		t.Fatalf("transaction not marked as known")
+	}
+
+	// Transactions without options don't belong to the protocol
+	_, enc = newTestTx(t, 1, nil)
+
+	go p2p.Send(app, TransactionsMsg, TransactionsPacket{enc})
+
+	if err := handler.handleMessage(peer); !errors.Is(err, errDecode) {
+		t.Fatalf("error mismatch: have %v, want %v", err, errDecode)
+	}
+}
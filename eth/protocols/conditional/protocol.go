@@ -0,0 +1,48 @@
+// Package conditional implements the `cond` protocol, which trusted peers such
+// as sentries and their validators use to exchange conditional (EIP-4337)
+// transactions along with their options. Conditional transactions are never
+// propagated over the `eth` protocol, as it can't carry the options.
+package conditional
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	COND1 = 1
+)
+
+// ProtocolName is the official short name of the `cond` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "cond"
+
+// ProtocolVersions are the supported versions of the `cond` protocol (first
+// is primary).
+var ProtocolVersions = []uint{COND1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{COND1: 1}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 10 * 1024 * 1024
+
+const (
+	TransactionsMsg = 0x00
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+)
+
+// TransactionsPacket is the network packet for propagating conditional
+// transactions, each encoded along with its options.
+type TransactionsPacket []rlp.RawValue
+
+func (*TransactionsPacket) Name() string { return "Transactions" }
+func (*TransactionsPacket) Kind() byte   { return TransactionsMsg }
@@ -915,7 +915,7 @@ txloop:
 				if *config.BorTraceEnabled {
 					callmsg := prepareCallMessage(*msg)
 					// nolint : contextcheck
-					if _, err := statefull.ApplyBorMessage(*vmenv, callmsg); err != nil {
+					if _, err := statefull.ApplyBorMessage(vmenv, callmsg); err != nil {
 						failed = err
 						break txloop
 					}
@@ -1122,7 +1122,7 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 		if stateSyncPresent && i == len(txs)-1 {
 			if *config.BorTraceEnabled {
 				callmsg := prepareCallMessage(*msg)
-				_, err = statefull.ApplyBorMessage(*vmenv, callmsg)
+				_, err = statefull.ApplyBorMessage(vmenv, callmsg)
 
 				if writer != nil {
 					writer.Flush()
@@ -1367,7 +1367,7 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	if *config.BorTx {
 		callmsg := prepareCallMessage(*message)
 		// nolint : contextcheck
-		if _, err := statefull.ApplyBorMessage(*vmenv, callmsg); err != nil {
+		if _, err := statefull.ApplyBorMessage(vmenv, callmsg); err != nil {
 			return nil, fmt.Errorf("tracing failed: %w", err)
 		}
 	} else {
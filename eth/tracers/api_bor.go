@@ -7,6 +7,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
@@ -21,6 +23,24 @@ type BlockTraceResult struct {
 
 	// Block that we are executing on the trace
 	Block interface{} `json:"block"`
+
+	// Dependencies declared by the block between its transactions, checked
+	// against their execution
+	TxDependency *TxDependencyResult `json:"txDependency,omitempty"`
+}
+
+// TxDependencyResult reports the dependencies a block declares between its
+// transactions which are wrong.
+type TxDependencyResult struct {
+	// Dependencies declared by the block
+	Declared [][]uint64 `json:"declared"`
+
+	// Reason the declared dependencies are malformed, if they are
+	Error string `json:"error,omitempty"`
+
+	// Earlier transactions the transactions read the writes of, without
+	// declaring a dependency leading to them
+	Missing []core.TxDependencyViolation `json:"missing,omitempty"`
 }
 
 type TxTraceResult struct {
@@ -78,6 +98,25 @@ func (api *API) traceBorBlock(ctx context.Context, block *types.Block, config *T
 
 	blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 
+	// Record the state accessed by the transactions to check the dependencies
+	// declared between them
+	var (
+		declared = block.GetTxDependency()
+		reads    [][]blockstm.ReadDescriptor
+		writes   [][]blockstm.WriteDescriptor
+	)
+
+	if len(declared) > 0 {
+		res.TxDependency = &TxDependencyResult{Declared: declared}
+
+		if err := core.ValidateTxDependency(declared, len(block.Transactions())); err != nil {
+			res.TxDependency.Error = err.Error()
+		} else {
+			reads = make([][]blockstm.ReadDescriptor, len(declared))
+			writes = make([][]blockstm.WriteDescriptor, len(declared))
+		}
+	}
+
 	traceTxn := func(indx int, tx *types.Transaction, borTx bool) *TxTraceResult {
 		message, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
 		txContext := core.NewEVMTxContext(message)
@@ -93,9 +132,21 @@ func (api *API) traceBorBlock(ctx context.Context, block *types.Block, config *T
 
 		var execRes *core.ExecutionResult
 
+		if indx < len(reads) {
+			statedb.AddEmptyMVHashMap()
+
+			defer func() {
+				reads[indx], writes[indx] = statedb.MVReadList(), statedb.MVFullWriteList()
+
+				statedb.SetMVHashmap(nil)
+				statedb.ClearReadMap()
+				statedb.ClearWriteMap()
+			}()
+		}
+
 		if borTx {
 			callmsg := prepareCallMessage(*message)
-			execRes, err = statefull.ApplyBorMessage(*vmenv, callmsg)
+			execRes, err = statefull.ApplyBorMessage(vmenv, callmsg)
 		} else {
 			execRes, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit), nil)
 		}
@@ -133,9 +184,50 @@ func (api *API) traceBorBlock(ctx context.Context, block *types.Block, config *T
 		}
 	}
 
+	if reads != nil {
+		fees := []common.Address{blockCtx.Coinbase}
+		if config := api.backend.ChainConfig(); config.Bor != nil && config.IsLondon(block.Number()) {
+			fees = append(fees, common.HexToAddress(config.Bor.CalculateBurntContract(block.NumberU64())))
+		}
+
+		reads, writes = withoutFees(reads, writes, fees...)
+		res.TxDependency.Missing = core.CheckTxDependency(declared, reads, writes)
+	}
+
 	return res, nil
 }
 
+// withoutFees drops the accesses to the balances the transaction fees are
+// credited to, as the importers credit them out of the parallel execution.
+func withoutFees(reads [][]blockstm.ReadDescriptor, writes [][]blockstm.WriteDescriptor, feeRecipients ...common.Address) ([][]blockstm.ReadDescriptor, [][]blockstm.WriteDescriptor) {
+	fees := make(map[blockstm.Key]struct{}, len(feeRecipients))
+	for _, recipient := range feeRecipients {
+		fees[blockstm.NewSubpathKey(recipient, state.BalancePath)] = struct{}{}
+	}
+
+	txReads := make([][]blockstm.ReadDescriptor, len(reads))
+
+	for i := range reads {
+		for _, read := range reads[i] {
+			if _, ok := fees[read.Path]; !ok {
+				txReads[i] = append(txReads[i], read)
+			}
+		}
+	}
+
+	txWrites := make([][]blockstm.WriteDescriptor, len(writes))
+
+	for i := range writes {
+		for _, write := range writes[i] {
+			if _, ok := fees[write.Path]; !ok {
+				txWrites[i] = append(txWrites[i], write)
+			}
+		}
+	}
+
+	return txReads, txWrites
+}
+
 type TraceBlockRequest struct {
 	Number     int64
 	Hash       string
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// BuilderCommand is the command to group the block builder commands
+type BuilderCommand struct {
+	UI cli.Ui
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *BuilderCommand) MarkDown() string {
+	items := []string{
+		"# Builder",
+		"The ```builder``` command groups actions to inspect the block building of the client:",
+		"- [```builder replay```](./builder_replay.md): Replay the block building out of a mempool recording.",
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *BuilderCommand) Help() string {
+	return `Usage: bor builder <subcommand>
+
+  This command groups actions to inspect the block building.
+
+  Replay the building of a block out of a mempool recording:
+
+    $ bor builder replay --datadir <datadir> --recording <file> --block <number>`
+}
+
+// Synopsis implements the cli.Command interface
+func (c *BuilderCommand) Synopsis() string {
+	return "Inspect the block building"
+}
+
+// Run implements the cli.Command interface
+func (c *BuilderCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
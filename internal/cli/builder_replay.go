@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool/recorder"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+	"github.com/ethereum/go-ethereum/internal/cli/server"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// BuilderReplayCommand is the command to replay the block building out of a
+// mempool recording
+type BuilderReplayCommand struct {
+	*Meta
+
+	datadirAncient string
+	cache          uint64
+	recording      string
+	block          uint64
+	algorithms     []string
+	coinbase       string
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *BuilderReplayCommand) MarkDown() string {
+	items := []string{
+		"# Builder replay",
+		"The ```bor builder replay``` command rebuilds a block with each of the block building algorithms, out of the transactions pending in a mempool recording (see ```txpool.record```) when the block was built and on top of the state of its parent. It outputs the block each algorithm would have produced, with its value and gas used, next to the canonical block. It runs offline on a local datadir, which must hold the state of the parent block.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *BuilderReplayCommand) Help() string {
+	return `Usage: bor builder replay --datadir <datadir> --recording <file> --block <number>
+
+  This command replays the block building out of a mempool recording` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *BuilderReplayCommand) Synopsis() string {
+	return "Replay the block building out of a mempool recording"
+}
+
+// Flags: datadir, datadir.ancient, cache, recording, block, algorithms, coinbase
+func (c *BuilderReplayCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("replay")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "datadir.ancient",
+		Value:   &c.datadirAncient,
+		Usage:   "Path of the ancient data directory to store information",
+		Default: "",
+	})
+
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "cache",
+		Usage:   "Megabytes of memory allocated to internal caching",
+		Value:   &c.cache,
+		Default: 1024.0,
+	})
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:  "recording",
+		Value: &c.recording,
+		Usage: "Path of the mempool recording to replay",
+	})
+
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:  "block",
+		Value: &c.block,
+		Usage: "Number of the block to rebuild",
+	})
+
+	flags.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "algorithms",
+		Value:   &c.algorithms,
+		Usage:   "Comma separated block building algorithms to replay",
+		Default: []string{miner.AlgorithmGreedyTip, miner.AlgorithmGreedyProfit, miner.AlgorithmBundleAware, miner.AlgorithmMulti},
+	})
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:  "coinbase",
+		Value: &c.coinbase,
+		Usage: "Address receiving the fees of the rebuilt blocks (default: the coinbase of the canonical block)",
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *BuilderReplayCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.dataDir == "" {
+		c.UI.Error("datadir is required")
+		return 1
+	}
+
+	if c.recording == "" {
+		c.UI.Error("recording is required")
+		return 1
+	}
+
+	if c.block == 0 {
+		c.UI.Error("block is required")
+		return 1
+	}
+
+	var coinbase *common.Address
+
+	if c.coinbase != "" {
+		if !common.IsHexAddress(c.coinbase) {
+			c.UI.Error(fmt.Sprintf("invalid coinbase address %q", c.coinbase))
+			return 1
+		}
+
+		addr := common.HexToAddress(c.coinbase)
+		coinbase = &addr
+	}
+
+	txs, err := recorder.Mempool(c.recording, c.block)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to read mempool recording: %v", err))
+		return 1
+	}
+
+	// Create the node
+	node, err := node.New(&node.Config{
+		DataDir: c.dataDir,
+	})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer node.Close()
+
+	dbHandles, err := server.MakeDatabaseHandles(0)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	chaindb, err := node.OpenDatabaseWithFreezer(chaindataPath, int(c.cache), dbHandles, c.datadirAncient, "", true, rawdb.ExtraDBConfig{})
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// Blocks are only executed, so the consensus engine doesn't matter
+	chain, err := core.NewBlockChain(chaindb, nil, nil, nil, ethash.NewFaker(), vm.Config{}, nil, nil, nil)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer chain.Stop()
+
+	results, err := miner.Replay(chain, c.block, coinbase, txs, c.algorithms)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to replay block %d: %v", c.block, err))
+		return 1
+	}
+
+	out := make([]string, 0, len(results)+1)
+	out = append(out, "Algorithm|Txs|Gas used|Value (wei)")
+
+	for _, result := range results {
+		out = append(out, fmt.Sprintf("%s|%d|%d|%s", result.Algorithm, result.Txs, result.GasUsed, result.Value))
+	}
+
+	c.UI.Output(fmt.Sprintf("Block %d, %d pending transactions recorded", c.block, len(txs)))
+	c.UI.Output(formatList(out))
+
+	return 0
+}
@@ -77,6 +77,13 @@ func Commands() map[string]MarkDownCommandFactory {
 				UI: ui,
 			}, nil
 		},
+		"relay": func() (MarkDownCommand, error) {
+			return &server.RelayCommand{
+				Command: server.Command{
+					UI: ui,
+				},
+			}, nil
+		},
 		"version": func() (MarkDownCommand, error) {
 			return &VersionCommand{
 				UI: ui,
@@ -102,6 +109,11 @@ func Commands() map[string]MarkDownCommandFactory {
 				Meta2: meta2,
 			}, nil
 		},
+		"debug parallel": func() (MarkDownCommand, error) {
+			return &DebugParallelCommand{
+				Meta2: meta2,
+			}, nil
+		},
 		"chain": func() (MarkDownCommand, error) {
 			return &ChainCommand{
 				UI: ui,
@@ -189,6 +201,16 @@ func Commands() map[string]MarkDownCommandFactory {
 				Meta2: meta2,
 			}, nil
 		},
+		"builder": func() (MarkDownCommand, error) {
+			return &BuilderCommand{
+				UI: ui,
+			}, nil
+		},
+		"builder replay": func() (MarkDownCommand, error) {
+			return &BuilderReplayCommand{
+				Meta: meta,
+			}, nil
+		},
 		"snapshot": func() (MarkDownCommand, error) {
 			return &SnapshotCommand{
 				UI: ui,
@@ -51,6 +51,7 @@ func (d *DebugCommand) MarkDown() string {
 		"The ```bor debug``` command takes a debug dump of the running client.",
 		"- [```bor debug pprof```](./debug_pprof.md): Dumps bor pprof traces.",
 		"- [```bor debug block <number>```](./debug_block.md): Dumps bor block traces.",
+		"- [```bor debug parallel <number>```](./debug_parallel.md): Dumps the profile of the parallel execution of a bor block.",
 	}
 	items = append(items, examples...)
 
@@ -69,7 +70,11 @@ func (c *DebugCommand) Help() string {
 
 	Get the block traces:
 
-		$ bor debug block <number>`
+		$ bor debug block <number>
+
+	Get the parallel execution profile of a block:
+
+		$ bor debug parallel <number>`
 }
 
 // Synopsis implements the cli.Command interface
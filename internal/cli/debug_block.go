@@ -21,6 +21,7 @@ func (p *DebugBlockCommand) MarkDown() string {
 	items := []string{
 		"# Debug trace",
 		"The ```bor debug block <number>``` command will create an archive containing traces of a bor block.",
+		"If the block declares dependencies between its transactions, the traces report the ones missing from the declared dependencies.",
 		p.Flags().MarkDown(),
 	}
 
@@ -31,7 +32,8 @@ func (p *DebugBlockCommand) MarkDown() string {
 func (c *DebugBlockCommand) Help() string {
 	return `Usage: bor debug block <number>
 
-  This command is used get traces of a bor block`
+  This command is used get traces of a bor block, along with the
+  dependencies missing from the ones it declares between its transactions`
 }
 
 func (c *DebugBlockCommand) Flags() *flagset.Flagset {
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+	"github.com/ethereum/go-ethereum/internal/cli/server/proto"
+)
+
+// DebugParallelCommand is the command to profile the parallel execution of a block
+type DebugParallelCommand struct {
+	*Meta2
+
+	output string
+}
+
+func (p *DebugParallelCommand) MarkDown() string {
+	items := []string{
+		"# Debug parallel",
+		"The ```bor debug parallel <number>``` command will create an archive containing the profile of the parallel execution of a bor block.",
+		"The block is executed again with blockstm. The archive holds the profile as ```profile.json```, with the dependencies between the transactions, their incarnations and aborts, the longest path of dependent transactions and the speedup of the execution, and the dependency graph as ```profile.dot``` for Graphviz.",
+		p.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *DebugParallelCommand) Help() string {
+	return `Usage: bor debug parallel <number>
+
+  This command is used to profile the parallel execution of a bor block`
+}
+
+func (c *DebugParallelCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("parallel")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:  "output",
+		Value: &c.output,
+		Usage: "Output directory",
+	})
+
+	return flags
+}
+
+// Synopsis implements the cli.Command interface
+func (c *DebugParallelCommand) Synopsis() string {
+	return "Profile the parallel execution of a bor block"
+}
+
+// Run implements the cli.Command interface
+func (c *DebugParallelCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	var number *int64 = nil
+
+	// parse the block number (if available)
+	if len(args)%2 != 0 {
+		num, err := strconv.ParseInt(args[0], 10, 64)
+		if err == nil {
+			number = &num
+		}
+
+		args = args[1:]
+	}
+	// parse output directory
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	borClt, err := c.BorConn()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	dEnv := &debugEnv{
+		output: c.output,
+		prefix: "bor-parallel-profile-",
+	}
+	if err := dEnv.init(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output("Starting parallel execution profiler...")
+	c.UI.Output("")
+
+	// create a debug block request
+	var debugRequest *proto.DebugBlockRequest = &proto.DebugBlockRequest{}
+	if number != nil {
+		debugRequest.Number = *number
+	} else {
+		debugRequest.Number = -1
+	}
+
+	// send the request
+	// receives a grpc stream of the profile
+	stream, err := borClt.DebugParallel(context.Background(), debugRequest)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := dEnv.writeFromStream("profile.json", stream); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	profile, err := c.writeDot(dEnv)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if err := dEnv.finish(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	pathStrs := make([]string, 0, len(profile.LongestPath))
+	for _, tx := range profile.LongestPath {
+		pathStrs = append(pathStrs, strconv.Itoa(tx))
+	}
+
+	c.UI.Output(formatKV([]string{
+		fmt.Sprintf("Transactions|%d", len(profile.Txs)),
+		fmt.Sprintf("Longest path|(%d) %s", len(profile.LongestPath), strings.Join(pathStrs, "->")),
+		fmt.Sprintf("Longest path time|%v of %v (serial total)", profile.LongestPathTime, profile.SerialTime),
+		fmt.Sprintf("Parallel time|%v", profile.ParallelTime),
+		fmt.Sprintf("Theoretical speedup|%.2fx", profile.TheoreticalSpeedup),
+		fmt.Sprintf("Actual speedup|%.2fx", profile.ActualSpeedup),
+	}))
+	c.UI.Output("")
+
+	if c.output != "" {
+		c.UI.Output(fmt.Sprintf("Created debug directory: %s", dEnv.dst))
+	} else {
+		c.UI.Output(fmt.Sprintf("Created parallel profile archive: %s", dEnv.tarName()))
+	}
+
+	return 0
+}
+
+// writeDot writes the dependency graph of the profile received next to it
+func (c *DebugParallelCommand) writeDot(dEnv *debugEnv) (*blockstm.ExecutionProfile, error) {
+	data, err := os.ReadFile(filepath.Join(dEnv.dst, "profile.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var profile blockstm.ExecutionProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to decode profile: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dEnv.dst, "profile.dot"), []byte(profile.Dot()), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write dependency graph: %v", err)
+	}
+
+	return &profile, nil
+}
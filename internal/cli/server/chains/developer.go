@@ -2,6 +2,7 @@ package chains
 
 import (
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -9,6 +10,17 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// The genesis contracts of the developer bor chain are the ones of the bor
+// tests, which hardcode the sprint length, the first span and its validator.
+const (
+	developerSprint           = 4
+	developerGenesisValidator = "71562b71999873db5b286df957af199ec94617f7"
+
+	// DeveloperFirstSpanEnd is the last block of the first span of the
+	// developer bor chain.
+	DeveloperFirstSpanEnd = 7
+)
+
 // GetDeveloperChain returns the developer mode configs.
 func GetDeveloperChain(period uint64, gasLimitt uint64, faucet common.Address) *Chain {
 	// Override the default period to the user requested one
@@ -29,19 +41,75 @@ func GetDeveloperChain(period uint64, gasLimitt uint64, faucet common.Address) *
 			GasLimit:   gasLimitt,
 			BaseFee:    big.NewInt(params.InitialBaseFee),
 			Difficulty: big.NewInt(1),
-			Alloc: map[common.Address]core.GenesisAccount{
-				common.BytesToAddress([]byte{1}): {Balance: big.NewInt(1)}, // ECRecover
-				common.BytesToAddress([]byte{2}): {Balance: big.NewInt(1)}, // SHA256
-				common.BytesToAddress([]byte{3}): {Balance: big.NewInt(1)}, // RIPEMD
-				common.BytesToAddress([]byte{4}): {Balance: big.NewInt(1)}, // Identity
-				common.BytesToAddress([]byte{5}): {Balance: big.NewInt(1)}, // ModExp
-				common.BytesToAddress([]byte{6}): {Balance: big.NewInt(1)}, // ECAdd
-				common.BytesToAddress([]byte{7}): {Balance: big.NewInt(1)}, // ECScalarMul
-				common.BytesToAddress([]byte{8}): {Balance: big.NewInt(1)}, // ECPairing
-				common.BytesToAddress([]byte{9}): {Balance: big.NewInt(1)}, // BLAKE2b
-				faucet:                           {Balance: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))},
-			},
+			Alloc:      developerAlloc(faucet),
 		},
 		Bootnodes: []string{},
 	}
 }
+
+// GetDeveloperBorChain returns the developer mode configs running bor
+// consensus, with the genesis contracts deployed and the faucet as validator
+// of the first span. The period is at least a second.
+func GetDeveloperBorChain(period uint64, gasLimit uint64, faucet common.Address) *Chain {
+	if period == 0 {
+		period = 1
+	}
+
+	config := *params.AllCliqueProtocolChanges
+	config.Clique = nil
+	config.Bor = &params.BorConfig{
+		JaipurBlock:                big.NewInt(0),
+		DelhiBlock:                 big.NewInt(0),
+		IndoreBlock:                big.NewInt(0),
+		StateSyncConfirmationDelay: map[string]uint64{"0": 0},
+		Period:                     map[string]uint64{"0": period},
+		ProducerDelay:              map[string]uint64{"0": period},
+		Sprint:                     map[string]uint64{"0": developerSprint},
+		BackupMultiplier:           map[string]uint64{"0": period},
+		ValidatorContract:          "0x0000000000000000000000000000000000001000",
+		StateReceiverContract:      "0x0000000000000000000000000000000000001001",
+		BurntContract:              config.Bor.BurntContract,
+	}
+
+	alloc := developerAlloc(faucet)
+
+	for address, account := range readPrealloc("allocs/developer.json") {
+		if address == common.HexToAddress(config.Bor.ValidatorContract) {
+			code := strings.Replace(common.Bytes2Hex(account.Code), developerGenesisValidator, common.Bytes2Hex(faucet[:]), 1)
+			account.Code = common.Hex2Bytes(code)
+		}
+
+		alloc[address] = account
+	}
+
+	return &Chain{
+		Hash:      common.Hash{},
+		NetworkId: 1337,
+		Genesis: &core.Genesis{
+			Config:     &config,
+			ExtraData:  make([]byte, 32+crypto.SignatureLength),
+			GasLimit:   gasLimit,
+			BaseFee:    big.NewInt(params.InitialBaseFee),
+			Difficulty: big.NewInt(1),
+			Alloc:      alloc,
+		},
+		Bootnodes: []string{},
+	}
+}
+
+// developerAlloc returns the genesis allocation of the developer chains, with
+// the precompiles and faucet pre-funded.
+func developerAlloc(faucet common.Address) core.GenesisAlloc {
+	return core.GenesisAlloc{
+		common.BytesToAddress([]byte{1}): {Balance: big.NewInt(1)}, // ECRecover
+		common.BytesToAddress([]byte{2}): {Balance: big.NewInt(1)}, // SHA256
+		common.BytesToAddress([]byte{3}): {Balance: big.NewInt(1)}, // RIPEMD
+		common.BytesToAddress([]byte{4}): {Balance: big.NewInt(1)}, // Identity
+		common.BytesToAddress([]byte{5}): {Balance: big.NewInt(1)}, // ModExp
+		common.BytesToAddress([]byte{6}): {Balance: big.NewInt(1)}, // ECAdd
+		common.BytesToAddress([]byte{7}): {Balance: big.NewInt(1)}, // ECScalarMul
+		common.BytesToAddress([]byte{8}): {Balance: big.NewInt(1)}, // ECPairing
+		common.BytesToAddress([]byte{9}): {Balance: big.NewInt(1)}, // BLAKE2b
+		faucet:                           {Balance: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))},
+	}
+}
@@ -134,6 +134,12 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	return c.run()
+}
+
+// run starts the server with the extracted configuration and blocks until it
+// is shut down.
+func (c *Command) run() int {
 	if c.config.Heimdall.RunHeimdall {
 		shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 		defer stop()
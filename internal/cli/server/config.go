@@ -22,15 +22,19 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/builder"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/simulated"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/internal/cli/server/chains"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
@@ -106,6 +110,12 @@ type Config struct {
 	// TxPool has the transaction pool related settings
 	TxPool *TxPoolConfig `hcl:"txpool,block" toml:"txpool,block"`
 
+	// BundlePool has the bundle pool related settings
+	BundlePool *BundlePoolConfig `hcl:"bundlepool,block" toml:"bundlepool,block"`
+
+	// PrivatePool has the private transaction pool related settings
+	PrivatePool *PrivatePoolConfig `hcl:"privatepool,block" toml:"privatepool,block"`
+
 	// Sealer has the validator related settings
 	Sealer *SealerConfig `hcl:"miner,block" toml:"miner,block"`
 
@@ -135,6 +145,12 @@ type Config struct {
 	// ParallelEVM has the parallel evm related settings
 	ParallelEVM *ParallelEVMConfig `hcl:"parallelevm,block" toml:"parallelevm,block"`
 
+	// Builder has the block builder mode related settings
+	Builder *BuilderConfig `hcl:"builder,block" toml:"builder,block"`
+
+	// Relay has the block relay related settings
+	Relay *RelayConfig `hcl:"relay,block" toml:"relay,block"`
+
 	// Develop Fake Author mode to produce blocks without authorisation
 	DevFakeAuthor bool `hcl:"devfakeauthor,optional" toml:"devfakeauthor,optional"`
 
@@ -217,6 +233,9 @@ type P2PConfig struct {
 	// an announced transaction to arrive before explicitly requesting it
 	TxArrivalWait    time.Duration `hcl:"-,optional" toml:"-"`
 	TxArrivalWaitRaw string        `hcl:"txarrivalwait,optional" toml:"txarrivalwait,optional"`
+
+	// ConditionalTxs exchanges conditional transactions along with their options with trusted peers
+	ConditionalTxs bool `hcl:"conditionaltxs,optional" toml:"conditionaltxs,optional"`
 }
 
 type P2PDiscovery struct {
@@ -243,15 +262,18 @@ type P2PDiscovery struct {
 }
 
 type HeimdallConfig struct {
-	// URL is the url of the heimdall server
+	// URL is the url of the heimdall server, or comma separated urls to fail over between
 	URL string `hcl:"url,optional" toml:"url,optional"`
 
 	// Without is used to disable remote heimdall during testing
 	Without bool `hcl:"bor.without,optional" toml:"bor.without,optional"`
 
-	// GRPCAddress is the address of the heimdall grpc server
+	// GRPCAddress is the address of the heimdall grpc server, or comma separated addresses to fail over between
 	GRPCAddress string `hcl:"grpc-address,optional" toml:"grpc-address,optional"`
 
+	// CrossCheck is used to cross-check the spans and milestones between the heimdall endpoints
+	CrossCheck bool `hcl:"bor.crosscheck,optional" toml:"bor.crosscheck,optional"`
+
 	// RunHeimdall is used to run heimdall as a child process
 	RunHeimdall bool `hcl:"bor.runheimdall,optional" toml:"bor.runheimdall,optional"`
 
@@ -297,6 +319,34 @@ type TxPoolConfig struct {
 	// lifetime is the maximum amount of time non-executable transaction are queued
 	LifeTime    time.Duration `hcl:"-,optional" toml:"-"`
 	LifeTimeRaw string        `hcl:"lifetime,optional" toml:"lifetime,optional"`
+
+	// Record is the path to record the transaction pool events and chain heads to
+	Record string `hcl:"record,optional" toml:"record,optional"`
+}
+
+type BundlePoolConfig struct {
+	// Bundles is the maximum number of bundles kept in the pool
+	Bundles uint64 `hcl:"bundles,optional" toml:"bundles,optional"`
+
+	// BundleTxs is the maximum number of transactions in a single bundle
+	BundleTxs uint64 `hcl:"bundletxs,optional" toml:"bundletxs,optional"`
+
+	// BlocksAhead is the maximum distance between the head and the target block of a bundle
+	BlocksAhead uint64 `hcl:"blocksahead,optional" toml:"blocksahead,optional"`
+
+	// SenderBundles is the maximum number of bundles holding transactions of a single sender
+	SenderBundles uint64 `hcl:"senderbundles,optional" toml:"senderbundles,optional"`
+}
+
+type PrivatePoolConfig struct {
+	// Txs is the maximum number of private transactions kept in the pool
+	Txs uint64 `hcl:"txs,optional" toml:"txs,optional"`
+
+	// SenderTxs is the maximum number of private transactions of a single sender
+	SenderTxs uint64 `hcl:"sendertxs,optional" toml:"sendertxs,optional"`
+
+	// BlocksAhead is the maximum distance between the head and the max block number of a private transaction
+	BlocksAhead uint64 `hcl:"blocksahead,optional" toml:"blocksahead,optional"`
 }
 
 type SealerConfig struct {
@@ -321,6 +371,34 @@ type SealerConfig struct {
 	RecommitRaw string        `hcl:"recommit,optional" toml:"recommit,optional"`
 
 	CommitInterruptFlag bool `hcl:"commitinterrupt,optional" toml:"commitinterrupt,optional"`
+
+	// Builders is the list of external block builder endpoints asked for in-turn block bodies
+	Builders []string `hcl:"builders,optional" toml:"builders,optional"`
+
+	// The maximum time to wait for external builder bids
+	BuilderTimeout    time.Duration `hcl:"-,optional" toml:"-"`
+	BuilderTimeoutRaw string        `hcl:"buildertimeout,optional" toml:"buildertimeout,optional"`
+
+	// Time before the block timestamp at which the external builder auction closes
+	BuilderCutoff    time.Duration `hcl:"-,optional" toml:"-"`
+	BuilderCutoffRaw string        `hcl:"buildercutoff,optional" toml:"buildercutoff,optional"`
+
+	// EngineAPI serves the engine API on the authenticated RPC endpoint so that external block producers can drive block building
+	EngineAPI bool `hcl:"engineapi,optional" toml:"engineapi,optional"`
+
+	// ConditionalGasShare is the percentage of the block gas limit conditional transactions are committed in ahead of the others
+	ConditionalGasShare uint64 `hcl:"conditionalgasshare,optional" toml:"conditionalgasshare,optional"`
+
+	// Algorithm is the block building algorithm picking and ordering the pending transactions
+	Algorithm string `hcl:"algorithm,optional" toml:"algorithm,optional"`
+
+	// ParallelBuild executes the candidate transactions in parallel with the
+	// parallel EVM processes, and records their dependencies in the blocks
+	ParallelBuild bool `hcl:"parallel,optional" toml:"parallel,optional"`
+
+	// ParallelOrder reorders the candidate transactions tipping the same out of
+	// their parallel execution, to shorten the chains of dependent transactions
+	ParallelOrder bool `hcl:"parallelorder,optional" toml:"parallelorder,optional"`
 }
 
 type JsonRPCConfig struct {
@@ -586,6 +664,15 @@ type DeveloperConfig struct {
 
 	// Initial block gas limit
 	GasLimit uint64 `hcl:"gaslimit,optional" toml:"gaslimit,optional"`
+
+	// Heimdall runs bor consensus against a simulated Heimdall instead of clique
+	Heimdall bool `hcl:"heimdall,optional" toml:"heimdall,optional"`
+
+	// Validators is the validator set of the simulated spans, as address:power
+	Validators []string `hcl:"validators,optional" toml:"validators,optional"`
+
+	// StateSyncs is the JSON file of the state sync events of the simulated Heimdall
+	StateSyncs string `hcl:"state-syncs,optional" toml:"state-syncs,optional"`
 }
 
 type ParallelEVMConfig struct {
@@ -594,6 +681,36 @@ type ParallelEVMConfig struct {
 	SpeculativeProcesses int `hcl:"procs,optional" toml:"procs,optional"`
 }
 
+type BuilderConfig struct {
+	// Enabled runs the node as a block builder submitting blocks to a relay
+	Enabled bool `hcl:"enabled,optional" toml:"enabled,optional"`
+
+	// RelayURL is the RPC endpoint of the relay receiving the built blocks
+	RelayURL string `hcl:"relay,optional" toml:"relay,optional"`
+
+	// KeyFile is the file holding the key bids are signed with (defaults to the node key)
+	KeyFile string `hcl:"key,optional" toml:"key,optional"`
+
+	// Proposers is the number of upcoming proposers to build blocks for
+	Proposers uint64 `hcl:"proposers,optional" toml:"proposers,optional"`
+
+	// The time interval for the builder to rebuild and resubmit the next block
+	Interval    time.Duration `hcl:"-,optional" toml:"-"`
+	IntervalRaw string        `hcl:"interval,optional" toml:"interval,optional"`
+
+	// Payout is the strategy paying proposers out of the blocks built on their behalf (proportional or fixed)
+	Payout string `hcl:"payout,optional" toml:"payout,optional"`
+
+	// Margin is the share of the profit kept by the builder, in basis points or wei depending on the payout strategy
+	Margin    *big.Int `hcl:"-,optional" toml:"-"`
+	MarginRaw string   `hcl:"margin,optional" toml:"margin,optional"`
+}
+
+type RelayConfig struct {
+	// Enabled validates builder submissions and serves the best bids to proposers
+	Enabled bool `hcl:"enabled,optional" toml:"enabled,optional"`
+}
+
 func DefaultConfig() *Config {
 	return &Config{
 		Chain:                   "mainnet",
@@ -654,6 +771,17 @@ func DefaultConfig() *Config {
 			GlobalQueue:  32768,
 			LifeTime:     3 * time.Hour,
 		},
+		BundlePool: &BundlePoolConfig{
+			Bundles:       1024,
+			BundleTxs:     64,
+			BlocksAhead:   128,
+			SenderBundles: 16,
+		},
+		PrivatePool: &PrivatePoolConfig{
+			Txs:         4096,
+			SenderTxs:   16,
+			BlocksAhead: 128,
+		},
 		Sealer: &SealerConfig{
 			Enabled:             false,
 			Etherbase:           "",
@@ -662,6 +790,9 @@ func DefaultConfig() *Config {
 			ExtraData:           "",
 			Recommit:            125 * time.Second,
 			CommitInterruptFlag: true,
+			Builders:            []string{},
+			BuilderTimeout:      500 * time.Millisecond,
+			Algorithm:           miner.AlgorithmGreedyTip,
 		},
 		Gpo: &GpoConfig{
 			Blocks:           20,
@@ -770,9 +901,12 @@ func DefaultConfig() *Config {
 			Addr: ":3131",
 		},
 		Developer: &DeveloperConfig{
-			Enabled:  false,
-			Period:   0,
-			GasLimit: 11500000,
+			Enabled:    false,
+			Period:     0,
+			GasLimit:   11500000,
+			Heimdall:   false,
+			Validators: []string{},
+			StateSyncs: "",
 		},
 		DevFakeAuthor: false,
 		Pprof: &PprofConfig{
@@ -787,6 +921,18 @@ func DefaultConfig() *Config {
 			Enable:               true,
 			SpeculativeProcesses: 8,
 		},
+		Builder: &BuilderConfig{
+			Enabled:   false,
+			RelayURL:  "",
+			KeyFile:   "",
+			Proposers: 1,
+			Interval:  500 * time.Millisecond,
+			Payout:    "",
+			Margin:    big.NewInt(0),
+		},
+		Relay: &RelayConfig{
+			Enabled: false,
+		},
 	}
 }
 
@@ -799,6 +945,7 @@ func (c *Config) fillBigInt() error {
 		{"gpo.maxprice", &c.Gpo.MaxPrice, &c.Gpo.MaxPriceRaw},
 		{"gpo.ignoreprice", &c.Gpo.IgnorePrice, &c.Gpo.IgnorePriceRaw},
 		{"miner.gasprice", &c.Sealer.GasPrice, &c.Sealer.GasPriceRaw},
+		{"builder.margin", &c.Builder.Margin, &c.Builder.MarginRaw},
 	}
 
 	for _, x := range tds {
@@ -833,6 +980,9 @@ func (c *Config) fillTimeDurations() error {
 	}{
 		{"jsonrpc.evmtimeout", &c.JsonRPC.RPCEVMTimeout, &c.JsonRPC.RPCEVMTimeoutRaw},
 		{"miner.recommit", &c.Sealer.Recommit, &c.Sealer.RecommitRaw},
+		{"miner.buildertimeout", &c.Sealer.BuilderTimeout, &c.Sealer.BuilderTimeoutRaw},
+		{"miner.buildercutoff", &c.Sealer.BuilderCutoff, &c.Sealer.BuilderCutoffRaw},
+		{"builder.interval", &c.Builder.Interval, &c.Builder.IntervalRaw},
 		{"jsonrpc.timeouts.read", &c.JsonRPC.HttpTimeout.ReadTimeout, &c.JsonRPC.HttpTimeout.ReadTimeoutRaw},
 		{"jsonrpc.timeouts.write", &c.JsonRPC.HttpTimeout.WriteTimeout, &c.JsonRPC.HttpTimeout.WriteTimeoutRaw},
 		{"jsonrpc.timeouts.idle", &c.JsonRPC.HttpTimeout.IdleTimeout, &c.JsonRPC.HttpTimeout.IdleTimeoutRaw},
@@ -867,9 +1017,13 @@ func readConfigFile(path string) (*Config, error) {
 	}
 
 	config := &Config{
-		TxPool: &TxPoolConfig{},
-		Cache:  &CacheConfig{},
-		Sealer: &SealerConfig{},
+		TxPool:      &TxPoolConfig{},
+		BundlePool:  &BundlePoolConfig{},
+		PrivatePool: &PrivatePoolConfig{},
+		Cache:       &CacheConfig{},
+		Sealer:      &SealerConfig{},
+		Builder:     &BuilderConfig{},
+		Relay:       &RelayConfig{},
 	}
 
 	if err := hclsimple.DecodeFile(path, nil, config); err != nil {
@@ -921,7 +1075,9 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 
 	n.HeimdallURL = c.Heimdall.URL
 	n.WithoutHeimdall = c.Heimdall.Without
+	n.ConditionalTxProtocol = c.P2P.ConditionalTxs
 	n.HeimdallgRPCAddress = c.Heimdall.GRPCAddress
+	n.HeimdallCrossCheck = c.Heimdall.CrossCheck
 	n.RunHeimdall = c.Heimdall.RunHeimdall
 	n.RunHeimdallArgs = c.Heimdall.RunHeimdallArgs
 	n.UseHeimdallApp = c.Heimdall.UseHeimdallApp
@@ -956,6 +1112,22 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		n.TxPool.AccountQueue = c.TxPool.AccountQueue
 		n.TxPool.GlobalQueue = c.TxPool.GlobalQueue
 		n.TxPool.Lifetime = c.TxPool.LifeTime
+		n.MempoolRecording = c.TxPool.Record
+	}
+
+	// bundlepool options
+	{
+		n.BundlePool.MaxBundles = int(c.BundlePool.Bundles)
+		n.BundlePool.MaxBundleTxs = int(c.BundlePool.BundleTxs)
+		n.BundlePool.MaxBlocksAhead = c.BundlePool.BlocksAhead
+		n.BundlePool.MaxSenderBundles = int(c.BundlePool.SenderBundles)
+	}
+
+	// privatepool options
+	{
+		n.PrivatePool.MaxTxs = int(c.PrivatePool.Txs)
+		n.PrivatePool.MaxSenderTxs = int(c.PrivatePool.SenderTxs)
+		n.PrivatePool.MaxBlocksAhead = c.PrivatePool.BlocksAhead
 	}
 
 	// miner options
@@ -965,6 +1137,35 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		n.Miner.GasCeil = c.Sealer.GasCeil
 		n.Miner.ExtraData = []byte(c.Sealer.ExtraData)
 		n.Miner.CommitInterruptFlag = c.Sealer.CommitInterruptFlag
+		n.Miner.Builders = c.Sealer.Builders
+		n.Miner.BuilderTimeout = c.Sealer.BuilderTimeout
+		n.Miner.BuilderCutoff = c.Sealer.BuilderCutoff
+		n.Miner.ConditionalGasShare = c.Sealer.ConditionalGasShare
+
+		if _, err := miner.NewBlockBuildingAlgorithm(c.Sealer.Algorithm); err != nil {
+			return nil, err
+		}
+
+		n.Miner.Algorithm = c.Sealer.Algorithm
+		n.Miner.ParallelBuild = c.Sealer.ParallelBuild
+		n.Miner.ParallelOrder = c.Sealer.ParallelOrder
+
+		if payout := c.Builder.Payout; payout != "" {
+			if payout != miner.PayoutProportional && payout != miner.PayoutFixed {
+				return nil, fmt.Errorf("unknown builder payout strategy: %s", payout)
+			}
+
+			key, err := c.builderKey(stack)
+			if err != nil {
+				return nil, err
+			}
+
+			n.Miner.Payout = miner.PayoutConfig{
+				Key:      key,
+				Strategy: payout,
+				Margin:   c.Builder.Margin,
+			}
+		}
 
 		if etherbase := c.Sealer.Etherbase; etherbase != "" {
 			if !common.IsHexAddress(etherbase) {
@@ -1035,7 +1236,15 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		n.Miner.Etherbase = developer.Address
 
 		// get developer mode chain config
-		c.chain = chains.GetDeveloperChain(c.Developer.Period, c.Developer.GasLimit, developer.Address)
+		if c.Developer.Heimdall {
+			c.chain = chains.GetDeveloperBorChain(c.Developer.Period, c.Developer.GasLimit, developer.Address)
+
+			if n.DevHeimdall, err = c.Developer.buildHeimdall(c.chain, developer.Address); err != nil {
+				return nil, err
+			}
+		} else {
+			c.chain = chains.GetDeveloperChain(c.Developer.Period, c.Developer.GasLimit, developer.Address)
+		}
 
 		// update the parameters
 		n.NetworkId = c.chain.NetworkId
@@ -1295,6 +1504,38 @@ func getNodeKey(hex string, file string) *ecdsa.PrivateKey {
 	return nil
 }
 
+// buildBuilder returns the block builder settings along with the key its bids
+// are signed with, falling back to the node key if no key file is set.
+func (c *Config) buildBuilder(stack *node.Node) (*builder.Config, *ecdsa.PrivateKey, error) {
+	key, err := c.builderKey(stack)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := &builder.Config{
+		RelayURL:  c.Builder.RelayURL,
+		Interval:  c.Builder.Interval,
+		Proposers: int(c.Builder.Proposers),
+	}
+
+	return cfg, key, nil
+}
+
+// builderKey returns the key of the builder, which is the node key unless a
+// key file is set.
+func (c *Config) builderKey(stack *node.Node) (*ecdsa.PrivateKey, error) {
+	if c.Builder.KeyFile == "" {
+		return stack.Config().NodeKey(), nil
+	}
+
+	key, err := crypto.LoadECDSA(c.Builder.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load builder key: %v", err)
+	}
+
+	return key, nil
+}
+
 func (c *Config) buildNode() (*node.Config, error) {
 	ipcPath := ""
 	if !c.JsonRPC.IPCDisable {
@@ -1476,6 +1717,49 @@ func MakeDatabaseHandles(max int) (int, error) {
 	return int(raised / 2), nil // Leave half for networking and other stuff
 }
 
+// buildHeimdall returns the config of the simulated Heimdall of the developer
+// chain, the developer being the only validator unless configured otherwise.
+func (c *DeveloperConfig) buildHeimdall(chain *chains.Chain, developer common.Address) (*simulated.Config, error) {
+	config := &simulated.Config{
+		ChainID:       chain.Genesis.Config.ChainID.String(),
+		FirstSpanEnd:  chains.DeveloperFirstSpanEnd,
+		Confirmations: simulated.DefaultConfirmations,
+	}
+
+	if len(c.Validators) == 0 {
+		config.Validators = []*valset.Validator{valset.NewValidator(developer, 10000)}
+	}
+
+	for i, validator := range c.Validators {
+		address, power, found := strings.Cut(validator, ":")
+		if !found || !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid developer validator %q, expected address:power", validator)
+		}
+
+		votingPower, err := strconv.ParseInt(power, 10, 64)
+		if err != nil || votingPower <= 0 {
+			return nil, fmt.Errorf("invalid voting power of developer validator %q", validator)
+		}
+
+		config.Validators = append(config.Validators, &valset.Validator{
+			ID:          uint64(i + 1),
+			Address:     common.HexToAddress(address),
+			VotingPower: votingPower,
+		})
+	}
+
+	if c.StateSyncs != "" {
+		events, err := simulated.LoadStateSyncs(c.StateSyncs, config.ChainID)
+		if err != nil {
+			return nil, err
+		}
+
+		config.StateSyncs = events
+	}
+
+	return config, nil
+}
+
 func parseBootnodes(urls []string) ([]*enode.Node, error) {
 	dst := []*enode.Node{}
 
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/internal/cli/server/chains"
 )
 
 func TestConfigDefault(t *testing.T) {
@@ -133,3 +136,36 @@ func TestMakePasswordListFromFile(t *testing.T) {
 		assert.Equal(t, []string{"test1", "test2"}, result)
 	})
 }
+
+func TestDeveloperHeimdall(t *testing.T) {
+	t.Parallel()
+
+	developer := common.HexToAddress("0x1")
+	chain := chains.GetDeveloperBorChain(0, 11500000, developer)
+
+	t.Run("DefaultValidators", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := (&DeveloperConfig{}).buildHeimdall(chain, developer)
+		assert.NoError(t, err)
+		assert.Equal(t, "1337", config.ChainID)
+		assert.Len(t, config.Validators, 1)
+		assert.Equal(t, developer, config.Validators[0].Address)
+	})
+	t.Run("Validators", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := (&DeveloperConfig{Validators: []string{"0x1:10", "0x0000000000000000000000000000000000000002:20"}}).buildHeimdall(chain, developer)
+		assert.Error(t, err)
+		assert.Nil(t, config)
+
+		config, err = (&DeveloperConfig{Validators: []string{"0x0000000000000000000000000000000000000001:10", "0x0000000000000000000000000000000000000002:20"}}).buildHeimdall(chain, developer)
+		assert.NoError(t, err)
+		assert.Len(t, config.Validators, 2)
+		assert.Equal(t, uint64(2), config.Validators[1].ID)
+		assert.Equal(t, int64(20), config.Validators[1].VotingPower)
+
+		_, err = (&DeveloperConfig{Validators: []string{"0x0000000000000000000000000000000000000001:0"}}).buildHeimdall(chain, developer)
+		assert.Error(t, err)
+	})
+}
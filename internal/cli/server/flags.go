@@ -145,7 +145,7 @@ func (c *Command) Flags() *flagset.Flagset {
 	// heimdall
 	f.StringFlag(&flagset.StringFlag{
 		Name:    "bor.heimdall",
-		Usage:   "URL of Heimdall service",
+		Usage:   "URL of Heimdall service, or comma separated URLs to fail over between",
 		Value:   &c.cliConfig.Heimdall.URL,
 		Default: c.cliConfig.Heimdall.URL,
 	})
@@ -163,10 +163,16 @@ func (c *Command) Flags() *flagset.Flagset {
 	})
 	f.StringFlag(&flagset.StringFlag{
 		Name:    "bor.heimdallgRPC",
-		Usage:   "Address of Heimdall gRPC service",
+		Usage:   "Address of Heimdall gRPC service, or comma separated addresses to fail over between",
 		Value:   &c.cliConfig.Heimdall.GRPCAddress,
 		Default: c.cliConfig.Heimdall.GRPCAddress,
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.heimdallcrosscheck",
+		Usage:   "Cross-check the spans and milestones between the Heimdall endpoints, to detect a lying or stale one",
+		Value:   &c.cliConfig.Heimdall.CrossCheck,
+		Default: c.cliConfig.Heimdall.CrossCheck,
+	})
 	f.BoolFlag(&flagset.BoolFlag{
 		Name:    "bor.runheimdall",
 		Usage:   "Run Heimdall service as a child process",
@@ -264,6 +270,66 @@ func (c *Command) Flags() *flagset.Flagset {
 		Default: c.cliConfig.TxPool.LifeTime,
 		Group:   "Transaction Pool",
 	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "txpool.record",
+		Usage:   "File to record the transaction pool events and chain heads to, for replaying block building offline",
+		Value:   &c.cliConfig.TxPool.Record,
+		Default: c.cliConfig.TxPool.Record,
+		Group:   "Transaction Pool",
+	})
+
+	// bundlepool options
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bundlepool.bundles",
+		Usage:   "Maximum number of bundles kept in the pool",
+		Value:   &c.cliConfig.BundlePool.Bundles,
+		Default: c.cliConfig.BundlePool.Bundles,
+		Group:   "Bundle Pool",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bundlepool.bundletxs",
+		Usage:   "Maximum number of transactions in a single bundle",
+		Value:   &c.cliConfig.BundlePool.BundleTxs,
+		Default: c.cliConfig.BundlePool.BundleTxs,
+		Group:   "Bundle Pool",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bundlepool.blocksahead",
+		Usage:   "Maximum number of blocks between the head and the target block of a bundle",
+		Value:   &c.cliConfig.BundlePool.BlocksAhead,
+		Default: c.cliConfig.BundlePool.BlocksAhead,
+		Group:   "Bundle Pool",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bundlepool.senderbundles",
+		Usage:   "Maximum number of bundles holding transactions of a single sender",
+		Value:   &c.cliConfig.BundlePool.SenderBundles,
+		Default: c.cliConfig.BundlePool.SenderBundles,
+		Group:   "Bundle Pool",
+	})
+
+	// privatepool options
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "privatepool.txs",
+		Usage:   "Maximum number of private transactions kept in the pool",
+		Value:   &c.cliConfig.PrivatePool.Txs,
+		Default: c.cliConfig.PrivatePool.Txs,
+		Group:   "Private Transaction Pool",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "privatepool.sendertxs",
+		Usage:   "Maximum number of private transactions of a single sender kept in the pool",
+		Value:   &c.cliConfig.PrivatePool.SenderTxs,
+		Default: c.cliConfig.PrivatePool.SenderTxs,
+		Group:   "Private Transaction Pool",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "privatepool.blocksahead",
+		Usage:   "Maximum number of blocks between the head and the max block number of a private transaction",
+		Value:   &c.cliConfig.PrivatePool.BlocksAhead,
+		Default: c.cliConfig.PrivatePool.BlocksAhead,
+		Group:   "Private Transaction Pool",
+	})
 
 	// sealer options
 	f.BoolFlag(&flagset.BoolFlag{
@@ -315,6 +381,122 @@ func (c *Command) Flags() *flagset.Flagset {
 		Default: c.cliConfig.Sealer.CommitInterruptFlag,
 		Group:   "Sealer",
 	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "miner.builders",
+		Usage:   "Comma separated RPC endpoints of external block builders to request in-turn block bodies from",
+		Value:   &c.cliConfig.Sealer.Builders,
+		Default: c.cliConfig.Sealer.Builders,
+		Group:   "Sealer",
+	})
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "miner.buildertimeout",
+		Usage:   "The maximum time to wait for external builder bids before sealing the local block",
+		Value:   &c.cliConfig.Sealer.BuilderTimeout,
+		Default: c.cliConfig.Sealer.BuilderTimeout,
+		Group:   "Sealer",
+	})
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "miner.buildercutoff",
+		Usage:   "Time before the block timestamp at which the external builder auction closes (0 = only bounded by the builder timeout)",
+		Value:   &c.cliConfig.Sealer.BuilderCutoff,
+		Default: c.cliConfig.Sealer.BuilderCutoff,
+		Group:   "Sealer",
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "miner.engineapi",
+		Usage:   "Serve the engine API on the authenticated RPC endpoint, letting external block producers drive block building",
+		Value:   &c.cliConfig.Sealer.EngineAPI,
+		Default: c.cliConfig.Sealer.EngineAPI,
+		Group:   "Sealer",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "miner.conditionalgasshare",
+		Usage:   "Percentage of the block gas limit conditional (EIP-4337) transactions are committed in ahead of the others (0 = no separate lane)",
+		Value:   &c.cliConfig.Sealer.ConditionalGasShare,
+		Default: c.cliConfig.Sealer.ConditionalGasShare,
+		Group:   "Sealer",
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "miner.algorithm",
+		Usage:   "Block building algorithm picking and ordering the pending transactions (greedy, profit, bundles or multi)",
+		Value:   &c.cliConfig.Sealer.Algorithm,
+		Default: c.cliConfig.Sealer.Algorithm,
+		Group:   "Sealer",
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "miner.parallel",
+		Usage:   "Execute the candidate transactions of the blocks in parallel, recording their dependencies for the importers",
+		Value:   &c.cliConfig.Sealer.ParallelBuild,
+		Default: c.cliConfig.Sealer.ParallelBuild,
+		Group:   "Sealer",
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "miner.parallelorder",
+		Usage:   "Reorder the transactions tipping the same to shorten the chains of dependent transactions in the blocks built in parallel",
+		Value:   &c.cliConfig.Sealer.ParallelOrder,
+		Default: c.cliConfig.Sealer.ParallelOrder,
+		Group:   "Sealer",
+	})
+
+	// builder options
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "builder",
+		Usage:   "Run the node as a block builder submitting blocks for the upcoming proposers to a relay",
+		Value:   &c.cliConfig.Builder.Enabled,
+		Default: c.cliConfig.Builder.Enabled,
+		Group:   "Builder",
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "builder.relay",
+		Usage:   "RPC endpoint of the relay receiving the built blocks",
+		Value:   &c.cliConfig.Builder.RelayURL,
+		Default: c.cliConfig.Builder.RelayURL,
+		Group:   "Builder",
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "builder.key",
+		Usage:   "Path to the key file bids are signed with (default = node key)",
+		Value:   &c.cliConfig.Builder.KeyFile,
+		Default: c.cliConfig.Builder.KeyFile,
+		Group:   "Builder",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "builder.proposers",
+		Usage:   "Number of upcoming proposers to build blocks for, starting with the in-turn one",
+		Value:   &c.cliConfig.Builder.Proposers,
+		Default: c.cliConfig.Builder.Proposers,
+		Group:   "Builder",
+	})
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "builder.interval",
+		Usage:   "The time interval for the builder to rebuild and resubmit the next block",
+		Value:   &c.cliConfig.Builder.Interval,
+		Default: c.cliConfig.Builder.Interval,
+		Group:   "Builder",
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "builder.payout",
+		Usage:   "Pay proposers out of the blocks built on their behalf with a final transfer from the builder key (proportional or fixed)",
+		Value:   &c.cliConfig.Builder.Payout,
+		Default: c.cliConfig.Builder.Payout,
+		Group:   "Builder",
+	})
+	f.BigIntFlag(&flagset.BigIntFlag{
+		Name:    "builder.margin",
+		Usage:   "Share of the block profit kept by the builder, in basis points for proportional and wei for fixed payouts",
+		Value:   c.cliConfig.Builder.Margin,
+		Default: c.cliConfig.Builder.Margin,
+		Group:   "Builder",
+	})
+
+	// relay options
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "relay",
+		Usage:   "Validate blocks submitted by builders and serve the best bid of every slot to the in-turn proposer",
+		Value:   &c.cliConfig.Relay.Enabled,
+		Default: c.cliConfig.Relay.Enabled,
+		Group:   "Relay",
+	})
 
 	// ethstats
 	f.StringFlag(&flagset.StringFlag{
@@ -788,6 +970,13 @@ func (c *Command) Flags() *flagset.Flagset {
 		Default: c.cliConfig.P2P.TxArrivalWait,
 		Group:   "P2P",
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "conditionaltxs",
+		Usage:   "Exchange conditional (EIP-4337) transactions along with their options with trusted peers",
+		Value:   &c.cliConfig.P2P.ConditionalTxs,
+		Default: c.cliConfig.P2P.ConditionalTxs,
+		Group:   "P2P",
+	})
 
 	// metrics
 	f.BoolFlag(&flagset.BoolFlag{
@@ -967,6 +1156,24 @@ func (c *Command) Flags() *flagset.Flagset {
 		Value:   &c.cliConfig.Developer.GasLimit,
 		Default: c.cliConfig.Developer.GasLimit,
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "dev.heimdall",
+		Usage:   "Run bor consensus in developer mode against a simulated Heimdall producing spans, state syncs, milestones and checkpoints",
+		Value:   &c.cliConfig.Developer.Heimdall,
+		Default: c.cliConfig.Developer.Heimdall,
+	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "dev.validators",
+		Usage:   "Comma separated validators of the simulated Heimdall spans, as address:power (default: the developer account)",
+		Value:   &c.cliConfig.Developer.Validators,
+		Default: c.cliConfig.Developer.Validators,
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "dev.statesyncs",
+		Usage:   "JSON file of the state sync events emitted by the simulated Heimdall",
+		Value:   &c.cliConfig.Developer.StateSyncs,
+		Default: c.cliConfig.Developer.StateSyncs,
+	})
 
 	// pprof
 	f.BoolFlag(&flagset.BoolFlag{
@@ -31,6 +31,7 @@ type BorClient interface {
 	ChainWatch(ctx context.Context, in *ChainWatchRequest, opts ...grpc.CallOption) (Bor_ChainWatchClient, error)
 	DebugPprof(ctx context.Context, in *DebugPprofRequest, opts ...grpc.CallOption) (Bor_DebugPprofClient, error)
 	DebugBlock(ctx context.Context, in *DebugBlockRequest, opts ...grpc.CallOption) (Bor_DebugBlockClient, error)
+	DebugParallel(ctx context.Context, in *DebugBlockRequest, opts ...grpc.CallOption) (Bor_DebugParallelClient, error)
 }
 
 type borClient struct {
@@ -215,6 +216,38 @@ func (x *borDebugBlockClient) Recv() (*DebugFileResponse, error) {
 	return m, nil
 }
 
+func (c *borClient) DebugParallel(ctx context.Context, in *DebugBlockRequest, opts ...grpc.CallOption) (Bor_DebugParallelClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Bor_ServiceDesc.Streams[3], "/proto.Bor/DebugParallel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &borDebugParallelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Bor_DebugParallelClient interface {
+	Recv() (*DebugFileResponse, error)
+	grpc.ClientStream
+}
+
+type borDebugParallelClient struct {
+	grpc.ClientStream
+}
+
+func (x *borDebugParallelClient) Recv() (*DebugFileResponse, error) {
+	m := new(DebugFileResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // BorServer is the server API for Bor service.
 // All implementations must embed UnimplementedBorServer
 // for forward compatibility
@@ -228,6 +261,7 @@ type BorServer interface {
 	ChainWatch(*ChainWatchRequest, Bor_ChainWatchServer) error
 	DebugPprof(*DebugPprofRequest, Bor_DebugPprofServer) error
 	DebugBlock(*DebugBlockRequest, Bor_DebugBlockServer) error
+	DebugParallel(*DebugBlockRequest, Bor_DebugParallelServer) error
 	mustEmbedUnimplementedBorServer()
 }
 
@@ -262,6 +296,9 @@ func (UnimplementedBorServer) DebugPprof(*DebugPprofRequest, Bor_DebugPprofServe
 func (UnimplementedBorServer) DebugBlock(*DebugBlockRequest, Bor_DebugBlockServer) error {
 	return status.Errorf(codes.Unimplemented, "method DebugBlock not implemented")
 }
+func (UnimplementedBorServer) DebugParallel(*DebugBlockRequest, Bor_DebugParallelServer) error {
+	return status.Errorf(codes.Unimplemented, "method DebugParallel not implemented")
+}
 func (UnimplementedBorServer) mustEmbedUnimplementedBorServer() {}
 
 // UnsafeBorServer may be embedded to opt out of forward compatibility for this service.
@@ -467,6 +504,27 @@ func (x *borDebugBlockServer) Send(m *DebugFileResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Bor_DebugParallel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DebugBlockRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BorServer).DebugParallel(m, &borDebugParallelServer{stream})
+}
+
+type Bor_DebugParallelServer interface {
+	Send(*DebugFileResponse) error
+	grpc.ServerStream
+}
+
+type borDebugParallelServer struct {
+	grpc.ServerStream
+}
+
+func (x *borDebugParallelServer) Send(m *DebugFileResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Bor_ServiceDesc is the grpc.ServiceDesc for Bor service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -515,6 +573,11 @@ var Bor_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Bor_DebugBlock_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "DebugParallel",
+			Handler:       _Bor_DebugParallel_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "internal/cli/server/proto/server.proto",
 }
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"slices"
+	"strings"
+)
+
+// relayNamespaces are the RPC namespaces served by the block relay.
+var relayNamespaces = []string{"relay", "builder"}
+
+// RelayCommand is the command to start the server as a block relay
+type RelayCommand struct {
+	Command
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *RelayCommand) MarkDown() string {
+	items := []string{
+		"# Relay",
+		"The ```bor relay``` command runs the Bor client as a block relay. Builders submit their blocks to the ```relay``` namespace, " +
+			"where each block is re-executed on top of the local chain before its bid is accepted. " +
+			"In-turn proposers query the best bid of their slot over the ```builder``` namespace. " +
+			"It accepts the same options as ```bor server```, with the relay enabled and its namespaces added to the HTTP API.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *RelayCommand) Help() string {
+	return `Usage: bor relay [options]
+
+	Run the Bor server as a block relay validating builder submissions.
+  ` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *RelayCommand) Synopsis() string {
+	return "Run the Bor server as a block relay"
+}
+
+// Run implements the cli.Command interface
+func (c *RelayCommand) Run(args []string) int {
+	if err := c.extractFlags(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.config.Relay.Enabled = true
+
+	for _, namespace := range relayNamespaces {
+		if !slices.Contains(c.config.JsonRPC.Http.API, namespace) {
+			c.config.JsonRPC.Http.API = append(c.config.JsonRPC.Http.API, namespace)
+		}
+	}
+
+	return c.run()
+}
@@ -24,11 +24,15 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/builder"
+	"github.com/ethereum/go-ethereum/builder/relay"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/consensus/beacon" //nolint:typecheck
 	"github.com/ethereum/go-ethereum/consensus/bor"    //nolint:typecheck
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/blockvalidation"
+	"github.com/ethereum/go-ethereum/eth/catalyst"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethstats"
@@ -257,6 +261,9 @@ func NewServer(config *Config, opts ...serverOption) (*Server, error) {
 	stack.RegisterAPIs(tracers.APIs(srv.backend.APIBackend))
 	srv.tracerAPI = tracers.NewAPI(srv.backend.APIBackend)
 
+	// builder submission validation for relays, served on the authenticated RPC
+	stack.RegisterAPIs(blockvalidation.APIs(srv.backend.BlockChain()))
+
 	// graphql is started from another place
 	if config.JsonRPC.Graphql.Enabled {
 		if err := graphql.New(stack, srv.backend.APIBackend, filterSystem, config.JsonRPC.Graphql.Cors, config.JsonRPC.Graphql.VHost); err != nil {
@@ -271,6 +278,30 @@ func NewServer(config *Config, opts ...serverOption) (*Server, error) {
 		}
 	}
 
+	// block builder mode
+	if config.Builder.Enabled {
+		builderCfg, builderKey, err := config.buildBuilder(stack)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := builder.New(stack, srv.backend, builderKey, *builderCfg); err != nil {
+			return nil, fmt.Errorf("failed to register the block builder: %v", err)
+		}
+	}
+
+	// block relay
+	if config.Relay.Enabled {
+		relay.Register(stack, srv.backend.BlockChain())
+	}
+
+	// engine API for external block producers
+	if config.Sealer.EngineAPI {
+		if err := catalyst.RegisterBor(stack, srv.backend); err != nil {
+			return nil, err
+		}
+	}
+
 	// sealing (if enabled) or in dev mode
 	if config.Sealer.Enabled || config.Developer.Enabled {
 		if err := srv.backend.StartMining(1); err != nil {
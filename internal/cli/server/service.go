@@ -12,8 +12,11 @@ import (
 
 	grpc_net_conn "github.com/JekaMas/go-grpc-net-conn"
 
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/failover"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/internal/cli/server/pprof"
@@ -218,9 +221,48 @@ func (s *Server) Status(ctx context.Context, in *proto.StatusRequest) (*proto.St
 		Forks: gatherForks(s.config.chain.Genesis.Config, s.config.chain.Genesis.Config.Bor),
 	}
 
+	resp.HeimdallEndpoint, resp.HeimdallEndpoints = s.heimdallStatus()
+
 	return resp, nil
 }
 
+// heimdallStatus returns the Heimdall endpoint in use, and the state of all the
+// endpoints if the client fails over between several.
+func (s *Server) heimdallStatus() (string, []*proto.StatusResponse_HeimdallEndpoint) {
+	engine, ok := s.backend.Engine().(*bor.Bor)
+	if !ok || engine.HeimdallClient == nil {
+		return "", nil
+	}
+
+	client, ok := engine.HeimdallClient.(*failover.Client)
+	if !ok {
+		switch {
+		case s.config.Heimdall.RunHeimdall && s.config.Heimdall.UseHeimdallApp:
+			return "heimdall app", nil
+		case s.config.Heimdall.GRPCAddress != "":
+			return s.config.Heimdall.GRPCAddress, nil
+		default:
+			return s.config.Heimdall.URL, nil
+		}
+	}
+
+	statuses := client.Endpoints()
+	endpoints := make([]*proto.StatusResponse_HeimdallEndpoint, 0, len(statuses))
+
+	for _, status := range statuses {
+		endpoints = append(endpoints, &proto.StatusResponse_HeimdallEndpoint{
+			Url:      status.URL,
+			Current:  status.Current,
+			Healthy:  status.Healthy,
+			Score:    status.Score,
+			Latency:  status.Latency.Milliseconds(),
+			Failures: int64(status.Failures),
+		})
+	}
+
+	return client.Current(), endpoints
+}
+
 func headerToProtoHeader(h *types.Header) *proto.Header {
 	return &proto.Header{
 		Hash:   h.Hash().String(),
@@ -256,6 +298,35 @@ func (s *Server) DebugBlock(req *proto.DebugBlockRequest, stream proto.Bor_Debug
 	return nil
 }
 
+func (s *Server) DebugParallel(req *proto.DebugBlockRequest, stream proto.Bor_DebugParallelServer) error {
+	var header *types.Header
+	if req.Number == -1 {
+		header = s.backend.BlockChain().CurrentBlock()
+	} else {
+		header = s.backend.BlockChain().GetHeaderByNumber(uint64(req.Number))
+	}
+
+	if header == nil {
+		return fmt.Errorf("block %d not found", req.Number)
+	}
+
+	profile, err := eth.NewDebugAPI(s.backend).ParallelExecutionProfile(stream.Context(), header.Hash())
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+
+	if err := sendStreamDebugFile(stream, map[string]string{}, data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 var bigIntT = reflect.TypeOf(new(big.Int)).Kind()
 
 // gatherForks gathers all the fork numbers via reflection
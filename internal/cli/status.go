@@ -111,5 +111,22 @@ func printStatus(status *proto.StatusResponse) string {
 		formatList(forks),
 	}
 
+	if status.HeimdallEndpoint != "" {
+		full = append(full, "\nHeimdall", formatKV([]string{
+			fmt.Sprintf("Current endpoint|%s", status.HeimdallEndpoint),
+		}))
+	}
+
+	if len(status.HeimdallEndpoints) > 0 {
+		endpoints := make([]string, len(status.HeimdallEndpoints)+1)
+		endpoints[0] = "URL|Current|Healthy|Score|Latency (ms)|Failures"
+
+		for i, e := range status.HeimdallEndpoints {
+			endpoints[i+1] = fmt.Sprintf("%s|%v|%v|%.1f|%d|%d", e.Url, e.Current, e.Healthy, e.Score, e.Latency, e.Failures)
+		}
+
+		full = append(full, "\nHeimdall endpoints", formatList(endpoints))
+	}
+
 	return strings.Join(full, "\n")
 }
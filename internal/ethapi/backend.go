@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -85,6 +86,13 @@ type Backend interface {
 	TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 
+	// Bundle pool API
+	SendBundle(ctx context.Context, bundle *bundlepool.Bundle) error
+
+	// Private transaction API
+	SendPrivateTx(ctx context.Context, signedTx *types.Transaction, maxBlock uint64) error
+	CancelPrivateTx(hash common.Hash, sender common.Address) bool
+
 	ChainConfig() *params.ChainConfig
 	Engine() consensus.Engine
 
@@ -142,6 +150,15 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 		}, {
 			Namespace: "bor",
 			Service:   NewBorAPI(apiBackend),
+		}, {
+			Namespace: "eth",
+			Service:   NewBundleAPI(apiBackend),
+		}, {
+			Namespace: "mev",
+			Service:   NewBundleAPI(apiBackend),
+		}, {
+			Namespace: "eth",
+			Service:   NewPrivateTxAPI(apiBackend),
 		},
 	}
 }
@@ -0,0 +1,331 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BundleAPI lets searchers submit transaction bundles, which are included in
+// their target block atomically or not at all.
+type BundleAPI struct {
+	b Backend
+}
+
+// NewBundleAPI creates a new bundle API.
+func NewBundleAPI(b Backend) *BundleAPI {
+	return &BundleAPI{b}
+}
+
+// SendBundleArgs represents the arguments of a bundle submission.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	MinTimestamp      *uint64         `json:"minTimestamp"`
+	MaxTimestamp      *uint64         `json:"maxTimestamp"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+}
+
+// SendBundleResult is the response to a bundle submission.
+type SendBundleResult struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+// toBundle decodes the bundle transactions.
+func (args *SendBundleArgs) toBundle() (*bundlepool.Bundle, error) {
+	if args.BlockNumber == 0 {
+		return nil, errors.New("bundle block number missing")
+	}
+
+	bundle := &bundlepool.Bundle{
+		Txs:               make(types.Transactions, 0, len(args.Txs)),
+		BlockNumber:       uint64(args.BlockNumber),
+		RevertingTxHashes: args.RevertingTxHashes,
+	}
+
+	if args.MinTimestamp != nil {
+		bundle.MinTimestamp = *args.MinTimestamp
+	}
+
+	if args.MaxTimestamp != nil {
+		bundle.MaxTimestamp = *args.MaxTimestamp
+	}
+
+	for i, input := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, fmt.Errorf("invalid transaction %d: %v", i, err)
+		}
+
+		bundle.Txs = append(bundle.Txs, tx)
+	}
+
+	return bundle, nil
+}
+
+// SendBundle adds a bundle of signed transactions to the bundle pool. The
+// transactions are included in order at the given block if they all succeed,
+// except those listed as allowed to revert.
+func (api *BundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (*SendBundleResult, error) {
+	bundle, err := args.toBundle()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := api.b.SendBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+
+	return &SendBundleResult{BundleHash: bundle.Hash()}, nil
+}
+
+// CallBundleArgs represents the arguments of a bundle simulation. The block
+// fields override those of the simulated block, which otherwise follows the
+// state block.
+type CallBundleArgs struct {
+	Txs                    []hexutil.Bytes       `json:"txs"`
+	StateBlockNumberOrHash rpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	BlockNumber            *hexutil.Uint64       `json:"blockNumber"`
+	Timestamp              *hexutil.Uint64       `json:"timestamp"`
+	Coinbase               *common.Address       `json:"coinbase"`
+	GasLimit               *hexutil.Uint64       `json:"gasLimit"`
+	BaseFee                *hexutil.Big          `json:"baseFee"`
+}
+
+// CallBundleTxResult is the outcome of a single transaction of a simulated
+// bundle.
+type CallBundleTxResult struct {
+	TxHash       common.Hash     `json:"txHash"`
+	From         common.Address  `json:"fromAddress"`
+	To           *common.Address `json:"toAddress"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	GasPrice     *hexutil.Big    `json:"gasPrice"`
+	GasFees      *hexutil.Big    `json:"gasFees"`
+	CoinbaseDiff *hexutil.Big    `json:"coinbaseDiff"`
+	Logs         []*types.Log    `json:"logs"`
+	ReturnData   hexutil.Bytes   `json:"value,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revert,omitempty"`
+}
+
+// CallBundleResult is the outcome of a simulated bundle.
+type CallBundleResult struct {
+	BundleHash       common.Hash          `json:"bundleHash"`
+	BundleGasPrice   *hexutil.Big         `json:"bundleGasPrice"`
+	CoinbaseDiff     *hexutil.Big         `json:"coinbaseDiff"`
+	GasFees          *hexutil.Big         `json:"gasFees"`
+	TotalGasUsed     hexutil.Uint64       `json:"totalGasUsed"`
+	StateBlockNumber hexutil.Uint64       `json:"stateBlockNumber"`
+	Results          []CallBundleTxResult `json:"results"`
+}
+
+// CallBundle simulates a bundle of signed transactions on top of the given
+// state block, optionally modified by the state overrides. Transactions which
+// revert are reported, whereas invalid ones abort the whole simulation.
+func (api *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs, overrides *StateOverride) (*CallBundleResult, error) {
+	if len(args.Txs) == 0 {
+		return nil, bundlepool.ErrEmptyBundle
+	}
+
+	bundle, err := (&SendBundleArgs{Txs: args.Txs, BlockNumber: 1}).toBundle()
+	if err != nil {
+		return nil, err
+	}
+
+	stateBlockNrOrHash := args.StateBlockNumberOrHash
+	if stateBlockNrOrHash.BlockNumber == nil && stateBlockNrOrHash.BlockHash == nil {
+		stateBlockNrOrHash = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	}
+
+	state, parent, err := api.b.StateAndHeaderByNumberOrHash(ctx, stateBlockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+
+	header := api.simulatedHeader(parent, &args)
+
+	timeout := api.b.RPCEVMTimeout()
+
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var (
+		signer  = types.MakeSigner(api.b.ChainConfig(), header.Number)
+		gp      = new(core.GasPool).AddGas(header.GasLimit)
+		gasUsed uint64
+
+		coinbaseBalance = state.GetBalance(header.Coinbase)
+		gasFees         = new(big.Int)
+		results         = make([]CallBundleTxResult, 0, len(bundle.Txs))
+	)
+
+	for i, tx := range bundle.Txs {
+		msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		}
+
+		state.SetTxContext(tx.Hash(), i)
+
+		evm, vmError, err := api.b.GetEVM(ctx, msg, state, header, &vm.Config{})
+		if err != nil {
+			return nil, err
+		}
+
+		evm.Context.Coinbase = header.Coinbase
+
+		go func() {
+			<-ctx.Done()
+			evm.Cancel()
+		}()
+
+		txCoinbaseBalance := state.GetBalance(header.Coinbase)
+
+		// nolint : contextcheck
+		result, err := core.ApplyMessage(evm, msg, gp, context.Background())
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		}
+
+		if api.b.ChainConfig().IsByzantium(header.Number) {
+			state.Finalise(true)
+		} else {
+			state.IntermediateRoot(api.b.ChainConfig().IsEIP158(header.Number))
+		}
+
+		tip, err := tx.EffectiveGasTip(header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("err: %w; txhash %s", err, tx.Hash())
+		}
+
+		var (
+			txGasFees      = new(big.Int).Mul(tip, new(big.Int).SetUint64(result.UsedGas))
+			txCoinbaseDiff = new(big.Int).Sub(state.GetBalance(header.Coinbase), txCoinbaseBalance)
+		)
+
+		txResult := CallBundleTxResult{
+			TxHash:       tx.Hash(),
+			From:         msg.From,
+			To:           tx.To(),
+			GasUsed:      hexutil.Uint64(result.UsedGas),
+			GasPrice:     (*hexutil.Big)(new(big.Int).Div(txCoinbaseDiff, new(big.Int).SetUint64(result.UsedGas))),
+			GasFees:      (*hexutil.Big)(txGasFees),
+			CoinbaseDiff: (*hexutil.Big)(txCoinbaseDiff),
+			Logs:         state.GetLogs(tx.Hash(), header.Number.Uint64(), common.Hash{}),
+		}
+
+		if result.Err != nil {
+			txResult.Error = result.Err.Error()
+
+			if len(result.Revert()) > 0 {
+				txResult.RevertReason = hexutil.Encode(result.Revert())
+
+				if reason, err := abi.UnpackRevert(result.Revert()); err == nil {
+					txResult.RevertReason = reason
+				}
+			}
+		} else {
+			txResult.ReturnData = result.Return()
+		}
+
+		if txResult.Logs == nil {
+			txResult.Logs = []*types.Log{}
+		}
+
+		gasUsed += result.UsedGas
+		gasFees.Add(gasFees, txGasFees)
+		results = append(results, txResult)
+	}
+
+	coinbaseDiff := new(big.Int).Sub(state.GetBalance(header.Coinbase), coinbaseBalance)
+
+	return &CallBundleResult{
+		BundleHash:       bundle.Hash(),
+		BundleGasPrice:   (*hexutil.Big)(new(big.Int).Div(coinbaseDiff, new(big.Int).SetUint64(gasUsed))),
+		CoinbaseDiff:     (*hexutil.Big)(coinbaseDiff),
+		GasFees:          (*hexutil.Big)(gasFees),
+		TotalGasUsed:     hexutil.Uint64(gasUsed),
+		StateBlockNumber: hexutil.Uint64(parent.Number.Uint64()),
+		Results:          results,
+	}, nil
+}
+
+// simulatedHeader assembles the header of the block a bundle is simulated in,
+// on top of parent and with the block overrides of args applied. The gas limit
+// override is capped by the RPC gas cap.
+func (api *BundleAPI) simulatedHeader(parent *types.Header, args *CallBundleArgs) *types.Header {
+	config := api.b.ChainConfig()
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		Difficulty: parent.Difficulty,
+		Coinbase:   parent.Coinbase,
+		MixDigest:  parent.MixDigest,
+	}
+
+	if args.BlockNumber != nil {
+		header.Number = new(big.Int).SetUint64(uint64(*args.BlockNumber))
+	}
+
+	if config.Bor != nil {
+		header.Time = parent.Time + config.Bor.CalculatePeriod(header.Number.Uint64())
+	}
+
+	if args.Timestamp != nil {
+		header.Time = uint64(*args.Timestamp)
+	}
+
+	if args.Coinbase != nil {
+		header.Coinbase = *args.Coinbase
+	}
+
+	if args.GasLimit != nil {
+		header.GasLimit = uint64(*args.GasLimit)
+
+		if gasCap := api.b.RPCGasCap(); gasCap != 0 && gasCap < header.GasLimit {
+			log.Warn("Caller gas limit above allowance, capping", "requested", header.GasLimit, "cap", gasCap)
+			header.GasLimit = gasCap
+		}
+	}
+
+	switch {
+	case args.BaseFee != nil:
+		header.BaseFee = args.BaseFee.ToInt()
+	case config.IsLondon(header.Number):
+		header.BaseFee = misc.CalcBaseFee(config, parent)
+	}
+
+	return header
+}
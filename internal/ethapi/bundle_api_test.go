@@ -0,0 +1,175 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// bundleBackendMock serves an empty state on top of the mocked head of a bor
+// chain.
+type bundleBackendMock struct {
+	*backendMock
+
+	gasCap uint64
+}
+
+func newBundleBackendMock() *bundleBackendMock {
+	backend := newBackendMock()
+	backend.config = params.BorUnittestChainConfig
+
+	return &bundleBackendMock{backendMock: backend}
+}
+
+func (b *bundleBackendMock) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	return statedb, b.current, err
+}
+
+func (b *bundleBackendMock) RPCGasCap() uint64 { return b.gasCap }
+
+func (b *bundleBackendMock) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	blockCtx := core.NewEVMBlockContext(header, nil, &header.Coinbase)
+	return vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), state, b.config, *vmConfig), state.Error, nil
+}
+
+func TestCallBundle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = newBundleBackendMock()
+		api     = NewBundleAPI(backend)
+
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase = common.HexToAddress("0xc014ba5e")
+		logger   = common.HexToAddress("0x1000") // LOG0 with empty data
+		reverter = common.HexToAddress("0x2000") // REVERT with empty data
+
+		signer  = types.LatestSigner(backend.config)
+		baseFee = big.NewInt(params.GWei)
+		tip     = big.NewInt(2 * params.GWei)
+	)
+
+	newTx := func(nonce uint64, to common.Address) hexutil.Bytes {
+		tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   backend.config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: tip,
+			GasFeeCap: new(big.Int).Add(tip, baseFee),
+			Gas:       100_000,
+			To:        &to,
+		})
+		enc, _ := tx.MarshalBinary()
+
+		return enc
+	}
+
+	balance := (*hexutil.Big)(big.NewInt(params.Ether))
+	overrides := &StateOverride{
+		sender:   {Balance: &balance},
+		logger:   {Code: (*hexutil.Bytes)(&[]byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG0), byte(vm.STOP)})},
+		reverter: {Code: (*hexutil.Bytes)(&[]byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT)})},
+	}
+
+	result, err := api.CallBundle(context.Background(), CallBundleArgs{
+		Txs:      []hexutil.Bytes{newTx(0, logger), newTx(1, reverter)},
+		Coinbase: &coinbase,
+		BaseFee:  (*hexutil.Big)(baseFee),
+	}, overrides)
+	if err != nil {
+		t.Fatalf("failed to call bundle: %v", err)
+	}
+
+	if len(result.Results) != 2 {
+		t.Fatalf("result count mismatch: have %d, want 2", len(result.Results))
+	}
+
+	// Bor also emits a fee transfer log for every transaction
+	var emitted int
+
+	for _, log := range result.Results[0].Logs {
+		if log.Address == logger {
+			emitted++
+		}
+	}
+
+	if emitted != 1 {
+		t.Errorf("contract log count mismatch: have %d, want 1", emitted)
+	}
+
+	if res := result.Results[0]; res.Error != "" {
+		t.Errorf("unexpected error for first transaction: %v", res.Error)
+	}
+
+	if res := result.Results[1]; res.Error != vm.ErrExecutionReverted.Error() {
+		t.Errorf("revert error mismatch: have %q, want %q", res.Error, vm.ErrExecutionReverted)
+	}
+
+	var (
+		gasUsed = uint64(result.Results[0].GasUsed + result.Results[1].GasUsed)
+		fees    = new(big.Int).Mul(tip, new(big.Int).SetUint64(gasUsed))
+	)
+
+	if uint64(result.TotalGasUsed) != gasUsed {
+		t.Errorf("total gas mismatch: have %d, want %d", result.TotalGasUsed, gasUsed)
+	}
+
+	if result.CoinbaseDiff.ToInt().Cmp(fees) != 0 || result.GasFees.ToInt().Cmp(fees) != 0 {
+		t.Errorf("coinbase payment mismatch: have diff %v, fees %v, want %v", result.CoinbaseDiff, result.GasFees, fees)
+	}
+
+	if result.BundleGasPrice.ToInt().Cmp(tip) != 0 {
+		t.Errorf("bundle gas price mismatch: have %v, want %v", result.BundleGasPrice, tip)
+	}
+
+	if uint64(result.StateBlockNumber) != backend.current.Number.Uint64() {
+		t.Errorf("state block mismatch: have %d, want %d", result.StateBlockNumber, backend.current.Number)
+	}
+
+	// Transactions which can't be executed invalidate the whole bundle
+	if _, err := api.CallBundle(context.Background(), CallBundleArgs{
+		Txs:      []hexutil.Bytes{newTx(0, logger), newTx(2, logger)},
+		Coinbase: &coinbase,
+		BaseFee:  (*hexutil.Big)(baseFee),
+	}, overrides); err == nil {
+		t.Fatal("bundle with nonce gap didn't fail")
+	}
+}
+
+func TestCallBundleGasLimitCap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		gasCap   uint64
+		gasLimit uint64
+		want     uint64
+	}{
+		{0, 100_000_000, 100_000_000},
+		{50_000_000, 100_000_000, 50_000_000},
+		{50_000_000, 20_000_000, 20_000_000},
+	}
+
+	for i, tt := range tests {
+		backend := newBundleBackendMock()
+		backend.gasCap = tt.gasCap
+
+		gasLimit := hexutil.Uint64(tt.gasLimit)
+
+		header := NewBundleAPI(backend).simulatedHeader(backend.current, &CallBundleArgs{GasLimit: &gasLimit})
+		if header.GasLimit != tt.want {
+			t.Errorf("test %d: gas limit mismatch: have %d, want %d", i, header.GasLimit, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PrivateTxAPI lets users submit transactions which are never gossiped to
+// peers, and may only be included by the local miner.
+type PrivateTxAPI struct {
+	b Backend
+}
+
+// NewPrivateTxAPI creates a new private transaction API.
+func NewPrivateTxAPI(b Backend) *PrivateTxAPI {
+	return &PrivateTxAPI{b}
+}
+
+// SendPrivateTransactionArgs represents the arguments of a private transaction
+// submission.
+type SendPrivateTransactionArgs struct {
+	Tx             hexutil.Bytes   `json:"tx"`
+	MaxBlockNumber *hexutil.Uint64 `json:"maxBlockNumber"`
+}
+
+// CancelPrivateTransactionArgs represents the arguments of a private
+// transaction cancellation. The signature is the personal_sign of the
+// transaction hash by the sender of the transaction.
+type CancelPrivateTransactionArgs struct {
+	TxHash    common.Hash   `json:"txHash"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// SendPrivateTransaction adds a signed transaction to the private pool, from
+// which it's included by the local miner up to the max block number. If none is
+// given the transaction is kept for as long as the pool allows.
+func (api *PrivateTxAPI) SendPrivateTransaction(ctx context.Context, args SendPrivateTransactionArgs) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(args.Tx); err != nil {
+		return common.Hash{}, err
+	}
+
+	var maxBlock uint64
+	if args.MaxBlockNumber != nil {
+		maxBlock = uint64(*args.MaxBlockNumber)
+	}
+
+	if err := api.b.SendPrivateTx(ctx, tx, maxBlock); err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
+// CancelPrivateTransaction drops a transaction from the private pool, and
+// reports whether it was still pending there. Only the sender of the
+// transaction may cancel it.
+func (api *PrivateTxAPI) CancelPrivateTransaction(ctx context.Context, args CancelPrivateTransactionArgs) (bool, error) {
+	sender, err := cancelSender(args.TxHash, args.Signature)
+	if err != nil {
+		return false, err
+	}
+
+	return api.b.CancelPrivateTx(args.TxHash, sender), nil
+}
+
+// cancelSender recovers the account which signed the cancellation of the
+// transaction with the given hash.
+func cancelSender(hash common.Hash, signature hexutil.Bytes) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+	}
+
+	if signature[crypto.RecoveryIDOffset] != 27 && signature[crypto.RecoveryIDOffset] != 28 {
+		return common.Address{}, fmt.Errorf("invalid Ethereum signature (V is not 27 or 28)")
+	}
+
+	sig := common.CopyBytes(signature)
+	sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
+
+	pub, err := crypto.SigToPub(accounts.TextHash(hash.Bytes()), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pub), nil
+}
@@ -0,0 +1,72 @@
+package ethapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// privateBackendMock records the cancellations of private transactions.
+type privateBackendMock struct {
+	*backendMock
+
+	hash   common.Hash
+	sender common.Address
+}
+
+func (b *privateBackendMock) CancelPrivateTx(hash common.Hash, sender common.Address) bool {
+	b.hash, b.sender = hash, sender
+	return true
+}
+
+func TestCancelPrivateTransaction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = &privateBackendMock{backendMock: newBackendMock()}
+		api     = NewPrivateTxAPI(backend)
+
+		key, _ = crypto.GenerateKey()
+		hash   = common.HexToHash("0xc0ffee")
+	)
+
+	sig, err := crypto.Sign(accounts.TextHash(hash.Bytes()), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig[crypto.RecoveryIDOffset] += 27
+
+	if _, err := api.CancelPrivateTransaction(context.Background(), CancelPrivateTransactionArgs{TxHash: hash}); err == nil {
+		t.Fatalf("cancelled without a signature")
+	}
+
+	invalid := common.CopyBytes(sig)
+	invalid[crypto.RecoveryIDOffset] = 0
+
+	if _, err := api.CancelPrivateTransaction(context.Background(), CancelPrivateTransactionArgs{TxHash: hash, Signature: invalid}); err == nil {
+		t.Fatalf("cancelled with an invalid signature")
+	}
+
+	ok, err := api.CancelPrivateTransaction(context.Background(), CancelPrivateTransactionArgs{TxHash: hash, Signature: sig})
+	if err != nil || !ok {
+		t.Fatalf("cancel failed: %v", err)
+	}
+
+	if sender := crypto.PubkeyToAddress(key.PublicKey); backend.hash != hash || backend.sender != sender {
+		t.Fatalf("cancel mismatch: have %v from %v, want %v from %v", backend.hash, backend.sender, hash, sender)
+	}
+
+	// The signature of another hash recovers another sender
+	other := common.HexToHash("0xdecaf")
+	if _, err := api.CancelPrivateTransaction(context.Background(), CancelPrivateTransactionArgs{TxHash: other, Signature: sig}); err != nil {
+		t.Fatal(err)
+	}
+
+	if backend.sender == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("signature of another transaction recovered its sender")
+	}
+}
@@ -71,6 +71,16 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'getConditionalTransactionStatus',
+			call: 'bor_getConditionalTransactionStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getFinality',
+			call: 'bor_getFinality',
+			params: 0
+		}),
 	]
 });
 `
@@ -559,6 +559,11 @@ web3._extend({
 			call: 'debug_setTrieFlushInterval',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'parallelExecutionProfile',
+			call: 'debug_parallelExecutionProfile',
+			params: 1
+		}),
 	],
 	properties: []
 });
@@ -745,6 +750,17 @@ web3._extend({
 			call: 'miner_setRecommitInterval',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'getBuilderAuctions',
+			call: 'miner_getBuilderAuctions',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal],
+		}),
+		new web3._extend.Method({
+			name: 'getBlockProfit',
+			call: 'miner_getBlockProfit',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
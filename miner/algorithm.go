@@ -0,0 +1,451 @@
+package miner
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ethereum/go-ethereum/common"
+	cmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/common/tracing"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Block building algorithms the miner can be configured with.
+const (
+	AlgorithmGreedyTip    = "greedy"  // Locals first, then remotes, highest effective tip first
+	AlgorithmGreedyProfit = "profit"  // Highest simulated coinbase payment per gas first
+	AlgorithmBundleAware  = "bundles" // Single price ordered lane leaving the bundled transactions to their bundles
+	AlgorithmMulti        = "multi"   // All of the above concurrently, keeping the most valuable block
+)
+
+// BlockBuildingAlgorithm decides which of the pending transactions go into a
+// block and in which order. It runs once the bundles are simulated and the
+// private and conditional transactions are handled.
+type BlockBuildingAlgorithm interface {
+	// Name returns the name the algorithm is configured with.
+	Name() string
+
+	// Fill commits transactions of pending to env until the block is full, the
+	// transactions run out or the building is interrupted. Pending is owned by
+	// the algorithm.
+	Fill(ctx context.Context, w *worker, env *environment, pending *pendingSet, interrupt *atomic.Int32, interruptCtx context.Context) error
+}
+
+// NewBlockBuildingAlgorithm returns the block building algorithm with the given
+// name, the greedy by tip one if the name is empty.
+func NewBlockBuildingAlgorithm(name string) (BlockBuildingAlgorithm, error) {
+	switch name {
+	case "", AlgorithmGreedyTip:
+		return greedyTipAlgorithm{}, nil
+	case AlgorithmGreedyProfit:
+		return greedyProfitAlgorithm{}, nil
+	case AlgorithmBundleAware:
+		return bundleAwareAlgorithm{}, nil
+	case AlgorithmMulti:
+		return multiAlgorithm{algorithms: []BlockBuildingAlgorithm{greedyTipAlgorithm{}, greedyProfitAlgorithm{}, bundleAwareAlgorithm{}}}, nil
+	default:
+		return nil, fmt.Errorf("unknown block building algorithm: %s", name)
+	}
+}
+
+// newWorkerAlgorithm returns the block building algorithm the worker is
+// configured with, falling back to the greedy by tip one.
+func newWorkerAlgorithm(name string) BlockBuildingAlgorithm {
+	algorithm, err := NewBlockBuildingAlgorithm(name)
+	if err != nil {
+		log.Warn("Falling back to the greedy block building algorithm", "err", err)
+		return greedyTipAlgorithm{}
+	}
+
+	return algorithm
+}
+
+// pendingSet holds the executable pool transactions, split by origin and sorted
+// by nonce for each sender.
+type pendingSet struct {
+	locals  map[common.Address]types.Transactions
+	remotes map[common.Address]types.Transactions
+}
+
+// copy returns a copy of the pending transactions which can be consumed
+// independently.
+func (p *pendingSet) copy() *pendingSet {
+	cpy := &pendingSet{
+		locals:  make(map[common.Address]types.Transactions, len(p.locals)),
+		remotes: make(map[common.Address]types.Transactions, len(p.remotes)),
+	}
+
+	for sender, txs := range p.locals {
+		cpy.locals[sender] = txs
+	}
+
+	for sender, txs := range p.remotes {
+		cpy.remotes[sender] = txs
+	}
+
+	return cpy
+}
+
+// all returns the local and remote transactions in a single set.
+func (p *pendingSet) all() map[common.Address]types.Transactions {
+	all := make(map[common.Address]types.Transactions, len(p.locals)+len(p.remotes))
+
+	for sender, txs := range p.remotes {
+		all[sender] = txs
+	}
+
+	for sender, txs := range p.locals {
+		all[sender] = txs
+	}
+
+	return all
+}
+
+// orderedTxs is a set of transactions commitTransactions takes the next one to
+// execute from.
+type orderedTxs interface {
+	// Peek returns the next transaction, nil if there is none left.
+	Peek() *types.Transaction
+
+	// Shift replaces the next transaction with the following one of its sender.
+	Shift()
+
+	// Pop drops the next transaction along with the rest of its sender's.
+	Pop()
+
+	// GetTxs returns the number of senders with transactions left.
+	GetTxs() int
+}
+
+// greedyTipAlgorithm commits the local transactions first and then the remote
+// ones, each by descending effective tip, merging in the bundles which pay more
+// per gas than the next transaction.
+type greedyTipAlgorithm struct{}
+
+func (greedyTipAlgorithm) Name() string { return AlgorithmGreedyTip }
+
+func (greedyTipAlgorithm) Fill(ctx context.Context, w *worker, env *environment, pending *pendingSet, interrupt *atomic.Int32, interruptCtx context.Context) error {
+	lanes := []struct {
+		name string
+		txs  map[common.Address]types.Transactions
+	}{
+		{"worker.LocalCommitTransactions", pending.locals},
+		{"worker.RemoteCommitTransactions", pending.remotes},
+	}
+
+	for _, lane := range lanes {
+		if len(lane.txs) == 0 {
+			continue
+		}
+
+		var err error
+
+		tracing.Exec(ctx, "", lane.name, func(ctx context.Context, span trace.Span) {
+			txs := types.NewTransactionsByPriceAndNonce(env.signer, lane.txs, envBaseFee(env))
+
+			tracing.SetAttributes(
+				span,
+				attribute.Int("len of tx heads", txs.GetTxs()),
+			)
+
+			err = w.commitTransactions(env, txs, interrupt, interruptCtx)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// Append the bundles paying less than any pending transaction
+	w.commitBundles(env, nil)
+
+	return nil
+}
+
+// greedyProfitAlgorithm simulates the transactions of every sender on top of
+// the block, and commits them by descending coinbase payment per gas. Unlike
+// the effective tip, the payment includes the direct transfers to the coinbase.
+// Senders are simulated on their own copy of the state, which makes this the
+// most expensive of the algorithms on large pools.
+type greedyProfitAlgorithm struct{}
+
+func (greedyProfitAlgorithm) Name() string { return AlgorithmGreedyProfit }
+
+func (greedyProfitAlgorithm) Fill(ctx context.Context, w *worker, env *environment, pending *pendingSet, interrupt *atomic.Int32, interruptCtx context.Context) error {
+	txs, err := w.simulateProfits(env, pending.all(), interrupt)
+	if err != nil {
+		return err
+	}
+
+	if err := w.commitTransactions(env, txs, interrupt, interruptCtx); err != nil {
+		return err
+	}
+
+	w.commitBundles(env, nil)
+
+	return nil
+}
+
+// bundleAwareAlgorithm merges the local and remote transactions into a single
+// lane by descending effective tip, interleaved with the bundles. Transactions
+// contained in a bundle, and the later ones of their senders, are left to the
+// bundle instead of being included on their own and invalidating it.
+type bundleAwareAlgorithm struct{}
+
+func (bundleAwareAlgorithm) Name() string { return AlgorithmBundleAware }
+
+func (bundleAwareAlgorithm) Fill(ctx context.Context, w *worker, env *environment, pending *pendingSet, interrupt *atomic.Int32, interruptCtx context.Context) error {
+	bundled := make(map[common.Hash]struct{})
+
+	for _, sim := range env.bundles {
+		for _, tx := range sim.bundle.Txs {
+			bundled[tx.Hash()] = struct{}{}
+		}
+	}
+
+	all := pending.all()
+
+	for sender, txs := range all {
+		for i, tx := range txs {
+			if _, ok := bundled[tx.Hash()]; !ok {
+				continue
+			}
+
+			if i == 0 {
+				delete(all, sender)
+			} else {
+				all[sender] = txs[:i]
+			}
+
+			break
+		}
+	}
+
+	if err := w.commitTransactions(env, types.NewTransactionsByPriceAndNonce(env.signer, all, envBaseFee(env)), interrupt, interruptCtx); err != nil {
+		return err
+	}
+
+	w.commitBundles(env, nil)
+
+	return nil
+}
+
+// multiAlgorithm builds a candidate block with each of its algorithms
+// concurrently, and keeps the one paying the most to the coinbase.
+type multiAlgorithm struct {
+	algorithms []BlockBuildingAlgorithm
+}
+
+func (multiAlgorithm) Name() string { return AlgorithmMulti }
+
+func (a multiAlgorithm) Fill(ctx context.Context, w *worker, env *environment, pending *pendingSet, interrupt *atomic.Int32, interruptCtx context.Context) error {
+	type candidate struct {
+		env   *environment
+		value *big.Int
+		err   error
+	}
+
+	var (
+		balance    = env.state.GetBalance(env.coinbase)
+		candidates = make([]candidate, len(a.algorithms))
+		wg         sync.WaitGroup
+	)
+
+	for i, algorithm := range a.algorithms {
+		candEnv := env.copy()
+		candEnv.bundles = env.bundles
+
+		wg.Add(1)
+
+		go func(i int, algorithm BlockBuildingAlgorithm, candEnv *environment, pending *pendingSet) {
+			defer wg.Done()
+
+			err := algorithm.Fill(ctx, w, candEnv, pending, interrupt, interruptCtx)
+
+			candidates[i] = candidate{
+				env:   candEnv,
+				value: new(big.Int).Sub(candEnv.state.GetBalance(candEnv.coinbase), balance),
+				err:   err,
+			}
+		}(i, algorithm, candEnv, pending.copy())
+	}
+
+	wg.Wait()
+
+	best := 0
+
+	for i := range candidates {
+		if candidates[i].value.Cmp(candidates[best].value) > 0 {
+			best = i
+		}
+	}
+
+	for i := range candidates {
+		if i != best {
+			candidates[i].env.discard()
+		}
+	}
+
+	log.Debug("Picked candidate block", "number", env.header.Number, "algorithm", a.algorithms[best].Name(), "value", candidates[best].value, "txs", len(candidates[best].env.txs))
+
+	env.discard()
+	*env = *candidates[best].env
+
+	return candidates[best].err
+}
+
+// envBaseFee returns the base fee of the block of env, nil before London.
+func envBaseFee(env *environment) *uint256.Int {
+	if env.header.BaseFee == nil {
+		return nil
+	}
+
+	return cmath.FromBig(env.header.BaseFee)
+}
+
+// profitTx is a transaction along with the coinbase payment per gas it made
+// when simulated.
+type profitTx struct {
+	tx     *types.Transaction
+	from   common.Address
+	profit *big.Int
+}
+
+// profitHeads implements the heap interface over the next transaction of each
+// sender, most profitable first.
+type profitHeads []*profitTx
+
+func (h profitHeads) Len() int { return len(h) }
+func (h profitHeads) Less(i, j int) bool {
+	if cmp := h[i].profit.Cmp(h[j].profit); cmp != 0 {
+		return cmp > 0
+	}
+
+	return bytes.Compare(h[i].from[:], h[j].from[:]) < 0
+}
+func (h profitHeads) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *profitHeads) Push(x interface{}) {
+	*h = append(*h, x.(*profitTx))
+}
+
+func (h *profitHeads) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[0 : n-1]
+
+	return x
+}
+
+// txsByProfitAndNonce orders transactions by their simulated profit while
+// keeping the nonce order of each sender.
+type txsByProfitAndNonce struct {
+	txs   map[common.Address][]*profitTx // Per sender nonce sorted transactions following its head
+	heads profitHeads
+}
+
+func (t *txsByProfitAndNonce) Peek() *types.Transaction {
+	if len(t.heads) == 0 {
+		return nil
+	}
+
+	return t.heads[0].tx
+}
+
+func (t *txsByProfitAndNonce) Shift() {
+	from := t.heads[0].from
+
+	if txs := t.txs[from]; len(txs) > 0 {
+		t.heads[0], t.txs[from] = txs[0], txs[1:]
+		heap.Fix(&t.heads, 0)
+
+		return
+	}
+
+	heap.Pop(&t.heads)
+}
+
+func (t *txsByProfitAndNonce) Pop() {
+	heap.Pop(&t.heads)
+}
+
+func (t *txsByProfitAndNonce) GetTxs() int {
+	return len(t.heads)
+}
+
+// simulateProfits executes the transactions of every sender in nonce order on
+// a copy of env, and returns them ordered by the coinbase payment per gas they
+// made. The transactions of a sender following one which failed are dropped.
+func (w *worker) simulateProfits(env *environment, pending map[common.Address]types.Transactions, interrupt *atomic.Int32) (*txsByProfitAndNonce, error) {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+
+	ordered := &txsByProfitAndNonce{
+		txs:   make(map[common.Address][]*profitTx, len(pending)),
+		heads: make(profitHeads, 0, len(pending)),
+	}
+
+	for from, txs := range pending {
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return nil, signalToErr(signal)
+			}
+		}
+
+		var (
+			simEnv    = env.copy()
+			simulated = make([]*profitTx, 0, len(txs))
+		)
+
+		for _, tx := range txs {
+			balance := simEnv.state.GetBalance(simEnv.coinbase)
+
+			simEnv.state.SetTxContext(tx.Hash(), simEnv.tcount)
+
+			if _, err := w.commitTransaction(simEnv, tx, context.Background()); err != nil {
+				log.Trace("Dropping transaction failing simulation", "hash", tx.Hash(), "sender", from, "err", err)
+				break
+			}
+
+			simEnv.tcount++
+
+			var (
+				gasUsed = simEnv.receipts[len(simEnv.receipts)-1].GasUsed
+				payment = new(big.Int).Sub(simEnv.state.GetBalance(simEnv.coinbase), balance)
+			)
+
+			simulated = append(simulated, &profitTx{
+				tx:     tx,
+				from:   from,
+				profit: payment.Div(payment, new(big.Int).SetUint64(gasUsed)),
+			})
+		}
+
+		simEnv.discard()
+
+		if len(simulated) == 0 {
+			continue
+		}
+
+		ordered.heads = append(ordered.heads, simulated[0])
+		ordered.txs[from] = simulated[1:]
+	}
+
+	heap.Init(&ordered.heads)
+
+	return ordered, nil
+}
@@ -0,0 +1,235 @@
+package miner
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestNewBlockBuildingAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{AlgorithmGreedyTip, AlgorithmGreedyProfit, AlgorithmBundleAware, AlgorithmMulti} {
+		algorithm, err := NewBlockBuildingAlgorithm(name)
+		if err != nil {
+			t.Fatalf("failed to create %s algorithm: %v", name, err)
+		}
+
+		if algorithm.Name() != name {
+			t.Errorf("name mismatch: have %s, want %s", algorithm.Name(), name)
+		}
+	}
+
+	if algorithm, err := NewBlockBuildingAlgorithm(""); err != nil || algorithm.Name() != AlgorithmGreedyTip {
+		t.Errorf("default algorithm mismatch: have %v (err %v), want %s", algorithm, err, AlgorithmGreedyTip)
+	}
+
+	if _, err := NewBlockBuildingAlgorithm("random"); err == nil {
+		t.Errorf("unknown algorithm accepted")
+	}
+}
+
+func TestGreedyProfitAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	w, cfg := newBuilderTestWorker(t)
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: common.HexToAddress("0xc014ba5e")})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	// The payer tips less than the bank, but transfers to the coinbase
+	payerKey, _ := crypto.GenerateKey()
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+
+	env.state.AddBalance(payer, big.NewInt(params.Ether))
+
+	var (
+		tip     = newBuilderTestTx(t, cfg, 0, params.GWei)
+		payment = types.MustSignNewTx(payerKey, types.LatestSigner(cfg), &types.DynamicFeeTx{
+			ChainID:   cfg.ChainID,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(1 + 100*params.InitialBaseFee),
+			Gas:       params.TxGas,
+			To:        &env.coinbase,
+			Value:     big.NewInt(params.GWei * int64(params.TxGas) * 2),
+		})
+		pending = &pendingSet{
+			remotes: map[common.Address]types.Transactions{TestBankAddress: {tip}, payer: {payment}},
+		}
+	)
+
+	tests := []struct {
+		algorithm BlockBuildingAlgorithm
+		want      types.Transactions
+	}{
+		{greedyTipAlgorithm{}, types.Transactions{tip, payment}},
+		{greedyProfitAlgorithm{}, types.Transactions{payment, tip}},
+	}
+
+	for _, tt := range tests {
+		algoEnv := env.copy()
+
+		if err := tt.algorithm.Fill(context.Background(), w, algoEnv, pending.copy(), nil, context.Background()); err != nil {
+			t.Fatalf("%s: failed to fill transactions: %v", tt.algorithm.Name(), err)
+		}
+
+		if len(algoEnv.txs) != len(tt.want) {
+			t.Fatalf("%s: transaction count mismatch: have %d, want %d", tt.algorithm.Name(), len(algoEnv.txs), len(tt.want))
+		}
+
+		for i, tx := range algoEnv.txs {
+			if tx.Hash() != tt.want[i].Hash() {
+				t.Errorf("%s: transaction %d mismatch: have %s, want %s", tt.algorithm.Name(), i, tx.Hash(), tt.want[i].Hash())
+			}
+		}
+
+		algoEnv.discard()
+	}
+}
+
+func TestBundleAwareAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		algorithm string
+		bundled   bool // Whether the bundle sharing its first transaction with the pool makes it in
+	}{
+		{AlgorithmGreedyTip, false},
+		{AlgorithmGreedyProfit, false},
+		{AlgorithmBundleAware, true},
+		{AlgorithmMulti, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.algorithm, func(t *testing.T) {
+			t.Parallel()
+
+			w, cfg := newBuilderTestWorker(t)
+			w.algorithm = newWorkerAlgorithm(tt.algorithm)
+
+			// The pooled transaction outbids the bundle, so a bundle unaware
+			// algorithm includes it alone and invalidates the bundle
+			var (
+				pooled = newBuilderTestTx(t, cfg, 0, params.GWei)
+				bundle = types.Transactions{pooled, newBuilderTestTx(t, cfg, 1, 1)}
+			)
+
+			for _, err := range w.eth.TxPool().AddLocals(types.Transactions{pooled}) {
+				if err != nil {
+					t.Fatalf("failed to add pending transaction: %v", err)
+				}
+			}
+
+			if err := w.eth.BundlePool().Add(&bundlepool.Bundle{Txs: bundle, BlockNumber: 1}); err != nil {
+				t.Fatalf("failed to add bundle: %v", err)
+			}
+
+			env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: common.HexToAddress("0xc014ba5e")})
+			if err != nil {
+				t.Fatalf("failed to prepare work: %v", err)
+			}
+			defer env.discard()
+
+			if err := w.fillTransactions(context.Background(), nil, env, context.Background()); err != nil {
+				t.Fatalf("failed to fill transactions: %v", err)
+			}
+
+			want := types.Transactions{pooled}
+			if tt.bundled {
+				want = bundle
+			}
+
+			if len(env.txs) != len(want) {
+				t.Fatalf("transaction count mismatch: have %d, want %d", len(env.txs), len(want))
+			}
+
+			for i, tx := range env.txs {
+				if tx.Hash() != want[i].Hash() {
+					t.Errorf("transaction %d mismatch: have %s, want %s", i, tx.Hash(), want[i].Hash())
+				}
+			}
+		})
+	}
+}
+
+// TestMultiAlgorithm checks that the block picked by the multi algorithm is
+// the most valuable of the ones built by each of its algorithms on their own.
+// The algorithms share the worker while building concurrently, run it with
+// -race (make test-miner-race) to check that they don't race. Transactions are
+// committed sequentially, as block-stm doesn't pass -race on its own.
+func TestMultiAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newBuilderTestWorker(t)
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: benchCoinbase})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	txs := loadBenchMempool(t, "payments")
+
+	for _, tx := range txs {
+		from, _ := types.Sender(env.signer, tx)
+		env.state.SetBalance(from, big.NewInt(params.Ether))
+	}
+
+	var (
+		pending = &pendingSet{remotes: replayPending(env, txs)}
+		balance = env.state.GetBalance(env.coinbase)
+		multi   = multiAlgorithm{algorithms: []BlockBuildingAlgorithm{greedyTipAlgorithm{}, greedyProfitAlgorithm{}, bundleAwareAlgorithm{}}}
+		best    *environment
+	)
+
+	for _, algorithm := range multi.algorithms {
+		algoEnv := env.copy()
+		defer algoEnv.discard()
+
+		if err := algorithm.Fill(context.Background(), w, algoEnv, pending.copy(), nil, context.Background()); err != nil {
+			t.Fatalf("%s: failed to fill transactions: %v", algorithm.Name(), err)
+		}
+
+		if best == nil || algoEnv.state.GetBalance(algoEnv.coinbase).Cmp(best.state.GetBalance(best.coinbase)) > 0 {
+			best = algoEnv
+		}
+	}
+
+	multiEnv := env.copy()
+	defer multiEnv.discard()
+
+	if err := multi.Fill(context.Background(), w, multiEnv, pending.copy(), nil, context.Background()); err != nil {
+		t.Fatalf("failed to fill transactions: %v", err)
+	}
+
+	var (
+		have = new(big.Int).Sub(multiEnv.state.GetBalance(multiEnv.coinbase), balance)
+		want = new(big.Int).Sub(best.state.GetBalance(best.coinbase), balance)
+	)
+
+	if have.Cmp(want) != 0 {
+		t.Fatalf("block value mismatch: have %v, want %v", have, want)
+	}
+
+	if len(multiEnv.txs) != len(best.txs) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(multiEnv.txs), len(best.txs))
+	}
+
+	for i, tx := range multiEnv.txs {
+		if tx.Hash() != best.txs[i].Hash() {
+			t.Fatalf("transaction %d mismatch: have %s, want %s", i, tx.Hash(), best.txs[i].Hash())
+		}
+	}
+}
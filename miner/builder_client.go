@@ -0,0 +1,501 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/builder/api"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// builderAuctionRetention is the number of blocks the records of the builder
+// auctions are kept for, a couple of days of bor blocks.
+const builderAuctionRetention = 100_000
+
+var (
+	errNoBuilderBid = errors.New("no builder bid")
+
+	builderBidCounter      = metrics.NewRegisteredCounter("worker/builder/bids", nil)
+	builderWinCounter      = metrics.NewRegisteredCounter("worker/builder/wins", nil)
+	builderFallbackCounter = metrics.NewRegisteredCounter("worker/builder/fallbacks", nil)
+)
+
+// builderEndpoint is a lazily dialed connection to a single block builder.
+type builderEndpoint struct {
+	url string
+
+	mu     sync.Mutex
+	client *rpc.Client
+}
+
+// dial returns the rpc client of the endpoint, connecting on first use.
+func (e *builderEndpoint) dial(ctx context.Context) (*rpc.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.client != nil {
+		return e.client, nil
+	}
+
+	client, err := rpc.DialContext(ctx, e.url)
+	if err != nil {
+		return nil, err
+	}
+
+	e.client = client
+
+	return client, nil
+}
+
+// builderBid is a verified bid together with the endpoint which offered it.
+type builderBid struct {
+	endpoint *builderEndpoint
+	bid      *api.SignedBid
+	received time.Time
+}
+
+// builderAuction is a sealed-bid auction run among the builders for a single
+// in-turn block, along with the record kept for auditing it.
+type builderAuction struct {
+	record *types.BuilderAuction
+	best   *builderBid // Most valuable valid bid, nil if none arrived in time
+}
+
+// builderClient collects bids for in-turn blocks from the configured builders
+// and retrieves the body of the winning one.
+type builderClient struct {
+	endpoints []*builderEndpoint
+	timeout   time.Duration
+	cutoff    time.Duration // Time before the block timestamp bids are accepted until, zero to only rely on timeout
+}
+
+func newBuilderClient(urls []string, timeout time.Duration, cutoff time.Duration) *builderClient {
+	if timeout <= 0 {
+		log.Warn("Sanitizing builder timeout to default", "provided", timeout, "updated", DefaultConfig.BuilderTimeout)
+		timeout = DefaultConfig.BuilderTimeout
+	}
+
+	if cutoff < 0 {
+		log.Warn("Sanitizing builder cutoff to default", "provided", cutoff, "updated", DefaultConfig.BuilderCutoff)
+		cutoff = DefaultConfig.BuilderCutoff
+	}
+
+	c := &builderClient{timeout: timeout, cutoff: cutoff}
+	for _, url := range urls {
+		if url != "" {
+			c.endpoints = append(c.endpoints, &builderEndpoint{url: url})
+		}
+	}
+
+	return c
+}
+
+// deadline returns the time the auction for a block with the given timestamp
+// closes at: after the builder timeout, but no later than the cutoff before
+// the block is due.
+func (c *builderClient) deadline(now time.Time, timestamp uint64) time.Time {
+	deadline := now.Add(c.timeout)
+
+	if c.cutoff > 0 {
+		if closing := time.Unix(int64(timestamp), 0).Add(-c.cutoff); closing.Before(deadline) {
+			deadline = closing
+		}
+	}
+
+	return deadline
+}
+
+// auction asks every builder for a sealed bid on the requested slot until the
+// context is done, and records every valid bid received along with the time it
+// arrived at. The most valuable bid wins the auction.
+func (c *builderClient) auction(ctx context.Context, req *api.BidRequest) *builderAuction {
+	auction := &builderAuction{
+		record: &types.BuilderAuction{
+			Number:     uint64(req.Number),
+			ParentHash: req.ParentHash,
+		},
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		auction.record.Deadline = uint64(deadline.UnixMilli())
+	}
+
+	results := make(chan *builderBid, len(c.endpoints))
+
+	for _, endpoint := range c.endpoints {
+		go func(endpoint *builderEndpoint) {
+			bid, err := c.getHeader(ctx, endpoint, req)
+			if err != nil {
+				log.Debug("Failed to get builder bid", "builder", endpoint.url, "number", uint64(req.Number), "err", err)
+				results <- nil
+
+				return
+			}
+
+			builderBidCounter.Inc(1)
+			results <- &builderBid{endpoint: endpoint, bid: bid, received: time.Now()}
+		}(endpoint)
+	}
+
+	for range c.endpoints {
+		bid := <-results
+		if bid == nil {
+			continue
+		}
+
+		builder, _ := bid.bid.Message.BuilderAddress()
+
+		auction.record.Bids = append(auction.record.Bids, &types.BuilderAuctionBid{
+			Time:    uint64(bid.received.UnixMilli()),
+			Builder: builder,
+			Value:   bid.value(),
+			TxHash:  bid.bid.Message.TxHash,
+			BidHash: bid.bid.Message.Hash(),
+		})
+
+		if auction.best == nil || bid.value().Cmp(auction.best.value()) > 0 {
+			auction.best = bid
+		}
+	}
+
+	return auction
+}
+
+// getHeader requests a single bid and checks that it is signed and matches the
+// requested slot.
+func (c *builderClient) getHeader(ctx context.Context, endpoint *builderEndpoint, req *api.BidRequest) (*api.SignedBid, error) {
+	client, err := endpoint.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bid *api.SignedBid
+	if err := client.CallContext(ctx, &bid, "builder_getHeader", req); err != nil {
+		return nil, err
+	}
+
+	if bid == nil {
+		return nil, errNoBuilderBid
+	}
+
+	if err := bid.Verify(); err != nil {
+		return nil, err
+	}
+
+	if msg := bid.Message; msg.ParentHash != req.ParentHash || msg.Number != req.Number || msg.Proposer != req.Proposer {
+		return nil, fmt.Errorf("bid for a different slot: number %d, parent %s, proposer %s", uint64(msg.Number), msg.ParentHash, msg.Proposer)
+	}
+
+	return bid, nil
+}
+
+// getPayload reveals the transactions of the given bid.
+func (c *builderClient) getPayload(ctx context.Context, bid *builderBid) (types.Transactions, error) {
+	client, err := bid.endpoint.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload *api.Payload
+	if err := client.CallContext(ctx, &payload, "builder_getPayload", bid.bid); err != nil {
+		return nil, err
+	}
+
+	if payload == nil || payload.BidHash != bid.bid.Message.Hash() {
+		return nil, api.ErrPayloadMismatch
+	}
+
+	return payload.Decode(bid.bid.Message)
+}
+
+// close disconnects from all builders.
+func (c *builderClient) close() {
+	for _, endpoint := range c.endpoints {
+		endpoint.mu.Lock()
+		if endpoint.client != nil {
+			endpoint.client.Close()
+		}
+		endpoint.mu.Unlock()
+	}
+}
+
+// value returns the amount the builder promises to pay the proposer.
+func (b *builderBid) value() *big.Int {
+	return b.bid.Message.Value.ToInt()
+}
+
+// requestBuilderBid starts an auction among the builders for the block of env
+// in the background, so that the local block can be filled meanwhile. It
+// returns nil if no builder is configured or the local signer is not the
+// in-turn proposer. Otherwise the returned channel yields exactly one auction
+// once it closes.
+func (w *worker) requestBuilderBid(ctx context.Context, env *environment) <-chan *builderAuction {
+	engine, ok := w.engine.(*bor.Bor)
+	if w.builders == nil || !ok || !w.IsRunning() {
+		return nil
+	}
+
+	parent := w.chain.GetHeaderByHash(env.header.ParentHash)
+	if parent == nil {
+		return nil
+	}
+
+	slot, err := engine.GetSlot(ctx, w.chain, parent)
+	if err != nil {
+		log.Debug("Failed to retrieve slot for builder bids", "number", env.header.Number, "err", err)
+		return nil
+	}
+
+	// Only the in-turn proposer outsources its block, backup proposers keep
+	// sealing the local one.
+	if slot.Succession != 0 {
+		return nil
+	}
+
+	req := &api.BidRequest{
+		ParentHash: parent.Hash(),
+		Number:     hexutil.Uint64(slot.Number),
+		Timestamp:  hexutil.Uint64(env.header.Time),
+		GasLimit:   hexutil.Uint64(env.header.GasLimit),
+		BaseFee:    (*hexutil.Big)(env.header.BaseFee),
+		Span:       hexutil.Uint64(slot.Span),
+		Sprint:     hexutil.Uint64(slot.Sprint),
+		Proposer:   slot.Proposer,
+	}
+
+	result := make(chan *builderAuction, 1)
+
+	go func() {
+		ctx, cancel := context.WithDeadline(ctx, w.builders.deadline(time.Now(), env.header.Time))
+		defer cancel()
+
+		auction := w.builders.auction(ctx, req)
+		if auction.best == nil {
+			log.Debug("No builder bid for block", "number", slot.Number)
+		}
+		result <- auction
+	}()
+
+	return result
+}
+
+// applyBuilderBid compares the winning builder bid with the locally built
+// block and, if the bid is more valuable, returns a new environment holding
+// the builder's body re-executed on top of the same header. The local
+// environment is returned whenever the builder is late, its payload can't be
+// retrieved or fails validation, or it doesn't pay what it bid. The outcome
+// is stored along with the auction record.
+func (w *worker) applyBuilderBid(ctx context.Context, env *environment, auction *builderAuction) *environment {
+	if auction == nil {
+		builderFallbackCounter.Inc(1)
+		return env
+	}
+
+	defer w.writeBuilderAuction(auction.record)
+
+	bid := auction.best
+	if bid == nil {
+		builderFallbackCounter.Inc(1)
+		auction.record.Reason = errNoBuilderBid.Error()
+
+		return env
+	}
+
+	local := envFees(env)
+	if bid.value().Cmp(local) <= 0 {
+		log.Debug("Local block more valuable than builder bid", "number", env.header.Number, "local", local, "bid", bid.value())
+		auction.record.Reason = fmt.Sprintf("local block more valuable: local %v, bid %v", local, bid.value())
+
+		return env
+	}
+
+	fallback := func(err error) *environment {
+		builderFallbackCounter.Inc(1)
+		log.Warn("Discarding builder block, sealing local block", "number", env.header.Number, "builder", bid.endpoint.url, "err", err)
+
+		auction.record.Reason = err.Error()
+
+		return env
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.builders.timeout)
+	defer cancel()
+
+	txs, err := w.builders.getPayload(ctx, bid)
+	if err != nil {
+		return fallback(err)
+	}
+
+	builderEnv, err := w.executePayload(env, txs)
+	if err != nil {
+		return fallback(err)
+	}
+
+	if value := envFees(builderEnv); value.Cmp(bid.value()) < 0 {
+		builderEnv.discard()
+		return fallback(fmt.Errorf("builder paid %v, bid %v", value, bid.value()))
+	}
+
+	if err := w.validateBuilderBlock(ctx, builderEnv); err != nil {
+		builderEnv.discard()
+		return fallback(err)
+	}
+
+	builderWinCounter.Inc(1)
+
+	auction.record.Winner = bid.bid.Message.Hash()
+
+	feesInEther := new(big.Float).Quo(new(big.Float).SetInt(bid.value()), big.NewFloat(params.Ether))
+	log.Info("Using builder block", "number", env.header.Number, "builder", bid.endpoint.url,
+		"txs", builderEnv.tcount, "gas", builderEnv.header.GasUsed, "fees", feesInEther)
+
+	return builderEnv
+}
+
+// validateBuilderBlock fully validates the block assembled from env before it
+// gets signed: the body must match the header, and executing it again on top
+// of the parent state, independently of env, must yield the same receipts and
+// state root.
+func (w *worker) validateBuilderBlock(ctx context.Context, env *environment) error {
+	parent := w.chain.GetHeaderByHash(env.header.ParentHash)
+	if parent == nil {
+		return errors.New("missing parent")
+	}
+
+	work := env.copy()
+
+	block, err := w.engine.FinalizeAndAssemble(ctx, core.NewStateSyncCollector(w.chain), work.header, work.state, work.txs, nil, work.receipts, nil)
+	work.discard()
+
+	if err != nil {
+		return fmt.Errorf("failed to assemble block: %w", err)
+	}
+
+	if err := w.chain.Validator().ValidateBody(block); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+
+	statedb, err := w.chain.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+
+	var (
+		header   = block.Header()
+		author   = env.coinbase
+		gasPool  = new(core.GasPool).AddGas(header.GasLimit)
+		receipts = make(types.Receipts, 0, len(block.Transactions()))
+		usedGas  uint64
+	)
+
+	for i, tx := range block.Transactions() {
+		statedb.SetTxContext(tx.Hash(), i)
+
+		receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &author, gasPool, statedb, header, tx, &usedGas, *w.chain.GetVMConfig(), ctx)
+		if err != nil {
+			return fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash(), err)
+		}
+
+		receipts = append(receipts, receipt)
+	}
+
+	w.engine.Finalize(core.NewStateSyncCollector(w.chain), types.CopyHeader(header), statedb, block.Transactions(), nil, nil)
+
+	if err := w.chain.Validator().ValidateState(block, statedb, receipts, usedGas); err != nil {
+		return fmt.Errorf("invalid state: %w", err)
+	}
+
+	return nil
+}
+
+// writeBuilderAuction stores the record of an auction, replacing the one of
+// the previous auction run for the same block as the work is recommitted.
+func (w *worker) writeBuilderAuction(record *types.BuilderAuction) {
+	w.auctionMu.Lock()
+	defer w.auctionMu.Unlock()
+
+	rawdb.WriteBuilderAuction(w.chain.DB(), record)
+}
+
+// sealedBuilderBlock links the given block, just written to the chain, to the
+// auction whose winning body it contains.
+func (w *worker) sealedBuilderBlock(block *types.Block) {
+	w.auctionMu.Lock()
+	defer w.auctionMu.Unlock()
+
+	db := w.chain.DB()
+
+	auction := rawdb.ReadBuilderAuction(db, block.NumberU64(), block.ParentHash())
+	if auction == nil {
+		return
+	}
+
+	if bid := auction.WinningBid(); bid != nil && bid.TxHash == block.TxHash() {
+		auction.BlockHash = block.Hash()
+		rawdb.WriteBuilderAuction(db, auction)
+	}
+}
+
+// pruneBuilderAuctions deletes the records of the auctions run for blocks
+// more than builderAuctionRetention blocks below the given head.
+func (w *worker) pruneBuilderAuctions(head uint64) {
+	if head <= builderAuctionRetention {
+		return
+	}
+
+	w.auctionMu.Lock()
+	defer w.auctionMu.Unlock()
+
+	rawdb.DeleteBuilderAuctionsBefore(w.chain.DB(), head-builderAuctionRetention)
+}
+
+// executePayload applies the given transactions on a fresh environment built
+// on the same parent and header as env. Any failing transaction invalidates
+// the whole payload.
+func (w *worker) executePayload(env *environment, txs types.Transactions) (*environment, error) {
+	parent := w.chain.GetHeaderByHash(env.header.ParentHash)
+	if parent == nil {
+		return nil, errors.New("missing parent")
+	}
+
+	header := types.CopyHeader(env.header)
+	header.GasUsed = 0
+
+	payloadEnv, err := w.makeEnv(parent, header, env.coinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadEnv.gasPool = new(core.GasPool).AddGas(header.GasLimit)
+
+	for i, tx := range txs {
+		payloadEnv.state.SetTxContext(tx.Hash(), payloadEnv.tcount)
+
+		if _, err := w.commitTransaction(payloadEnv, tx, context.Background()); err != nil {
+			payloadEnv.discard()
+			return nil, fmt.Errorf("transaction %d (%s): %w", i, tx.Hash(), err)
+		}
+
+		payloadEnv.tcount++
+	}
+
+	return payloadEnv, nil
+}
+
+// envFees computes the value of the transactions included in env to the
+// coinbase: the tips and the transfers, as for the blocks of external builders.
+func envFees(env *environment) *big.Int {
+	return types.NewBlockProfit(nil, env.header, env.coinbase, env.txs, env.receipts, env.balances, nil).Value()
+}
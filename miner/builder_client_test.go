@@ -0,0 +1,371 @@
+package miner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/ethereum/go-ethereum/builder/api"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	borapi "github.com/ethereum/go-ethereum/consensus/bor/api"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/tests/bor/mocks"
+)
+
+// fakeBuilder is an in-process block builder serving a fixed list of
+// transactions for every slot.
+type fakeBuilder struct {
+	key     *ecdsa.PrivateKey
+	txs     types.Transactions
+	overbid *big.Int // Added to the real value of the block when bidding
+}
+
+func (b *fakeBuilder) GetHeader(req api.BidRequest) (*api.SignedBid, error) {
+	value := new(big.Int)
+	for _, tx := range b.txs {
+		tip, _ := tx.EffectiveGasTip(req.BaseFee.ToInt())
+		value.Add(value, new(big.Int).Mul(tip, new(big.Int).SetUint64(tx.Gas())))
+	}
+
+	if b.overbid != nil {
+		value.Add(value, b.overbid)
+	}
+
+	return api.SignBid(&api.Bid{
+		ParentHash: req.ParentHash,
+		Number:     req.Number,
+		Proposer:   req.Proposer,
+		TxHash:     api.TxHash(b.txs),
+		Value:      (*hexutil.Big)(value),
+		TxCount:    hexutil.Uint64(len(b.txs)),
+	}, b.key)
+}
+
+func (b *fakeBuilder) GetPayload(bid api.SignedBid) (*api.Payload, error) {
+	return api.NewPayload(bid.Message.Hash(), b.txs)
+}
+
+func startFakeBuilder(t *testing.T, builder *fakeBuilder) string {
+	t.Helper()
+
+	server := rpc.NewServer("test", 0, 0)
+	if err := server.RegisterName("builder", builder); err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer := httptest.NewServer(server)
+
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	return httpServer.URL
+}
+
+func newBuilderTestWorker(t testing.TB, builders ...string) (*worker, *params.ChainConfig) {
+	t.Helper()
+
+	return newBorTestWorker(t, params.BorUnittestChainConfig, builders...)
+}
+
+// newBorTestWorker creates a running worker on a bor chain only containing the
+// genesis block, with TestBankAddress as the single validator.
+func newBorTestWorker(t testing.TB, borConfig *params.ChainConfig, builders ...string) (*worker, *params.ChainConfig) {
+	t.Helper()
+
+	chainConfig := *borConfig
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	ethAPIMock := borapi.NewMockCaller(ctrl)
+	ethAPIMock.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	spanner := bor.NewMockSpanner(ctrl)
+	spanner.EXPECT().GetCurrentValidatorsByHash(gomock.Any(), gomock.Any(), gomock.Any()).Return([]*valset.Validator{
+		{
+			ID:               0,
+			Address:          TestBankAddress,
+			VotingPower:      100,
+			ProposerPriority: 0,
+		},
+	}, nil).AnyTimes()
+	spanner.EXPECT().GetCurrentSpan(gomock.Any(), gomock.Any()).Return(&span.Span{ID: 1, StartBlock: 0, EndBlock: 255}, nil).AnyTimes()
+
+	heimdallClientMock := mocks.NewMockIHeimdallClient(ctrl)
+	heimdallClientMock.EXPECT().Close().AnyTimes()
+
+	db, _, _ := NewDBForFakes(t)
+	engine := NewFakeBor(t, db, &chainConfig, ethAPIMock, spanner, heimdallClientMock, bor.NewMockGenesisContract(ctrl))
+
+	config := *testConfig
+	config.Builders = builders
+	config.BuilderTimeout = time.Second
+
+	backend := newTestWorkerBackend(t, &chainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+
+	//nolint:staticcheck
+	w := newWorker(&config, &chainConfig, engine, backend, new(event.TypeMux), nil, false)
+	w.setEtherbase(TestBankAddress)
+	w.running.Store(true)
+
+	t.Cleanup(func() {
+		w.close()
+		engine.Close()
+	})
+
+	return w, &chainConfig
+}
+
+func newBuilderTestTx(t *testing.T, chainConfig *params.ChainConfig, nonce uint64, tip int64) *types.Transaction {
+	t.Helper()
+
+	return types.MustSignNewTx(testBankKey, types.LatestSigner(chainConfig), &types.DynamicFeeTx{
+		ChainID:   chainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(tip + 100*params.InitialBaseFee),
+		Gas:       params.TxGas,
+		To:        &testUserAddress,
+		Value:     big.NewInt(1),
+	})
+}
+
+func TestBuilderBidReplacesLocalBlock(t *testing.T) {
+	t.Parallel()
+
+	builderKey, _ := crypto.GenerateKey()
+
+	var (
+		builder = &fakeBuilder{key: builderKey}
+		w, cfg  = newBuilderTestWorker(t, "", startFakeBuilder(t, builder))
+	)
+
+	builder.txs = types.Transactions{
+		newBuilderTestTx(t, cfg, 0, params.GWei),
+		newBuilderTestTx(t, cfg, 1, params.GWei),
+	}
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: TestBankAddress})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	bidCh := w.requestBuilderBid(context.Background(), env)
+	if bidCh == nil {
+		t.Fatal("in-turn proposer didn't request builder bids")
+	}
+
+	got := w.applyBuilderBid(context.Background(), env, <-bidCh)
+	if got == env {
+		t.Fatal("more valuable builder block was not used")
+	}
+	defer got.discard()
+
+	if len(got.txs) != 2 || got.txs[0].Hash() != builder.txs[0].Hash() || got.txs[1].Hash() != builder.txs[1].Hash() {
+		t.Fatalf("unexpected builder body: %v", got.txs)
+	}
+
+	if want := uint64(2 * params.TxGas); got.header.GasUsed != want {
+		t.Fatalf("gas used mismatch: have %d, want %d", got.header.GasUsed, want)
+	}
+}
+
+func TestBuilderBidFallback(t *testing.T) {
+	t.Parallel()
+
+	builderKey, _ := crypto.GenerateKey()
+
+	tests := []struct {
+		name    string
+		builder func(cfg *params.ChainConfig) *fakeBuilder
+	}{
+		{
+			name: "overbid",
+			builder: func(cfg *params.ChainConfig) *fakeBuilder {
+				return &fakeBuilder{
+					key:     builderKey,
+					txs:     types.Transactions{newBuilderTestTx(t, cfg, 0, params.GWei)},
+					overbid: big.NewInt(1),
+				}
+			},
+		},
+		{
+			name: "invalid nonce",
+			builder: func(cfg *params.ChainConfig) *fakeBuilder {
+				return &fakeBuilder{
+					key: builderKey,
+					txs: types.Transactions{newBuilderTestTx(t, cfg, 5, params.GWei)},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			builder := new(fakeBuilder)
+			w, cfg := newBuilderTestWorker(t, startFakeBuilder(t, builder))
+			*builder = *tt.builder(cfg)
+
+			env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: TestBankAddress})
+			if err != nil {
+				t.Fatalf("failed to prepare work: %v", err)
+			}
+			defer env.discard()
+
+			bidCh := w.requestBuilderBid(context.Background(), env)
+			if bidCh == nil {
+				t.Fatal("in-turn proposer didn't request builder bids")
+			}
+
+			if got := w.applyBuilderBid(context.Background(), env, <-bidCh); got != env {
+				t.Fatal("invalid builder block was used")
+			}
+		})
+	}
+}
+
+func TestBuilderBidUnreachable(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newBuilderTestWorker(t, "http://127.0.0.1:1")
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: TestBankAddress})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	bidCh := w.requestBuilderBid(context.Background(), env)
+	if bidCh == nil {
+		t.Fatal("in-turn proposer didn't request builder bids")
+	}
+
+	if got := w.applyBuilderBid(context.Background(), env, <-bidCh); got != env {
+		t.Fatal("local block was not used without builder bids")
+	}
+}
+
+func TestBuilderAuction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lowKey, _  = crypto.GenerateKey()
+		highKey, _ = crypto.GenerateKey()
+
+		low    = &fakeBuilder{key: lowKey}
+		high   = &fakeBuilder{key: highKey}
+		w, cfg = newBuilderTestWorker(t, startFakeBuilder(t, low), startFakeBuilder(t, high))
+	)
+
+	low.txs = types.Transactions{newBuilderTestTx(t, cfg, 0, params.GWei)}
+	high.txs = types.Transactions{newBuilderTestTx(t, cfg, 0, 2*params.GWei), newBuilderTestTx(t, cfg, 1, 2*params.GWei)}
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: TestBankAddress})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	auctionCh := w.requestBuilderBid(context.Background(), env)
+	if auctionCh == nil {
+		t.Fatal("in-turn proposer didn't run a builder auction")
+	}
+
+	got := w.applyBuilderBid(context.Background(), env, <-auctionCh)
+	if got == env {
+		t.Fatal("winning builder block was not used")
+	}
+	defer got.discard()
+
+	if len(got.txs) != 2 || got.txs[0].Hash() != high.txs[0].Hash() {
+		t.Fatalf("unexpected builder body: %v", got.txs)
+	}
+
+	number := env.header.Number.Uint64()
+
+	auctions := rawdb.ReadBuilderAuctions(w.chain.DB(), number)
+	if len(auctions) != 1 {
+		t.Fatalf("auction record count mismatch: have %d, want 1", len(auctions))
+	}
+
+	record := auctions[0]
+	if len(record.Bids) != 2 {
+		t.Fatalf("bid count mismatch: have %d, want 2", len(record.Bids))
+	}
+
+	winner := record.WinningBid()
+	if winner == nil || winner.Builder != crypto.PubkeyToAddress(highKey.PublicKey) || winner.TxHash != api.TxHash(high.txs) {
+		t.Fatalf("winning bid mismatch: %+v", winner)
+	}
+
+	for _, bid := range record.Bids {
+		if bid.Time == 0 || bid.Time > record.Deadline {
+			t.Errorf("bid from %s received at %d, auction closed at %d", bid.Builder, bid.Time, record.Deadline)
+		}
+	}
+
+	// Sealing the winning body links the block to the auction
+	block, err := w.engine.FinalizeAndAssemble(context.Background(), w.chain, got.header, got.state, got.txs, nil, got.receipts, nil)
+	if err != nil {
+		t.Fatalf("failed to assemble block: %v", err)
+	}
+
+	w.sealedBuilderBlock(block)
+
+	if auction := rawdb.ReadBuilderAuction(w.chain.DB(), number, block.ParentHash()); auction.BlockHash != block.Hash() {
+		t.Fatalf("sealed block hash mismatch: have %s, want %s", auction.BlockHash, block.Hash())
+	}
+
+	// Recommitting the work replaces the record of the auction
+	w.writeBuilderAuction(&types.BuilderAuction{Number: number, ParentHash: env.header.ParentHash, Reason: errNoBuilderBid.Error()})
+
+	if auctions := rawdb.ReadBuilderAuctions(w.chain.DB(), number); len(auctions) != 1 || auctions[0].Reason != errNoBuilderBid.Error() {
+		t.Fatalf("recommitted auction mismatch: %+v", auctions)
+	}
+}
+
+func TestBuilderAuctionDeadline(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now       = time.Unix(1000, 0)
+		timestamp = uint64(1002)
+	)
+
+	tests := []struct {
+		cutoff time.Duration
+		want   time.Time
+	}{
+		{0, now.Add(time.Second)},
+		{500 * time.Millisecond, now.Add(time.Second)},
+		{1500 * time.Millisecond, now.Add(500 * time.Millisecond)},
+		{5 * time.Second, now.Add(-3 * time.Second)},
+	}
+
+	for i, tt := range tests {
+		c := newBuilderClient(nil, time.Second, tt.cutoff)
+		if have := c.deadline(now, timestamp); !have.Equal(tt.want) {
+			t.Errorf("test %d: deadline mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
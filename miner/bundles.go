@@ -0,0 +1,145 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	errBundleReverted = errors.New("bundle transaction reverted")
+
+	bundleCommittedMeter = metrics.NewRegisteredMeter("worker/bundles/committed", nil)
+	bundleFailedMeter    = metrics.NewRegisteredMeter("worker/bundles/failed", nil)
+)
+
+// simulatedBundle is a bundle together with the outcome of executing it at the
+// top of the block.
+type simulatedBundle struct {
+	bundle  *bundlepool.Bundle
+	gasUsed uint64
+	payment *big.Int // Increase of the coinbase balance caused by the bundle
+	price   *big.Int // Effective coinbase payment per unit of gas
+}
+
+// simulateBundles executes every bundle targeting the block of env on a copy
+// of its state and returns the valid ones, most profitable per gas first.
+func (w *worker) simulateBundles(env *environment) []*simulatedBundle {
+	pool := w.eth.BundlePool()
+	if pool == nil {
+		return nil
+	}
+
+	bundles := pool.Bundles(env.header.Number.Uint64(), env.header.Time)
+	if len(bundles) == 0 {
+		return nil
+	}
+
+	simulated := make([]*simulatedBundle, 0, len(bundles))
+
+	for _, bundle := range bundles {
+		simEnv := env.copy()
+
+		gasUsed, payment, err := w.applyBundle(simEnv, bundle)
+		simEnv.discard()
+
+		if err != nil {
+			bundleFailedMeter.Mark(1)
+			log.Debug("Dropping invalid bundle", "number", env.header.Number, "hash", bundle.Hash(), "err", err)
+
+			continue
+		}
+
+		simulated = append(simulated, &simulatedBundle{
+			bundle:  bundle,
+			gasUsed: gasUsed,
+			payment: payment,
+			price:   new(big.Int).Div(payment, new(big.Int).SetUint64(gasUsed)),
+		})
+	}
+
+	sort.SliceStable(simulated, func(i, j int) bool {
+		return simulated[i].price.Cmp(simulated[j].price) > 0
+	})
+
+	return simulated
+}
+
+// applyBundle executes the transactions of the bundle on env in order, and
+// returns the gas they used along with the payment they made to the coinbase.
+// On error env is left in a partially applied state and must be discarded.
+func (w *worker) applyBundle(env *environment, bundle *bundlepool.Bundle) (uint64, *big.Int, error) {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+
+	var (
+		gasUsed = env.header.GasUsed
+		balance = env.state.GetBalance(env.coinbase)
+	)
+
+	for i, tx := range bundle.Txs {
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+
+		if _, err := w.commitTransaction(env, tx, context.Background()); err != nil {
+			return 0, nil, fmt.Errorf("transaction %d (%s): %w", i, tx.Hash(), err)
+		}
+
+		env.tcount++
+
+		if receipt := env.receipts[len(env.receipts)-1]; receipt.Status == types.ReceiptStatusFailed && !bundle.CanRevert(tx.Hash()) {
+			return 0, nil, fmt.Errorf("%w: transaction %d (%s)", errBundleReverted, i, tx.Hash())
+		}
+	}
+
+	payment := new(big.Int).Sub(env.state.GetBalance(env.coinbase), balance)
+
+	return env.header.GasUsed - gasUsed, payment, nil
+}
+
+// commitBundles includes the pending simulated bundles of env which pay at
+// least minPrice per gas to the coinbase, all of them if minPrice is nil. Each
+// bundle is executed on a copy of the environment, which replaces env only if
+// the whole bundle succeeds.
+func (w *worker) commitBundles(env *environment, minPrice *big.Int) {
+	for len(env.bundles) > 0 {
+		sim := env.bundles[0]
+		if minPrice != nil && sim.price.Cmp(minPrice) < 0 {
+			return
+		}
+
+		remaining := env.bundles[1:]
+
+		if env.gasPool != nil && env.gasPool.Gas() < sim.gasUsed {
+			env.bundles = remaining
+			continue
+		}
+
+		bundleEnv := env.copy()
+
+		if _, _, err := w.applyBundle(bundleEnv, sim.bundle); err != nil {
+			bundleEnv.discard()
+			bundleFailedMeter.Mark(1)
+			log.Debug("Skipping bundle", "number", env.header.Number, "hash", sim.bundle.Hash(), "err", err)
+
+			env.bundles = remaining
+
+			continue
+		}
+
+		bundleCommittedMeter.Mark(1)
+		log.Debug("Committed bundle", "number", env.header.Number, "hash", sim.bundle.Hash(), "txs", len(sim.bundle.Txs), "gas", sim.gasUsed, "price", sim.price)
+
+		env.discard()
+		*env = *bundleEnv
+		env.bundles = remaining
+	}
+}
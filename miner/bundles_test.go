@@ -0,0 +1,107 @@
+package miner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestFillTransactionsWithBundles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pending func(cfg *params.ChainConfig) types.Transactions
+		bundle  func(cfg *params.ChainConfig) types.Transactions
+		want    func(pending, bundle types.Transactions) types.Transactions
+	}{
+		{
+			name: "bundle outbids pool",
+			pending: func(cfg *params.ChainConfig) types.Transactions {
+				return types.Transactions{newBuilderTestTx(t, cfg, 0, 1)}
+			},
+			bundle: func(cfg *params.ChainConfig) types.Transactions {
+				return types.Transactions{newBuilderTestTx(t, cfg, 0, params.GWei), newBuilderTestTx(t, cfg, 1, params.GWei)}
+			},
+			want: func(pending, bundle types.Transactions) types.Transactions {
+				return bundle
+			},
+		},
+		{
+			name: "pool outbids bundle",
+			pending: func(cfg *params.ChainConfig) types.Transactions {
+				return types.Transactions{newBuilderTestTx(t, cfg, 0, params.GWei)}
+			},
+			bundle: func(cfg *params.ChainConfig) types.Transactions {
+				return types.Transactions{newBuilderTestTx(t, cfg, 0, 1)}
+			},
+			want: func(pending, bundle types.Transactions) types.Transactions {
+				return pending
+			},
+		},
+		{
+			name: "failing bundle excluded atomically",
+			pending: func(cfg *params.ChainConfig) types.Transactions {
+				return types.Transactions{newBuilderTestTx(t, cfg, 0, 1)}
+			},
+			bundle: func(cfg *params.ChainConfig) types.Transactions {
+				return types.Transactions{newBuilderTestTx(t, cfg, 0, params.GWei), newBuilderTestTx(t, cfg, 5, params.GWei)}
+			},
+			want: func(pending, bundle types.Transactions) types.Transactions {
+				return pending
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w, cfg := newBuilderTestWorker(t)
+
+			var (
+				pending = tt.pending(cfg)
+				bundle  = tt.bundle(cfg)
+			)
+
+			for _, err := range w.eth.TxPool().AddLocals(pending) {
+				if err != nil {
+					t.Fatalf("failed to add pending transaction: %v", err)
+				}
+			}
+
+			if err := w.eth.BundlePool().Add(&bundlepool.Bundle{Txs: bundle, BlockNumber: 1}); err != nil {
+				t.Fatalf("failed to add bundle: %v", err)
+			}
+
+			// Pay the fees to a third party, as the bank also sends all transactions
+			env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: common.HexToAddress("0xc014ba5e")})
+			if err != nil {
+				t.Fatalf("failed to prepare work: %v", err)
+			}
+			defer env.discard()
+
+			if err := w.fillTransactions(context.Background(), nil, env, context.Background()); err != nil {
+				t.Fatalf("failed to fill transactions: %v", err)
+			}
+
+			want := tt.want(pending, bundle)
+			if len(env.txs) != len(want) {
+				t.Fatalf("transaction count mismatch: have %d, want %d", len(env.txs), len(want))
+			}
+
+			for i, tx := range env.txs {
+				if tx.Hash() != want[i].Hash() {
+					t.Errorf("transaction %d mismatch: have %s, want %s", i, tx.Hash(), want[i].Hash())
+				}
+			}
+		})
+	}
+}
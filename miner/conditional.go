@@ -0,0 +1,130 @@
+package miner
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	cmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// conditionalSkipsLimit is the number of conditional transactions the worker
+// remembers the last skip reason of.
+const conditionalSkipsLimit = 4096
+
+var (
+	conditionalCommittedMeter = metrics.NewRegisteredMeter("worker/conditional/committed", nil)
+	conditionalSkippedMeter   = metrics.NewRegisteredMeter("worker/conditional/skipped", nil)
+)
+
+// ConditionalTxSkip records why the worker last left a conditional transaction
+// out of a block.
+type ConditionalTxSkip struct {
+	Number uint64 // Block the transaction was left out of
+	Reason string
+}
+
+// conditionalSkipCache maps conditional transactions to their last skip.
+type conditionalSkipCache = lru.Cache[common.Hash, ConditionalTxSkip]
+
+func newConditionalSkips() *conditionalSkipCache {
+	return lru.NewCache[common.Hash, ConditionalTxSkip](conditionalSkipsLimit)
+}
+
+// validateConditionalTx checks the options of a conditional transaction against
+// the block and state of env, right before its execution.
+func (w *worker) validateConditionalTx(env *environment, tx *types.Transaction) error {
+	options := tx.GetOptions()
+
+	if err := env.header.ValidateBlockNumberOptions4337(options.BlockNumberMin, options.BlockNumberMax); err != nil {
+		return err
+	}
+
+	if err := env.header.ValidateTimestampOptions4337(options.TimestampMin, options.TimestampMax); err != nil {
+		return err
+	}
+
+	return env.state.ValidateKnownAccounts(options.KnownAccounts)
+}
+
+// skipConditionalTx records that a conditional transaction was left out of the
+// block of env because of err.
+func (w *worker) skipConditionalTx(env *environment, tx *types.Transaction, err error) {
+	log.Trace("Dropping conditional transaction", "hash", tx.Hash(), "reason", err)
+
+	conditionalSkippedMeter.Mark(1)
+	w.conditionalSkips.Add(tx.Hash(), ConditionalTxSkip{Number: env.header.Number.Uint64(), Reason: err.Error()})
+}
+
+// splitConditionalTxs moves the conditional transactions heading the nonce
+// sequence of each sender out of the given sets, and returns them grouped by
+// sender. The remaining transactions of these senders stay in their set.
+func splitConditionalTxs(sets ...map[common.Address]types.Transactions) map[common.Address]types.Transactions {
+	conditional := make(map[common.Address]types.Transactions)
+
+	for _, set := range sets {
+		for sender, txs := range set {
+			n := 0
+			for n < len(txs) && txs[n].GetOptions() != nil {
+				n++
+			}
+
+			if n == 0 {
+				continue
+			}
+
+			conditional[sender] = txs[:n]
+
+			if n == len(txs) {
+				delete(set, sender)
+			} else {
+				set[sender] = txs[n:]
+			}
+		}
+	}
+
+	return conditional
+}
+
+// commitConditionalTxs commits the conditional transactions ahead of the rest
+// of the block, highest effective tip first and within the share of the block
+// gas limit set aside for them. Bundles are left for the regular lanes.
+func (w *worker) commitConditionalTxs(env *environment, pending map[common.Address]types.Transactions, interrupt *atomic.Int32, interruptCtx context.Context) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+
+	var (
+		available = env.gasPool.Gas()
+		budget    = env.header.GasLimit / 100 * w.config.ConditionalGasShare
+		bundles   = env.bundles
+	)
+
+	if budget > available {
+		budget = available
+	}
+
+	env.gasPool.SetGas(budget)
+	env.bundles = nil
+
+	defer func() {
+		env.gasPool.SetGas(available - (budget - env.gasPool.Gas()))
+		env.bundles = bundles
+	}()
+
+	var baseFee *uint256.Int
+	if env.header.BaseFee != nil {
+		baseFee = cmath.FromBig(env.header.BaseFee)
+	}
+
+	txs := types.NewTransactionsByPriceAndNonce(env.signer, pending, baseFee)
+
+	return w.commitTransactions(env, txs, interrupt, interruptCtx)
+}
@@ -0,0 +1,102 @@
+package miner
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestSplitConditionalTxs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cfg     = params.TestChainConfig
+		senders = []common.Address{{0x01}, {0x02}, {0x03}}
+		txs     = make(types.Transactions, 3)
+	)
+
+	for i := range txs {
+		txs[i] = newBuilderTestTx(t, cfg, uint64(i), params.GWei)
+	}
+
+	conditional := func(tx *types.Transaction) *types.Transaction {
+		tx = newBuilderTestTx(t, cfg, tx.Nonce(), params.GWei)
+		tx.PutOptions(&types.OptionsAA4337{})
+
+		return tx
+	}
+
+	var (
+		local  = map[common.Address]types.Transactions{senders[0]: {conditional(txs[0]), txs[1]}}
+		remote = map[common.Address]types.Transactions{senders[1]: {conditional(txs[0]), conditional(txs[1])}, senders[2]: {txs[0], conditional(txs[1])}}
+	)
+
+	split := splitConditionalTxs(local, remote)
+
+	if len(split) != 2 || len(split[senders[0]]) != 1 || len(split[senders[1]]) != 2 {
+		t.Fatalf("conditional lane mismatch: have %v", split)
+	}
+
+	if len(local[senders[0]]) != 1 || local[senders[0]][0] != txs[1] {
+		t.Fatalf("local remainder mismatch: have %v", local[senders[0]])
+	}
+
+	if _, ok := remote[senders[1]]; ok || len(remote[senders[2]]) != 2 {
+		t.Fatalf("remote remainder mismatch: have %v", remote)
+	}
+}
+
+func TestCommitConditionalTxs(t *testing.T) {
+	t.Parallel()
+
+	w, cfg := newBuilderTestWorker(t)
+	w.config.ConditionalGasShare = 50
+
+	var (
+		valid   = newBuilderTestTx(t, cfg, 0, params.GWei)
+		expired = newBuilderTestTx(t, cfg, 1, params.GWei)
+		regular = newBuilderTestTx(t, cfg, 2, params.GWei)
+	)
+
+	valid.PutOptions(&types.OptionsAA4337{BlockNumberMax: big.NewInt(1)})
+	expired.PutOptions(&types.OptionsAA4337{BlockNumberMax: big.NewInt(0)})
+
+	for _, err := range w.eth.TxPool().AddLocals(types.Transactions{valid, expired, regular}) {
+		if err != nil {
+			t.Fatalf("failed to add pending transaction: %v", err)
+		}
+	}
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: common.HexToAddress("0xc014ba5e")})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	if err := w.fillTransactions(context.Background(), nil, env, context.Background()); err != nil {
+		t.Fatalf("failed to fill transactions: %v", err)
+	}
+
+	// The expired transaction is left out, along with the rest of its sender's
+	if len(env.txs) != 1 || env.txs[0].Hash() != valid.Hash() {
+		t.Fatalf("transactions mismatch: have %v, want %s", env.txs, valid.Hash())
+	}
+
+	if gas := env.gasPool.Gas(); gas != env.header.GasLimit-params.TxGas {
+		t.Fatalf("remaining gas mismatch: have %d, want %d", gas, env.header.GasLimit-params.TxGas)
+	}
+
+	skip, ok := w.conditionalSkips.Get(expired.Hash())
+	if !ok || skip.Number != 1 || skip.Reason == "" {
+		t.Fatalf("skip record mismatch: have %+v (found %v)", skip, ok)
+	}
+
+	if _, ok := w.conditionalSkips.Get(valid.Hash()); ok {
+		t.Fatalf("committed transaction recorded as skipped")
+	}
+}
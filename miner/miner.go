@@ -27,6 +27,8 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/privatepool"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -41,6 +43,8 @@ import (
 type Backend interface {
 	BlockChain() *core.BlockChain
 	TxPool() *txpool.TxPool
+	BundlePool() *bundlepool.BundlePool
+	PrivatePool() *privatepool.PrivatePool
 	PeerCount() int
 }
 
@@ -56,6 +60,14 @@ type Config struct {
 	Recommit            time.Duration  // The time interval for miner to re-create mining work.
 	Noverify            bool           // Disable remote mining solution verification(only useful in ethash).
 	CommitInterruptFlag bool           // Interrupt commit when time is up ( default = true)
+	Builders            []string       `toml:",omitempty"` // RPC endpoints of external builders asked for in-turn block bodies
+	BuilderTimeout      time.Duration  // The maximum time to wait for builder bids
+	BuilderCutoff       time.Duration  // Time before the block timestamp the builder auction closes at, zero to disable
+	Payout              PayoutConfig   // Payment of the proposers blocks are built for
+	ConditionalGasShare uint64         // Percentage of the block gas limit conditional transactions are committed in ahead of the others, zero to disable
+	Algorithm           string         // Block building algorithm picking and ordering the pending transactions
+	ParallelBuild       bool           // Execute the candidate transactions in parallel and record their dependencies in the blocks
+	ParallelOrder       bool           // Reorder the candidate transactions tipping the same to shorten the chains of dependent transactions, with ParallelBuild
 
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
 }
@@ -71,6 +83,7 @@ var DefaultConfig = Config{
 	// run 3 rounds.
 	Recommit:          2 * time.Second,
 	NewPayloadTimeout: 2 * time.Second,
+	BuilderTimeout:    500 * time.Millisecond,
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -273,7 +286,43 @@ func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscript
 	return miner.worker.pendingLogsFeed.Subscribe(ch)
 }
 
+// SubscribePayloadUpdates starts delivering every improved version of the
+// payloads being built and of the local sealing work to the given channel.
+func (miner *Miner) SubscribePayloadUpdates(ch chan<- *PayloadUpdate) event.Subscription {
+	return miner.worker.payloadFeed.Subscribe(ch)
+}
+
+// ConditionalTxSkip returns why the conditional transaction with the given
+// hash was last left out of a block, if the worker still remembers it.
+func (miner *Miner) ConditionalTxSkip(hash common.Hash) (ConditionalTxSkip, bool) {
+	return miner.worker.conditionalSkips.Get(hash)
+}
+
 // BuildPayload builds the payload according to the provided parameters.
 func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
 	return miner.worker.buildPayload(args)
 }
+
+// BuildBorPayload builds a Bor payload according to the provided parameters.
+func (miner *Miner) BuildBorPayload(args *BorPayloadArgs) (*Payload, error) {
+	return miner.worker.buildBorPayload(args)
+}
+
+// BuildBlock builds a block on top of the given parent without sealing it,
+// paying coinbase. It returns the block along with its value to coinbase: the
+// tips and transfers credited to it, or the payout transferred to it if the
+// miner is configured to pay proposers through a builder account.
+func (miner *Miner) BuildBlock(parent common.Hash, timestamp uint64, coinbase common.Address) (*types.Block, *big.Int, error) {
+	return miner.worker.getSealingBlock(parent, timestamp, coinbase, common.Hash{}, nil, false)
+}
+
+// FeeRecipient returns the account BuildBlock credits the fees of the blocks
+// built for coinbase to: the builder account if the miner pays proposers
+// through a final transaction, coinbase itself otherwise.
+func (miner *Miner) FeeRecipient(coinbase common.Address) common.Address {
+	if miner.worker.config.Payout.enabled() {
+		return miner.worker.config.Payout.address()
+	}
+
+	return coinbase
+}
@@ -0,0 +1,630 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"runtime"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// maxSpeculativeBatch is the maximum number of candidate transactions
+	// executed in parallel ahead of their commit.
+	maxSpeculativeBatch = 128
+
+	// minSpeculativeBatch is the number of candidate transactions below which
+	// they are executed sequentially instead.
+	minSpeculativeBatch = 4
+)
+
+// errReordered is returned by the speculator if the speculative execution moved
+// another transaction ahead of the one to commit, which is then to be taken
+// again out of the reordered transactions.
+var errReordered = errors.New("speculative candidates reordered")
+
+var (
+	speculativeCommitMeter   = metrics.NewRegisteredMeter("worker/parallel/committed", nil)
+	speculativeFallbackMeter = metrics.NewRegisteredMeter("worker/parallel/fallback", nil)
+	speculativeDiscardMeter  = metrics.NewRegisteredMeter("worker/parallel/discarded", nil)
+)
+
+// parallelBuildProcs returns the number of workers executing the candidate
+// transactions of the blocks in parallel, the ones of block import unless not
+// configured, or zero if blocks are built sequentially.
+func parallelBuildProcs(config *Config, chain *core.BlockChain) int {
+	if !config.ParallelBuild {
+		return 0
+	}
+
+	if procs := chain.GetVMConfig().ParallelSpeculativeProcesses; procs > 0 {
+		return procs
+	}
+
+	return runtime.NumCPU()
+}
+
+// recordsTxDependency returns whether the dependencies between the transactions
+// of the block with the given header are recorded in its extra data.
+func (w *worker) recordsTxDependency(header *types.Header) bool {
+	return w.parallelProcs > 0 && w.chainConfig.Bor != nil && w.chainConfig.Bor.IsParallelUniverse(header.Number)
+}
+
+// txAccesses holds the state read and written by each transaction of a block,
+// in block order, out of which the dependencies between them are computed.
+type txAccesses struct {
+	reads  [][]blockstm.ReadDescriptor
+	writes [][]blockstm.WriteDescriptor
+}
+
+func (a *txAccesses) copy() *txAccesses {
+	if a == nil {
+		return nil
+	}
+
+	return &txAccesses{
+		reads:  append([][]blockstm.ReadDescriptor(nil), a.reads...),
+		writes: append([][]blockstm.WriteDescriptor(nil), a.writes...),
+	}
+}
+
+// add records the state accessed by the next transaction. The written values
+// aren't kept, as they reference the state they were made on.
+func (a *txAccesses) add(reads []blockstm.ReadDescriptor, writes []blockstm.WriteDescriptor) {
+	paths := make([]blockstm.WriteDescriptor, len(writes))
+	for i, write := range writes {
+		paths[i] = blockstm.WriteDescriptor{Path: write.Path, V: write.V}
+	}
+
+	a.reads = append(a.reads, reads)
+	a.writes = append(a.writes, paths)
+}
+
+// dependencies returns for each transaction the earlier ones it read the writes
+// of. It returns nil if a transaction read the balance of one of the given fee
+// recipients, as the fees of all the earlier transactions are credited to them
+// and importers execute such blocks without the dependencies anyway.
+func (a *txAccesses) dependencies(feeRecipients ...common.Address) [][]uint64 {
+	fees := make(map[blockstm.Key]struct{}, len(feeRecipients))
+	for _, recipient := range feeRecipients {
+		fees[blockstm.NewSubpathKey(recipient, state.BalancePath)] = struct{}{}
+	}
+
+	deps := make(map[int]map[int]bool, len(a.reads))
+
+	for i, reads := range a.reads {
+		for _, read := range reads {
+			if _, ok := fees[read.Path]; ok {
+				return nil
+			}
+		}
+
+		deps = blockstm.UpdateDeps(deps, blockstm.TxDep{Index: i, ReadList: reads, FullWriteList: a.writes})
+	}
+
+	txDeps := make([][]uint64, len(a.reads))
+
+	for i := range txDeps {
+		for j := range deps[i] {
+			txDeps[i] = append(txDeps[i], uint64(j))
+		}
+
+		sort.Slice(txDeps[i], func(x, y int) bool { return txDeps[i][x] < txDeps[i][y] })
+	}
+
+	return txDeps
+}
+
+// setTxDependency encodes the dependencies between the transactions of env in
+// the extra data of its header, if they were recorded for all of them.
+func (w *worker) setTxDependency(env *environment) {
+	extra := env.header.Extra
+	if env.accesses == nil || len(extra) < types.ExtraVanityLength+types.ExtraSealLength {
+		return
+	}
+
+	var blockExtraData types.BlockExtraData
+	if err := rlp.DecodeBytes(extra[types.ExtraVanityLength:len(extra)-types.ExtraSealLength], &blockExtraData); err != nil {
+		log.Error("Failed to decode block extra data", "number", env.header.Number, "err", err)
+		return
+	}
+
+	blockExtraData.TxDependency = nil
+
+	if len(env.txs) > 0 && len(env.accesses.reads) == len(env.txs) {
+		burntContract := common.HexToAddress(w.chainConfig.Bor.CalculateBurntContract(env.header.Number.Uint64()))
+		blockExtraData.TxDependency = env.accesses.dependencies(env.coinbase, burntContract)
+
+		reportLongestPath(blockExtraData.TxDependency, env.receipts)
+	}
+
+	blockExtraDataBytes, err := rlp.EncodeToBytes(blockExtraData)
+	if err != nil {
+		log.Error("Failed to encode block extra data", "number", env.header.Number, "err", err)
+		return
+	}
+
+	header := make([]byte, 0, len(extra)-len(extra[types.ExtraVanityLength:len(extra)-types.ExtraSealLength])+len(blockExtraDataBytes))
+	header = append(header, extra[:types.ExtraVanityLength]...)
+	header = append(header, blockExtraDataBytes...)
+	header = append(header, extra[len(extra)-types.ExtraSealLength:]...)
+
+	env.header.Extra = header
+}
+
+// lookaheadTxs wraps the transactions commitTransactions takes the next one of,
+// to also look at the following ones in the order they are taken if they all
+// succeed.
+type lookaheadTxs struct {
+	orderedTxs
+
+	signer  types.Signer
+	ahead   []*types.Transaction        // Transactions shifted out of orderedTxs, taken first
+	dropped map[common.Address]struct{} // Senders popped while their next transactions were in orderedTxs
+}
+
+func newLookaheadTxs(signer types.Signer, txs orderedTxs) *lookaheadTxs {
+	return &lookaheadTxs{
+		orderedTxs: txs,
+		signer:     signer,
+		dropped:    make(map[common.Address]struct{}),
+	}
+}
+
+func (l *lookaheadTxs) Peek() *types.Transaction {
+	if len(l.ahead) > 0 {
+		return l.ahead[0]
+	}
+
+	return l.next()
+}
+
+func (l *lookaheadTxs) Shift() {
+	if len(l.ahead) > 0 {
+		l.ahead = l.ahead[1:]
+		return
+	}
+
+	l.orderedTxs.Shift()
+}
+
+func (l *lookaheadTxs) Pop() {
+	if len(l.ahead) == 0 {
+		l.orderedTxs.Pop()
+		return
+	}
+
+	from, _ := types.Sender(l.signer, l.ahead[0])
+
+	ahead := make([]*types.Transaction, 0, len(l.ahead)-1)
+
+	for _, tx := range l.ahead[1:] {
+		if sender, _ := types.Sender(l.signer, tx); sender != from {
+			ahead = append(ahead, tx)
+		}
+	}
+
+	l.ahead = ahead
+	l.dropped[from] = struct{}{}
+}
+
+// GetTxs returns the number of senders with transactions left, counting the
+// transactions looked ahead at as senders of their own.
+func (l *lookaheadTxs) GetTxs() int {
+	return l.orderedTxs.GetTxs() + len(l.ahead)
+}
+
+// lookahead returns up to n transactions starting with the next one, in the
+// order they are taken if they all succeed.
+func (l *lookaheadTxs) lookahead(n int) []*types.Transaction {
+	for len(l.ahead) < n {
+		tx := l.next()
+		if tx == nil {
+			break
+		}
+
+		l.ahead = append(l.ahead, tx)
+		l.orderedTxs.Shift()
+	}
+
+	if n > len(l.ahead) {
+		n = len(l.ahead)
+	}
+
+	return l.ahead[:n:n]
+}
+
+// next returns the next transaction of orderedTxs, skipping the dropped senders.
+func (l *lookaheadTxs) next() *types.Transaction {
+	for {
+		tx := l.orderedTxs.Peek()
+		if tx == nil {
+			return nil
+		}
+
+		from, _ := types.Sender(l.signer, tx)
+		if _, ok := l.dropped[from]; !ok {
+			return tx
+		}
+
+		l.orderedTxs.Pop()
+	}
+}
+
+// speculativeTask is a candidate transaction executed ahead of its commit, on
+// top of the block state and the writes of the candidates preceding it.
+type speculativeTask struct {
+	tx    *types.Transaction
+	msg   *core.Message
+	index int // Index of the candidate in its batch
+
+	config       *params.ChainConfig
+	vmConfig     vm.Config
+	blockContext vm.BlockContext
+	gasLimit     uint64
+	base         *state.StateDB // State the batch is executed on, never modified
+
+	statedb *state.StateDB // State of the last execution
+	result  *core.ExecutionResult
+	err     error // Consensus error leaving the transaction out of the block
+
+	// sequential is set if the execution read the balance the fees of the
+	// earlier candidates are credited to, which only happens on commit.
+	sequential bool
+}
+
+func (t *speculativeTask) Execute(mvh *blockstm.MVHashMap, incarnation int) (err error) {
+	t.statedb = t.base.Copy()
+	t.statedb.SetTxContext(t.tx.Hash(), t.index)
+	t.statedb.SetMVHashmap(mvh)
+	t.statedb.SetIncarnation(incarnation)
+
+	// The tasks of a batch are executed again once reordered
+	t.result, t.err, t.sequential = nil, nil, false
+
+	evm := vm.NewEVM(t.blockContext, core.NewEVMTxContext(t.msg), t.statedb, t.config, t.vmConfig)
+
+	defer func() {
+		if r := recover(); r != nil {
+			// Executions on inconsistent speculative reads may panic, retry them
+			log.Debug("Recovered from speculative execution failure", "hash", t.tx.Hash(), "err", r)
+
+			err = blockstm.ErrExecAbortError{Dependency: t.statedb.DepTxIndex()}
+		}
+	}()
+
+	t.result, t.err = core.ApplyMessageNoFeeBurnOrTip(evm, *t.msg, new(core.GasPool).AddGas(t.gasLimit), nil)
+
+	if t.statedb.HadInvalidRead() {
+		return blockstm.ErrExecAbortError{Dependency: t.statedb.DepTxIndex(), OriginError: t.err}
+	}
+
+	// Failing transactions are kept as such, and validated like the others as
+	// they may only fail on the speculative state
+	if t.err != nil {
+		return nil
+	}
+
+	reads := t.statedb.MVReadMap()
+	_, coinbase := reads[blockstm.NewSubpathKey(t.blockContext.Coinbase, state.BalancePath)]
+	_, burnt := reads[blockstm.NewSubpathKey(t.result.BurntContractAddress, state.BalancePath)]
+	t.sequential = coinbase || burnt
+
+	t.statedb.Finalise(t.config.IsEIP158(t.blockContext.BlockNumber))
+
+	return nil
+}
+
+func (t *speculativeTask) MVReadList() []blockstm.ReadDescriptor {
+	return t.statedb.MVReadList()
+}
+
+// MVWriteList returns the writes of the transaction, none if it failed as it
+// is left out of the block.
+func (t *speculativeTask) MVWriteList() []blockstm.WriteDescriptor {
+	if t.err != nil {
+		return nil
+	}
+
+	return t.statedb.MVWriteList()
+}
+
+func (t *speculativeTask) MVFullWriteList() []blockstm.WriteDescriptor {
+	if t.err != nil {
+		return nil
+	}
+
+	return t.statedb.MVFullWriteList()
+}
+
+func (t *speculativeTask) Hash() common.Hash {
+	return t.tx.Hash()
+}
+
+func (t *speculativeTask) Sender() common.Address {
+	return t.msg.From
+}
+
+// Settle does nothing, the results are committed by the worker as the block
+// reaches their transactions.
+func (t *speculativeTask) Settle() {}
+
+func (t *speculativeTask) Dependencies() []int {
+	return nil
+}
+
+// speculator commits the candidate transactions out of their speculative
+// execution in parallel. Candidates are executed in batches, in the order
+// commitTransactions takes them if they all succeed, and their results are
+// committed as long as the block follows that order. It falls back to the
+// sequential execution otherwise.
+type speculator struct {
+	w     *worker
+	txs   *lookaheadTxs
+	batch int // Number of candidates of the next batch
+
+	results []*speculativeTask // Results of the remaining candidates of the last batch
+	state   *state.StateDB     // State the results apply to
+	tcount  int                // Transaction count of the block the next result applies at
+}
+
+func newSpeculator(w *worker, env *environment, txs orderedTxs) *speculator {
+	return &speculator{
+		w:     w,
+		txs:   newLookaheadTxs(env.signer, txs),
+		batch: maxSpeculativeBatch,
+	}
+}
+
+// commit commits tx, the next transaction of the speculator, to env as
+// worker.commitTransaction does.
+func (s *speculator) commit(env *environment, tx *types.Transaction, interruptCtx context.Context) ([]*types.Log, error) {
+	// The failed candidates wrote nothing the following ones could depend on
+	for len(s.results) > 0 && s.results[0].tx.Hash() != tx.Hash() && s.results[0].err != nil {
+		s.results = s.results[1:]
+	}
+
+	if len(s.results) == 0 || s.results[0].tx.Hash() != tx.Hash() || s.state != env.state || s.tcount != env.tcount {
+		speculativeDiscardMeter.Mark(int64(len(s.results)))
+
+		s.results = s.execute(env, interruptCtx)
+
+		if len(s.results) > 0 && s.results[0].tx.Hash() != tx.Hash() {
+			return nil, errReordered
+		}
+	}
+
+	if len(s.results) == 0 {
+		return s.w.commitTransaction(env, tx, interruptCtx)
+	}
+
+	task := s.results[0]
+	s.results = s.results[1:]
+
+	if task.err != nil {
+		return nil, task.err
+	}
+
+	if task.sequential {
+		speculativeFallbackMeter.Mark(1)
+		speculativeDiscardMeter.Mark(int64(len(s.results)))
+
+		s.results = nil
+
+		if s.batch /= 2; s.batch < minSpeculativeBatch {
+			s.batch = minSpeculativeBatch
+		}
+
+		return s.w.commitTransaction(env, tx, interruptCtx)
+	}
+
+	speculativeCommitMeter.Mark(1)
+
+	if s.batch < maxSpeculativeBatch {
+		s.batch++
+	}
+
+	s.tcount++
+
+	return s.w.commitSpeculative(env, task), nil
+}
+
+// execute executes the next candidates in parallel on top of env, and returns
+// their results in commit order. It returns nil if the candidates are better
+// executed sequentially.
+func (s *speculator) execute(env *environment, interruptCtx context.Context) []*speculativeTask {
+	candidates := s.txs.lookahead(s.batch)
+
+	gas := env.gasPool.Gas()
+
+	for i, tx := range candidates {
+		// Out of gas candidates would fail on commit only
+		if tx.Gas() > gas {
+			candidates = candidates[:i]
+			break
+		}
+
+		gas -= tx.Gas()
+
+		// The bundles merged in before a candidate change the state it runs on
+		if i > 0 && len(env.bundles) > 0 {
+			if tip, _ := tx.EffectiveGasTip(env.header.BaseFee); env.bundles[0].price.Cmp(tip) >= 0 {
+				candidates = candidates[:i]
+				break
+			}
+		}
+	}
+
+	if len(candidates) < minSpeculativeBatch {
+		return nil
+	}
+
+	var (
+		signer       = types.MakeSigner(s.w.chainConfig, env.header.Number)
+		blockContext = core.NewEVMBlockContext(env.header, s.w.chain, &env.coinbase)
+		base         = env.state.Copy()
+		tasks        = make([]blockstm.ExecTask, 0, len(candidates))
+		results      = make([]*speculativeTask, 0, len(candidates))
+	)
+
+	base.StopPrefetcher()
+
+	for i, tx := range candidates {
+		msg, err := core.TransactionToMessage(tx, signer, env.header.BaseFee)
+		if err != nil {
+			break
+		}
+
+		task := &speculativeTask{
+			tx:           tx,
+			msg:          msg,
+			index:        i,
+			config:       s.w.chainConfig,
+			vmConfig:     *s.w.chain.GetVMConfig(),
+			blockContext: blockContext,
+			gasLimit:     env.header.GasLimit,
+			base:         base,
+		}
+
+		tasks = append(tasks, task)
+		results = append(results, task)
+	}
+
+	if len(tasks) < minSpeculativeBatch {
+		return nil
+	}
+
+	if _, err := blockstm.ExecuteParallel(tasks, false, false, s.w.parallelProcs, interruptCtx); err != nil {
+		log.Debug("Speculative execution failed", "number", env.header.Number, "txs", len(tasks), "err", err)
+		return nil
+	}
+
+	// Reorder the candidates out of the state they accessed if configured, and
+	// execute them again in their new order
+	if s.w.config.ParallelOrder {
+		if order := conflictOrder(results, env.header.BaseFee); !isIdentity(order) {
+			speculativeReorderMeter.Mark(1)
+
+			s.txs.reorder(order)
+
+			reordered := make([]*speculativeTask, len(order))
+
+			for i, j := range order {
+				reordered[i] = results[j]
+				reordered[i].index = i
+				tasks[i] = reordered[i]
+			}
+
+			results = reordered
+
+			if _, err := blockstm.ExecuteParallel(tasks, false, false, s.w.parallelProcs, interruptCtx); err != nil {
+				log.Debug("Speculative execution failed", "number", env.header.Number, "txs", len(tasks), "err", err)
+				return nil
+			}
+		}
+	}
+
+	s.state, s.tcount = env.state, env.tcount
+
+	return results
+}
+
+// commitSpeculative commits the result of the speculative execution of a
+// transaction to env, crediting its fees as core.ApplyTransaction does.
+func (w *worker) commitSpeculative(env *environment, task *speculativeTask) []*types.Log {
+	var (
+		statedb = env.state
+		number  = env.header.Number
+		result  = task.result
+		hash    = task.tx.Hash()
+	)
+
+	coinbaseBalance := statedb.GetBalance(env.coinbase)
+
+	statedb.ApplyMVWriteSet(task.statedb.MVFullWriteList())
+
+	for _, l := range task.statedb.GetLogs(hash, number.Uint64(), common.Hash{}) {
+		statedb.AddLog(l)
+	}
+
+	if w.chainConfig.IsLondon(number) {
+		statedb.AddBalance(result.BurntContractAddress, result.FeeBurnt)
+	}
+
+	statedb.AddBalance(env.coinbase, result.FeeTipped)
+	output1 := new(big.Int).SetBytes(result.SenderInitBalance.Bytes())
+	output2 := new(big.Int).SetBytes(coinbaseBalance.Bytes())
+
+	// Deprecating transfer log and will be removed in future fork. PLEASE DO NOT USE this transfer log going forward. Parameters won't get updated as expected going forward with EIP1559
+	// add transfer log
+	core.AddFeeTransferLog(
+		statedb,
+
+		task.msg.From,
+		env.coinbase,
+
+		result.FeeTipped,
+		result.SenderInitBalance,
+		coinbaseBalance,
+		output1.Sub(output1, result.FeeTipped),
+		output2.Add(output2, result.FeeTipped),
+	)
+
+	for k, v := range task.statedb.Preimages() {
+		statedb.AddPreimage(k, v)
+	}
+
+	var root []byte
+
+	if w.chainConfig.IsByzantium(number) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(w.chainConfig.IsEIP158(number)).Bytes()
+	}
+
+	// The candidates are within the gas left in the block
+	_ = env.gasPool.SubGas(result.UsedGas)
+	env.header.GasUsed += result.UsedGas
+
+	receipt := &types.Receipt{Type: task.tx.Type(), PostState: root, CumulativeGasUsed: env.header.GasUsed}
+	if result.Failed() {
+		receipt.Status = types.ReceiptStatusFailed
+	} else {
+		receipt.Status = types.ReceiptStatusSuccessful
+	}
+
+	receipt.TxHash = hash
+	receipt.GasUsed = result.UsedGas
+
+	if task.msg.To == nil {
+		receipt.ContractAddress = crypto.CreateAddress(task.msg.From, task.tx.Nonce())
+	}
+
+	receipt.Logs = statedb.GetLogs(hash, number.Uint64(), env.header.Hash())
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	receipt.BlockHash = env.header.Hash()
+	receipt.BlockNumber = number
+	receipt.TransactionIndex = uint(statedb.TxIndex())
+
+	env.txs = append(env.txs, task.tx)
+	env.receipts = append(env.receipts, receipt)
+	env.balances = append(env.balances, env.state.GetBalance(env.coinbase))
+
+	if env.accesses != nil {
+		env.accesses.add(task.statedb.MVReadList(), task.statedb.MVFullWriteList())
+	}
+
+	return receipt.Logs
+}
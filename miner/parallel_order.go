@@ -0,0 +1,170 @@
+package miner
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	speculativeReorderMeter = metrics.NewRegisteredMeter("worker/parallel/reordered", nil)
+
+	longestPathTxsHistogram = metrics.NewRegisteredHistogram("worker/parallel/longestpath/txs", nil, metrics.NewExpDecaySample(1028, 0.015))
+	longestPathGasHistogram = metrics.NewRegisteredHistogram("worker/parallel/longestpath/gas", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// conflictOrder returns the order of the speculatively executed candidates of
+// a batch which shortens the chains of dependent transactions of the block.
+//
+// The candidates tipping the same are reordered among themselves, keeping the
+// ones of each sender in nonce order. Each transaction is assumed to start once
+// the earlier ones it reads the writes of end, and to last as much as the gas it
+// uses. The candidate taken next is the one ending first, once the remaining
+// ones reading its writes are accounted for, as they will then have to wait for
+// it. The candidates executed sequentially stay in place.
+func conflictOrder(tasks []*speculativeTask, baseFee *big.Int) []int {
+	var (
+		gas   = make([]uint64, len(tasks))
+		tips  = make([]*big.Int, len(tasks))
+		reads = make([][]bool, len(tasks)) // Whether each candidate reads the writes of each other one
+	)
+
+	for i, task := range tasks {
+		writes := make(map[blockstm.Key]struct{})
+		for _, write := range task.MVFullWriteList() {
+			writes[write.Path] = struct{}{}
+		}
+
+		for j := range tasks {
+			if reads[j] == nil {
+				reads[j] = make([]bool, len(tasks))
+			}
+
+			if i == j {
+				continue
+			}
+
+			for _, read := range tasks[j].MVReadList() {
+				if _, ok := writes[read.Path]; ok {
+					reads[j][i] = true
+					break
+				}
+			}
+		}
+
+		gas[i] = task.tx.Gas()
+		if task.result != nil {
+			gas[i] = task.result.UsedGas
+		}
+
+		tips[i], _ = task.tx.EffectiveGasTip(baseFee)
+	}
+
+	var (
+		order = make([]int, 0, len(tasks))
+		end   = make([]uint64, len(tasks)) // End of the execution of the ordered candidates
+	)
+
+	for start := 0; start < len(tasks); {
+		// Candidates of the same tip, up to the next sequential one
+		stop := start + 1
+
+		if !tasks[start].sequential {
+			for stop < len(tasks) && !tasks[stop].sequential && tips[stop].Cmp(tips[start]) == 0 {
+				stop++
+			}
+		}
+
+		placed := make(map[int]bool, stop-start)
+
+		for len(placed) < stop-start {
+			var (
+				best      = -1
+				bestScore uint64
+				senders   = make(map[common.Address]struct{})
+			)
+
+			for i := start; i < stop; i++ {
+				if placed[i] {
+					continue
+				}
+
+				// Only the first candidate of each sender left can be taken
+				if _, ok := senders[tasks[i].msg.From]; ok {
+					continue
+				}
+
+				senders[tasks[i].msg.From] = struct{}{}
+
+				var txStart, waiting uint64
+
+				for _, j := range order {
+					if reads[i][j] && end[j] > txStart {
+						txStart = end[j]
+					}
+				}
+
+				for j := start; j < stop; j++ {
+					if j != i && !placed[j] && reads[j][i] && gas[j] > waiting {
+						waiting = gas[j]
+					}
+				}
+
+				if score := txStart + gas[i] + waiting; best == -1 || score < bestScore {
+					best, bestScore = i, score
+					end[i] = txStart + gas[i]
+				}
+			}
+
+			placed[best] = true
+			order = append(order, best)
+		}
+
+		start = stop
+	}
+
+	return order
+}
+
+// isIdentity returns whether the order keeps all the candidates in place.
+func isIdentity(order []int) bool {
+	for i, j := range order {
+		if i != j {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reorder moves the transactions looked ahead at in the given order, of their
+// current indexes.
+func (l *lookaheadTxs) reorder(order []int) {
+	ahead := make([]*types.Transaction, len(order))
+	for i, j := range order {
+		ahead[i] = l.ahead[j]
+	}
+
+	copy(l.ahead, ahead)
+}
+
+// reportLongestPath accounts for the longest chain of dependent transactions of
+// a block, weighting them with the gas they used.
+func reportLongestPath(deps [][]uint64, receipts types.Receipts) {
+	if len(deps) == 0 || len(deps) != len(receipts) {
+		return
+	}
+
+	stats := make(map[int]blockstm.ExecutionStat, len(receipts))
+	for i, receipt := range receipts {
+		stats[i] = blockstm.ExecutionStat{TxIdx: i, End: receipt.GasUsed}
+	}
+
+	path, weight := blockstm.NewDAG(deps).LongestPath(stats)
+
+	longestPathTxsHistogram.Update(int64(len(path)))
+	longestPathGasHistogram.Update(int64(weight))
+}
@@ -0,0 +1,385 @@
+package miner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestParallelBuild(t *testing.T) {
+	t.Parallel()
+
+	// Record the dependencies from the first block
+	borConfig := *params.BorUnittestChainConfig.Bor
+	borConfig.ParallelUniverseBlock = big.NewInt(1)
+
+	chainConfig := *params.BorUnittestChainConfig
+	chainConfig.Bor = &borConfig
+
+	w, cfg := newBorTestWorker(t, &chainConfig)
+	w.parallelProcs = 4
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: TestBankAddress})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	if env.accesses == nil {
+		t.Fatal("transaction accesses not recorded")
+	}
+
+	// Senders transferring to recipients of their own, except the last two
+	// sharing theirs, each tipping more than the next one
+	const senders, nonces = 8, 3
+
+	var (
+		signer  = types.LatestSigner(cfg)
+		pending = make(map[common.Address]types.Transactions)
+		shared  = common.HexToAddress("0x5ba7ed")
+	)
+
+	for i := 0; i < senders; i++ {
+		key, _ := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("sender-%d", i))))
+		from := crypto.PubkeyToAddress(key.PublicKey)
+
+		env.state.AddBalance(from, big.NewInt(params.Ether))
+
+		to := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		if i >= senders-2 {
+			to = shared
+		}
+
+		for nonce := uint64(0); nonce < nonces; nonce++ {
+			tip := big.NewInt(int64(senders-i) * params.GWei)
+
+			pending[from] = append(pending[from], types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   cfg.ChainID,
+				Nonce:     nonce,
+				GasTipCap: tip,
+				GasFeeCap: new(big.Int).Add(tip, big.NewInt(100*params.InitialBaseFee)),
+				Gas:       params.TxGas,
+				To:        &to,
+				Value:     big.NewInt(1),
+			}))
+		}
+	}
+
+	build := func(procs int) *environment {
+		w.parallelProcs = procs
+
+		buildEnv := env.copy()
+
+		txs := make(map[common.Address]types.Transactions, len(pending))
+		for from, list := range pending {
+			txs[from] = list
+		}
+
+		if err := w.commitTransactions(buildEnv, types.NewTransactionsByPriceAndNonce(signer, txs, math.FromBig(buildEnv.header.BaseFee)), nil, context.Background()); err != nil {
+			t.Fatalf("failed to commit transactions with %d processes: %v", procs, err)
+		}
+
+		w.setTxDependency(buildEnv)
+
+		return buildEnv
+	}
+
+	sequential, parallel := build(0), build(4)
+	defer sequential.discard()
+	defer parallel.discard()
+
+	// Both builds must result in the same block
+	if len(parallel.txs) != senders*nonces || len(sequential.txs) != len(parallel.txs) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(parallel.txs), len(sequential.txs))
+	}
+
+	for i, tx := range parallel.txs {
+		if tx.Hash() != sequential.txs[i].Hash() {
+			t.Errorf("transaction %d mismatch: have %s, want %s", i, tx.Hash(), sequential.txs[i].Hash())
+		}
+
+		have, want := parallel.receipts[i], sequential.receipts[i]
+		if have.Status != want.Status || have.CumulativeGasUsed != want.CumulativeGasUsed || have.TransactionIndex != want.TransactionIndex || len(have.Logs) != len(want.Logs) || have.Bloom != want.Bloom {
+			t.Errorf("receipt %d mismatch: have %+v, want %+v", i, have, want)
+		}
+	}
+
+	if have, want := parallel.state.IntermediateRoot(true), sequential.state.IntermediateRoot(true); have != want {
+		t.Errorf("state root mismatch: have %x, want %x", have, want)
+	}
+
+	if !bytes.Equal(parallel.header.Extra, sequential.header.Extra) {
+		t.Errorf("extra data mismatch: have %x, want %x", parallel.header.Extra, sequential.header.Extra)
+	}
+
+	// The transactions depend on the previous one of their sender, and the
+	// ones to the shared recipient on all the earlier ones
+	var blockExtraData types.BlockExtraData
+	if err := rlp.DecodeBytes(parallel.header.Extra[types.ExtraVanityLength:len(parallel.header.Extra)-types.ExtraSealLength], &blockExtraData); err != nil {
+		t.Fatalf("failed to decode block extra data: %v", err)
+	}
+
+	if len(blockExtraData.TxDependency) != len(parallel.txs) {
+		t.Fatalf("dependency count mismatch: have %d, want %d", len(blockExtraData.TxDependency), len(parallel.txs))
+	}
+
+	sharedTx := -1
+
+	for i, tx := range parallel.txs {
+		var want []uint64
+
+		switch {
+		case *tx.To() == shared:
+			if sharedTx >= 0 {
+				want = []uint64{uint64(sharedTx)}
+			}
+
+			sharedTx = i
+		case tx.Nonce() > 0:
+			want = []uint64{uint64(i - 1)}
+		}
+
+		if have := blockExtraData.TxDependency[i]; fmt.Sprint(have) != fmt.Sprint(want) {
+			t.Errorf("transaction %d dependencies mismatch: have %v, want %v", i, have, want)
+		}
+	}
+}
+
+func TestTxDependencyFeeRecipient(t *testing.T) {
+	t.Parallel()
+
+	var (
+		coinbase = common.HexToAddress("0xc014ba5e")
+		accesses = new(txAccesses)
+	)
+
+	// A transaction reading the balance credited with the fees of the earlier
+	// ones leaves the block without dependencies
+	accesses.add(nil, nil)
+
+	if deps := accesses.dependencies(coinbase); len(deps) != 1 || len(deps[0]) != 0 {
+		t.Fatalf("dependencies mismatch: have %v, want [[]]", deps)
+	}
+
+	accesses.add([]blockstm.ReadDescriptor{{Path: blockstm.NewSubpathKey(coinbase, state.BalancePath)}}, nil)
+
+	if deps := accesses.dependencies(coinbase); deps != nil {
+		t.Errorf("dependencies mismatch: have %v, want nil", deps)
+	}
+
+	if deps := accesses.dependencies(); len(deps) != 2 {
+		t.Errorf("dependencies mismatch: have %v, want 2 transactions", deps)
+	}
+}
+
+func TestParallelBuildOrder(t *testing.T) {
+	t.Parallel()
+
+	borConfig := *params.BorUnittestChainConfig.Bor
+	borConfig.ParallelUniverseBlock = big.NewInt(1)
+
+	chainConfig := *params.BorUnittestChainConfig
+	chainConfig.Bor = &borConfig
+
+	w, cfg := newBorTestWorker(t, &chainConfig)
+	w.parallelProcs = 4
+	w.config.ParallelOrder = true
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: TestBankAddress})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	// Senders tipping the same, the first one transferring to an account whose
+	// balance the others log through contracts of their own
+	const senders = 5
+
+	var (
+		signer  = types.LatestSigner(cfg)
+		pending = make(map[common.Address]types.Transactions)
+		account = common.HexToAddress("0xba1a9ce")
+		writer  common.Address
+	)
+
+	for i := 0; i < senders; i++ {
+		key, _ := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("sender-%d", i))))
+		from := crypto.PubkeyToAddress(key.PublicKey)
+
+		env.state.AddBalance(from, big.NewInt(params.Ether))
+
+		to, value := account, big.NewInt(1)
+
+		if i == 0 {
+			writer = from
+		} else {
+			// PUSH20 account BALANCE PUSH1 0 MSTORE PUSH1 32 PUSH1 0 LOG0 STOP
+			to, value = common.BigToAddress(big.NewInt(int64(0xc0de+i))), new(big.Int)
+			env.state.SetCode(to, append(append([]byte{0x73}, account.Bytes()...), 0x31, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xa0, 0x00))
+		}
+
+		pending[from] = types.Transactions{types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   cfg.ChainID,
+			GasTipCap: big.NewInt(params.GWei),
+			GasFeeCap: big.NewInt(100 * params.InitialBaseFee),
+			Gas:       100000,
+			To:        &to,
+			Value:     value,
+		})}
+	}
+
+	sequential := env.copy()
+	defer sequential.discard()
+
+	sequential.gasPool = new(core.GasPool).AddGas(sequential.header.GasLimit)
+
+	if err := w.commitTransactions(env, types.NewTransactionsByPriceAndNonce(signer, pending, math.FromBig(env.header.BaseFee)), nil, context.Background()); err != nil {
+		t.Fatalf("failed to commit transactions: %v", err)
+	}
+
+	w.setTxDependency(env)
+
+	if len(env.txs) != senders {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(env.txs), senders)
+	}
+
+	// The readers are moved ahead of the transfer, leaving them independent
+	if from, _ := types.Sender(signer, env.txs[senders-1]); from != writer {
+		t.Errorf("last transaction sender mismatch: have %s, want %s", from, writer)
+	}
+
+	// The readers read the transfer in the first execution of the batch, and no
+	// longer once reordered, so they must log the balance before it, ahead of
+	// the fee transfer log
+	for i, receipt := range env.receipts {
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("transaction %d failed", i)
+		}
+
+		if i < senders-1 && (len(receipt.Logs) != 2 || *env.txs[i].To() != receipt.Logs[0].Address || new(big.Int).SetBytes(receipt.Logs[0].Data).Sign() != 0) {
+			t.Errorf("transaction %d logs mismatch: have %v, want a zero balance", i, receipt.Logs)
+		}
+	}
+
+	// The receipts must match the ones of the sequential execution in the new order
+	for _, tx := range env.txs {
+		sequential.state.SetTxContext(tx.Hash(), sequential.tcount)
+
+		if _, err := w.commitTransaction(sequential, tx, context.Background()); err != nil {
+			t.Fatalf("failed to commit transaction %s sequentially: %v", tx.Hash(), err)
+		}
+
+		sequential.tcount++
+	}
+
+	for i, have := range env.receipts {
+		want := sequential.receipts[i]
+
+		if have.Status != want.Status || have.GasUsed != want.GasUsed || have.CumulativeGasUsed != want.CumulativeGasUsed || have.Bloom != want.Bloom || len(have.Logs) != len(want.Logs) {
+			t.Errorf("receipt %d mismatch: have %+v, want %+v", i, have, want)
+			continue
+		}
+
+		for j, log := range have.Logs {
+			if !bytes.Equal(log.Data, want.Logs[j].Data) {
+				t.Errorf("receipt %d log %d mismatch: have %x, want %x", i, j, log.Data, want.Logs[j].Data)
+			}
+		}
+	}
+
+	if have, want := env.state.IntermediateRoot(true), sequential.state.IntermediateRoot(true); have != want {
+		t.Errorf("state root mismatch: have %x, want %x", have, want)
+	}
+
+	var blockExtraData types.BlockExtraData
+	if err := rlp.DecodeBytes(env.header.Extra[types.ExtraVanityLength:len(env.header.Extra)-types.ExtraSealLength], &blockExtraData); err != nil {
+		t.Fatalf("failed to decode block extra data: %v", err)
+	}
+
+	for i, deps := range blockExtraData.TxDependency {
+		if len(deps) != 0 {
+			t.Errorf("transaction %d dependencies mismatch: have %v, want none", i, deps)
+		}
+	}
+
+	if balance := env.state.GetBalance(account); balance.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("balance mismatch: have %v, want 1", balance)
+	}
+}
+
+func TestConflictOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _ = crypto.GenerateKey()
+		slot   = blockstm.NewStateKey(common.Address{0x0c}, common.Hash{})
+		tip    = big.NewInt(params.GWei)
+		higher = big.NewInt(2 * params.GWei)
+	)
+
+	newTask := func(nonce uint64, tip *big.Int, from common.Address, gas uint64, reads []blockstm.ReadDescriptor, writes []blockstm.WriteDescriptor) *speculativeTask {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.AddEmptyMVHashMap()
+
+		for _, read := range reads {
+			statedb.GetState(read.Path.GetAddress(), read.Path.GetStateKey())
+		}
+
+		for _, write := range writes {
+			statedb.SetState(write.Path.GetAddress(), write.Path.GetStateKey(), common.Hash{0x01})
+		}
+
+		tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{Nonce: nonce, GasTipCap: tip, GasFeeCap: tip, Gas: gas})
+
+		return &speculativeTask{tx: tx, msg: &core.Message{From: from}, statedb: statedb, result: &core.ExecutionResult{UsedGas: gas}}
+	}
+
+	var (
+		a = common.Address{0x0a}
+		b = common.Address{0x0b}
+		c = common.Address{0x0c}
+		d = common.Address{0x0d}
+	)
+
+	for i, tt := range []struct {
+		tasks []*speculativeTask
+		want  []int
+	}{
+		// Readers move ahead of the writer of the same tip
+		{[]*speculativeTask{
+			newTask(0, tip, a, 50000, nil, []blockstm.WriteDescriptor{{Path: slot}}),
+			newTask(0, tip, b, 30000, []blockstm.ReadDescriptor{{Path: slot}}, nil),
+			newTask(0, tip, c, 30000, []blockstm.ReadDescriptor{{Path: slot}}, nil),
+		}, []int{1, 2, 0}},
+		// But not ahead of the writer tipping more
+		{[]*speculativeTask{
+			newTask(0, higher, a, 50000, nil, []blockstm.WriteDescriptor{{Path: slot}}),
+			newTask(0, tip, b, 30000, []blockstm.ReadDescriptor{{Path: slot}}, nil),
+			newTask(0, tip, c, 30000, []blockstm.ReadDescriptor{{Path: slot}}, nil),
+		}, []int{0, 1, 2}},
+		// Nor ahead of the earlier transactions of their sender
+		{[]*speculativeTask{
+			newTask(0, tip, a, 50000, nil, []blockstm.WriteDescriptor{{Path: slot}}),
+			newTask(1, tip, a, 30000, []blockstm.ReadDescriptor{{Path: slot}}, nil),
+			newTask(0, tip, d, 30000, []blockstm.ReadDescriptor{{Path: slot}}, nil),
+		}, []int{2, 0, 1}},
+	} {
+		if have := conflictOrder(tt.tasks, nil); fmt.Sprint(have) != fmt.Sprint(tt.want) {
+			t.Errorf("test %d: order mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
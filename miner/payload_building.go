@@ -19,13 +19,17 @@ package miner
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -59,6 +63,56 @@ func (args *BuildPayloadArgs) Id() engine.PayloadID {
 	return out
 }
 
+// BorPayloadArgs contains the provided parameters for building a Bor payload.
+// Unlike for post-merge payloads, the timestamp and the consensus fields of the
+// header are set by the Bor engine for the local signer, the same way as for
+// the blocks it seals itself.
+type BorPayloadArgs struct {
+	Parent       common.Hash    // The parent block to build payload on top
+	FeeRecipient common.Address // The provided recipient address for collecting transaction fee, must be the local signer
+}
+
+// Id computes an 8-byte identifier by hashing the components of the payload
+// arguments. It never collides with the identifier of post-merge arguments.
+func (args *BorPayloadArgs) Id() engine.PayloadID {
+	hasher := sha256.New()
+	hasher.Write([]byte("bor"))
+	hasher.Write(args.Parent[:])
+	hasher.Write(args.FeeRecipient[:])
+
+	var out engine.PayloadID
+
+	copy(out[:], hasher.Sum(nil)[:8])
+
+	return out
+}
+
+// PayloadUpdate announces an improved version of a block being built, either
+// a payload requested through the engine API or the local sealing work.
+type PayloadUpdate struct {
+	ID         engine.PayloadID // Identifier of the payload, zero for the local sealing work
+	Number     uint64
+	ParentHash common.Hash
+	BlockHash  common.Hash // Hash of the block before sealing
+	Fees       *big.Int    // Value of the block to the fee recipient
+	GasUsed    uint64
+	TxCount    int
+	Elapsed    time.Duration // Time spent building this version
+}
+
+func newPayloadUpdate(id engine.PayloadID, block *types.Block, fees *big.Int, elapsed time.Duration) *PayloadUpdate {
+	return &PayloadUpdate{
+		ID:         id,
+		Number:     block.NumberU64(),
+		ParentHash: block.ParentHash(),
+		BlockHash:  block.Hash(),
+		Fees:       fees,
+		GasUsed:    block.GasUsed(),
+		TxCount:    len(block.Transactions()),
+		Elapsed:    elapsed,
+	}
+}
+
 // Payload wraps the built payload(block waiting for sealing). According to the
 // engine-api specification, EL should build the initial version of the payload
 // which has an empty transaction set and then keep update it in order to maximize
@@ -72,6 +126,7 @@ type Payload struct {
 	stop     chan struct{}
 	lock     sync.Mutex
 	cond     *sync.Cond
+	feed     *event.Feed // Feed announcing every improved full block, if any
 }
 
 // newPayload initializes the payload object.
@@ -87,7 +142,9 @@ func newPayload(empty *types.Block, id engine.PayloadID) *Payload {
 	return payload
 }
 
-// update updates the full-block with latest built version.
+// update updates the full-block with latest built version. The fees are the
+// value of the block to the fee recipient, which is the proposer payment if
+// the block was built with payouts enabled.
 func (payload *Payload) update(block *types.Block, fees *big.Int, elapsed time.Duration) {
 	payload.lock.Lock()
 	defer payload.lock.Unlock()
@@ -108,6 +165,10 @@ func (payload *Payload) update(block *types.Block, fees *big.Int, elapsed time.D
 		log.Info("Updated payload", "id", payload.id, "number", block.NumberU64(), "hash", block.Hash(),
 			"txs", len(block.Transactions()), "gas", block.GasUsed(), "fees", feesInEther,
 			"root", block.Root(), "elapsed", common.PrettyDuration(elapsed))
+
+		if payload.feed != nil {
+			payload.feed.Send(newPayloadUpdate(payload.id, block, fees, elapsed))
+		}
 	}
 
 	payload.cond.Broadcast() // fire signal for notifying full block
@@ -116,6 +177,18 @@ func (payload *Payload) update(block *types.Block, fees *big.Int, elapsed time.D
 // Resolve returns the latest built payload and also terminates the background
 // thread for updating payload. It's safe to be called multiple times.
 func (payload *Payload) Resolve() *engine.ExecutionPayloadEnvelope {
+	return engine.BlockToExecutableData(payload.resolve())
+}
+
+// ResolveBor is identical to Resolve, but returns the payload along with the
+// Bor specific header fields.
+func (payload *Payload) ResolveBor() *engine.BorExecutionPayloadEnvelope {
+	return engine.BlockToBorExecutionPayload(payload.resolve())
+}
+
+// resolve terminates the background thread updating the payload and returns
+// the latest built block along with its fees.
+func (payload *Payload) resolve() (*types.Block, *big.Int) {
 	payload.lock.Lock()
 	defer payload.lock.Unlock()
 
@@ -126,10 +199,10 @@ func (payload *Payload) Resolve() *engine.ExecutionPayloadEnvelope {
 	}
 
 	if payload.full != nil {
-		return engine.BlockToExecutableData(payload.full, payload.fullFees)
+		return payload.full, payload.fullFees
 	}
 
-	return engine.BlockToExecutableData(payload.empty, big.NewInt(0))
+	return payload.empty, big.NewInt(0)
 }
 
 // ResolveEmpty is basically identical to Resolve, but it expects empty block only.
@@ -161,15 +234,46 @@ func (payload *Payload) ResolveFull() *engine.ExecutionPayloadEnvelope {
 
 // buildPayload builds the payload according to the provided parameters.
 func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
+	return w.startPayload(args.Id(), func(noTxs bool) (*types.Block, *big.Int, error) {
+		return w.getSealingBlock(args.Parent, args.Timestamp, args.FeeRecipient, args.Random, args.Withdrawals, noTxs)
+	})
+}
+
+// buildBorPayload builds a Bor payload according to the provided parameters.
+// As the fees are credited to the block signer when the block is imported, the
+// fee recipient must be the local signer.
+func (w *worker) buildBorPayload(args *BorPayloadArgs) (*Payload, error) {
+	if _, ok := w.engine.(*bor.Bor); !ok {
+		return nil, errors.New("consensus engine is not bor")
+	}
+
+	if signer := w.etherbase(); args.FeeRecipient != signer {
+		return nil, fmt.Errorf("fee recipient %s is not the block signer %s", args.FeeRecipient, signer)
+	}
+
+	return w.startPayload(args.Id(), func(noTxs bool) (*types.Block, *big.Int, error) {
+		return w.requestWork(&generateParams{
+			parentHash: args.Parent,
+			coinbase:   args.FeeRecipient,
+			noUncle:    true,
+			noTxs:      noTxs,
+		})
+	})
+}
+
+// startPayload builds the initial, empty version of a payload and keeps
+// updating it with full blocks in the background until it's resolved.
+func (w *worker) startPayload(id engine.PayloadID, build func(noTxs bool) (*types.Block, *big.Int, error)) (*Payload, error) {
 	// Build the initial version with no transaction included. It should be fast
 	// enough to run. The empty payload can at least make sure there is something
 	// to deliver for not missing slot.
-	empty, _, err := w.getSealingBlock(args.Parent, args.Timestamp, args.FeeRecipient, args.Random, args.Withdrawals, true)
+	empty, _, err := build(true)
 	if err != nil {
 		return nil, err
 	}
 	// Construct a payload object for return.
-	payload := newPayload(empty, args.Id())
+	payload := newPayload(empty, id)
+	payload.feed = &w.payloadFeed
 
 	// Spin up a routine for updating the payload in background. This strategy
 	// can maximum the revenue for including transactions with highest fee.
@@ -188,7 +292,7 @@ func (w *worker) buildPayload(args *BuildPayloadArgs) (*Payload, error) {
 			select {
 			case <-timer.C:
 				start := time.Now()
-				block, fees, err := w.getSealingBlock(args.Parent, args.Timestamp, args.FeeRecipient, args.Random, args.Withdrawals, false)
+				block, fees, err := build(false)
 
 				if err == nil {
 					payload.update(block, fees, time.Since(start))
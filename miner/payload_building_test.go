@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 	"time"
@@ -91,6 +92,129 @@ func TestBuildPayload(t *testing.T) {
 	}
 }
 
+func TestPayloadUpdates(t *testing.T) {
+	t.Parallel()
+
+	w, b, _ := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0, false, 0, 0)
+	defer w.close()
+
+	updates := make(chan *PayloadUpdate, 16)
+	sub := w.payloadFeed.Subscribe(updates)
+
+	defer sub.Unsubscribe()
+
+	// next waits for the first update of the given payload including the
+	// pending transactions.
+	next := func(id engine.PayloadID) *PayloadUpdate {
+		t.Helper()
+
+		timeout := time.NewTimer(3 * time.Second)
+		defer timeout.Stop()
+
+		for {
+			select {
+			case update := <-updates:
+				if update.ID == id && update.TxCount == len(pendingTxs) {
+					return update
+				}
+			case <-timeout.C:
+				t.Fatalf("no update for payload %s", id)
+			}
+		}
+	}
+
+	// Improved payload versions are streamed under the payload id
+	args := &BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		FeeRecipient: common.HexToAddress("0xdeadbeef"),
+	}
+
+	payload, err := w.buildPayload(args)
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+
+	update := next(args.Id())
+	full := payload.ResolveFull()
+
+	if update.BlockHash != full.ExecutionPayload.BlockHash || update.Fees.Cmp(full.BlockValue) != 0 {
+		t.Fatalf("update mismatch: have %s (fees %v), want %s (fees %v)", update.BlockHash, update.Fees, full.ExecutionPayload.BlockHash, full.BlockValue)
+	}
+
+	if update.GasUsed != full.ExecutionPayload.GasUsed || update.Number != 1 {
+		t.Fatalf("unexpected update: %+v", update)
+	}
+
+	// So is the local sealing work, without a payload id. Don't interrupt it at
+	// the block time, which is already due for ethash blocks.
+	w.interruptCommitFlag = false
+	w.skipSealHook = func(task *task) bool { return true }
+	w.start()
+
+	if update := next(engine.PayloadID{}); update.Fees.Sign() <= 0 || update.Number != 1 {
+		t.Fatalf("unexpected sealing work update: %+v", update)
+	}
+}
+
+func TestBuildBorPayload(t *testing.T) {
+	t.Parallel()
+
+	// Shorten the sprint so that the first block ends it
+	borConfig := *params.BorUnittestChainConfig.Bor
+	borConfig.Sprint = map[string]uint64{"0": 2}
+
+	chainConfig := *params.BorUnittestChainConfig
+	chainConfig.Bor = &borConfig
+
+	w, cfg := newBorTestWorker(t, &chainConfig)
+
+	if errs := w.eth.TxPool().AddLocals([]*types.Transaction{newBuilderTestTx(t, cfg, 0, params.GWei)}); errs[0] != nil {
+		t.Fatalf("failed to add pending transaction: %v", errs[0])
+	}
+
+	parent := w.chain.CurrentBlock()
+
+	if _, err := w.buildBorPayload(&BorPayloadArgs{Parent: parent.Hash(), FeeRecipient: common.HexToAddress("0xdeadbeef")}); err == nil {
+		t.Fatal("payload built for a fee recipient other than the signer")
+	}
+
+	payload, err := w.buildBorPayload(&BorPayloadArgs{Parent: parent.Hash(), FeeRecipient: TestBankAddress})
+	if err != nil {
+		t.Fatalf("failed to build payload: %v", err)
+	}
+
+	if full := payload.ResolveFull(); len(full.ExecutionPayload.Transactions) != 1 {
+		t.Fatalf("transaction count mismatch: have %d, want 1", len(full.ExecutionPayload.Transactions))
+	}
+
+	env := payload.ResolveBor()
+	header := env.Header
+
+	// The header fields must be the ones Bor.Prepare sets for the local signer
+	if header.ParentHash != parent.Hash() || header.Coinbase != (common.Address{}) || header.MixDigest != (common.Hash{}) {
+		t.Fatalf("unexpected header: %+v", header)
+	}
+
+	if header.Difficulty.Uint64() != 1 {
+		t.Fatalf("difficulty mismatch: have %v, want 1", header.Difficulty)
+	}
+
+	if header.Time < parent.Time+borConfig.CalculatePeriod(1) {
+		t.Fatalf("timestamp %d before producer delay, parent %d", header.Time, parent.Time)
+	}
+
+	// The block ends the sprint, so it carries the next validator set
+	validatorBytes := header.Extra[types.ExtraVanityLength : len(header.Extra)-types.ExtraSealLength]
+	if want := TestBankAddress.Bytes(); len(validatorBytes) != 40 || !bytes.Equal(validatorBytes[:common.AddressLength], want) {
+		t.Fatalf("validator bytes mismatch: %x", validatorBytes)
+	}
+
+	if len(env.Transactions) != 1 || env.BlockValue.ToInt().Sign() <= 0 {
+		t.Fatalf("unexpected payload body: %d txs, value %v", len(env.Transactions), env.BlockValue)
+	}
+}
+
 func TestPayloadId(t *testing.T) {
 	t.Parallel()
 
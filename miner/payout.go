@@ -0,0 +1,168 @@
+package miner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Payout strategies deciding which part of the profit of a block built on
+// behalf of a proposer is transferred to it.
+const (
+	PayoutProportional = "proportional" // The builder keeps Margin basis points of the profit
+	PayoutFixed        = "fixed"        // The builder keeps Margin wei of the profit
+)
+
+// payoutContractGas is the gas reserved for the proposer payment if the fee
+// recipient is a contract, which may run code on receiving funds.
+const payoutContractGas = 50_000
+
+var errPaymentFailed = errors.New("proposer payment failed")
+
+// PayoutConfig configures the payment made to the fee recipient of blocks the
+// worker builds on behalf of a proposer. If a key is set the fees are credited
+// to the key's address instead, and a final transaction transfers the profit,
+// minus the builder margin, to the fee recipient.
+type PayoutConfig struct {
+	Key      *ecdsa.PrivateKey `toml:"-"` // Builder key collecting the fees and signing the payment, nil to credit the fee recipient directly
+	Strategy string            // Payout strategy, proportional if empty
+	Margin   *big.Int          // Share of the profit kept by the builder, interpreted by the strategy
+}
+
+// enabled reports whether payments are made through a builder account.
+func (c *PayoutConfig) enabled() bool {
+	return c.Key != nil
+}
+
+// address returns the builder address collecting the fees.
+func (c *PayoutConfig) address() common.Address {
+	return crypto.PubkeyToAddress(c.Key.PublicKey)
+}
+
+// payout returns the amount the proposer is entitled to out of the profit.
+func (c *PayoutConfig) payout(profit *big.Int) *big.Int {
+	margin := new(big.Int)
+
+	if c.Margin != nil {
+		switch c.Strategy {
+		case PayoutFixed:
+			margin.Set(c.Margin)
+		default:
+			margin.Mul(profit, c.Margin)
+			margin.Div(margin, big.NewInt(10_000))
+		}
+	}
+
+	if margin.Cmp(profit) >= 0 {
+		return new(big.Int)
+	}
+
+	return margin.Sub(profit, margin)
+}
+
+// proposerPayment tracks the payment owed to the proposer a block is built for.
+type proposerPayment struct {
+	proposer common.Address // Fee recipient requested by the proposer
+	balance  *big.Int       // Builder balance before filling the block
+	gas      uint64         // Gas reserved for the payment transaction
+}
+
+// reservePayment prepares env for paying its profit to proposer at the end:
+// it records the builder balance and sets aside the gas of the payment. The
+// fees must already be credited to the builder.
+func (w *worker) reservePayment(env *environment, proposer common.Address) (*proposerPayment, error) {
+	payment := &proposerPayment{
+		proposer: proposer,
+		balance:  env.state.GetBalance(env.coinbase),
+		gas:      params.TxGas,
+	}
+
+	if env.state.GetCodeSize(proposer) > 0 {
+		payment.gas = payoutContractGas
+	}
+
+	if env.header.GasLimit < payment.gas {
+		return nil, fmt.Errorf("gas limit %d too low for proposer payment", env.header.GasLimit)
+	}
+
+	env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit - payment.gas)
+
+	return payment, nil
+}
+
+// commitPayment appends the transaction paying the proposer its share of the
+// profit made since reservePayment, and returns the paid amount. The cost of
+// the payment itself is borne by the proposer. If the payment fails env is
+// left without it.
+func (w *worker) commitPayment(ctx context.Context, env *environment, payment *proposerPayment) (*big.Int, error) {
+	var (
+		builder  = w.config.Payout.address()
+		profit   = new(big.Int).Sub(env.state.GetBalance(builder), payment.balance)
+		value    = w.config.Payout.payout(profit)
+		gasPrice = new(big.Int)
+	)
+
+	env.gasPool.AddGas(payment.gas)
+
+	if env.header.BaseFee != nil {
+		gasPrice = env.header.BaseFee
+	}
+
+	value.Sub(value, new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(payment.gas)))
+	if value.Sign() <= 0 {
+		return new(big.Int), nil
+	}
+
+	var txData types.TxData = &types.LegacyTx{
+		Nonce:    env.state.GetNonce(builder),
+		GasPrice: gasPrice,
+		Gas:      payment.gas,
+		To:       &payment.proposer,
+		Value:    value,
+	}
+
+	if env.header.BaseFee != nil {
+		txData = &types.DynamicFeeTx{
+			ChainID:   w.chainConfig.ChainID,
+			Nonce:     env.state.GetNonce(builder),
+			GasTipCap: new(big.Int),
+			GasFeeCap: gasPrice,
+			Gas:       payment.gas,
+			To:        &payment.proposer,
+			Value:     value,
+		}
+	}
+
+	tx, err := types.SignNewTx(w.config.Payout.Key, types.LatestSigner(w.chainConfig), txData)
+	if err != nil {
+		return nil, err
+	}
+
+	payEnv := env.copy()
+	payEnv.state.SetTxContext(tx.Hash(), payEnv.tcount)
+
+	if _, err := w.commitTransaction(payEnv, tx, ctx); err != nil {
+		payEnv.discard()
+		return nil, fmt.Errorf("%w: %v", errPaymentFailed, err)
+	}
+
+	if receipt := payEnv.receipts[len(payEnv.receipts)-1]; receipt.Status != types.ReceiptStatusSuccessful {
+		payEnv.discard()
+		return nil, fmt.Errorf("%w: transaction reverted", errPaymentFailed)
+	}
+
+	payEnv.tcount++
+
+	env.discard()
+	*env = *payEnv
+
+	return value, nil
+}
@@ -0,0 +1,118 @@
+package miner
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestPayoutStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy string
+		margin   int64
+		profit   int64
+		want     int64
+	}{
+		{PayoutProportional, 0, 1000, 1000},
+		{PayoutProportional, 500, 1000, 950},
+		{"", 500, 1000, 950},
+		{PayoutProportional, 10_000, 1000, 0},
+		{PayoutFixed, 100, 1000, 900},
+		{PayoutFixed, 2000, 1000, 0},
+	}
+
+	for i, tt := range tests {
+		config := &PayoutConfig{Strategy: tt.strategy, Margin: big.NewInt(tt.margin)}
+		if have := config.payout(big.NewInt(tt.profit)); have.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("test %d: payout mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestGenerateWorkWithProposerPayment(t *testing.T) {
+	t.Parallel()
+
+	var (
+		w, cfg     = newBuilderTestWorker(t)
+		builder, _ = crypto.GenerateKey()
+		proposer   = common.HexToAddress("0xb0b")
+		tip        = int64(params.GWei)
+	)
+
+	w.config.Payout = PayoutConfig{Key: builder, Strategy: PayoutProportional, Margin: big.NewInt(1000)}
+
+	for _, err := range w.eth.TxPool().AddLocals([]*types.Transaction{newBuilderTestTx(t, cfg, 0, tip), newBuilderTestTx(t, cfg, 1, tip)}) {
+		if err != nil {
+			t.Fatalf("failed to add pending transaction: %v", err)
+		}
+	}
+
+	block, value, err := w.generateWork(context.Background(), &generateParams{timestamp: uint64(time.Now().Unix()), coinbase: proposer})
+	if err != nil {
+		t.Fatalf("failed to generate work: %v", err)
+	}
+
+	txs := block.Transactions()
+	if len(txs) != 3 {
+		t.Fatalf("transaction count mismatch: have %d, want 3", len(txs))
+	}
+
+	// The builder keeps 10% of the tips and pays for the transfer out of the rest
+	var (
+		profit  = big.NewInt(2 * tip * int64(params.TxGas))
+		cost    = new(big.Int).Mul(block.BaseFee(), big.NewInt(int64(params.TxGas)))
+		want    = new(big.Int).Sub(new(big.Int).Div(new(big.Int).Mul(profit, big.NewInt(9)), big.NewInt(10)), cost)
+		payment = txs[2]
+	)
+
+	if payment.To() == nil || *payment.To() != proposer {
+		t.Fatalf("payment recipient mismatch: have %v, want %s", payment.To(), proposer)
+	}
+
+	if payment.Value().Cmp(want) != 0 || value.Cmp(want) != 0 {
+		t.Fatalf("payment value mismatch: have %v, reported %v, want %v", payment.Value(), value, want)
+	}
+}
+
+func TestGenerateWorkWithFailedProposerPayment(t *testing.T) {
+	t.Parallel()
+
+	var (
+		w, cfg     = newBuilderTestWorker(t)
+		builder, _ = crypto.GenerateKey()
+		tip        = int64(params.GWei)
+
+		// The payment to a precompile runs out of gas, as it reserves none for
+		// calling the precompile
+		proposer = common.BytesToAddress([]byte{0x01})
+	)
+
+	w.config.Payout = PayoutConfig{Key: builder, Strategy: PayoutProportional, Margin: big.NewInt(1000)}
+
+	for _, err := range w.eth.TxPool().AddLocals([]*types.Transaction{newBuilderTestTx(t, cfg, 0, tip), newBuilderTestTx(t, cfg, 1, tip)}) {
+		if err != nil {
+			t.Fatalf("failed to add pending transaction: %v", err)
+		}
+	}
+
+	block, value, err := w.generateWork(context.Background(), &generateParams{timestamp: uint64(time.Now().Unix()), coinbase: proposer})
+	if err != nil {
+		t.Fatalf("failed to generate work: %v", err)
+	}
+
+	if txs := block.Transactions(); len(txs) != 2 {
+		t.Fatalf("transaction count mismatch: have %d, want 2", len(txs))
+	}
+
+	if value.Sign() != 0 {
+		t.Fatalf("paid value mismatch: have %v, want 0", value)
+	}
+}
@@ -0,0 +1,50 @@
+package miner
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// mergePrivateTxs appends the private transactions which may be included in
+// the block of env to the pending transactions of their senders, in the local
+// set if the sender has local transactions and the remote one otherwise. Only
+// transactions continuing the nonce sequence of their sender are merged.
+func (w *worker) mergePrivateTxs(env *environment, localTxs, remoteTxs map[common.Address]types.Transactions) {
+	pool := w.eth.PrivatePool()
+	if pool == nil {
+		return
+	}
+
+	for sender, private := range pool.Pending(env.header.Number.Uint64()) {
+		set := remoteTxs
+		if _, ok := localTxs[sender]; ok {
+			set = localTxs
+		}
+
+		// Don't append to the pool's slice, it may be shared with other callers
+		txs := set[sender]
+		txs = txs[:len(txs):len(txs)]
+
+		nonce := env.state.GetNonce(sender)
+		if len(txs) > 0 {
+			nonce = txs[len(txs)-1].Nonce() + 1
+		}
+
+		for _, tx := range private {
+			if tx.Nonce() < nonce {
+				continue
+			}
+
+			if tx.Nonce() > nonce {
+				break
+			}
+
+			txs = append(txs, tx)
+			nonce++
+		}
+
+		if len(txs) > 0 {
+			set[sender] = txs
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package miner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestFillTransactionsWithPrivateTxs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		w, cfg  = newBuilderTestWorker(t)
+		public  = newBuilderTestTx(t, cfg, 0, params.GWei)
+		private = types.Transactions{newBuilderTestTx(t, cfg, 1, params.GWei), newBuilderTestTx(t, cfg, 3, params.GWei)}
+	)
+
+	for _, err := range w.eth.TxPool().AddLocals(types.Transactions{public}) {
+		if err != nil {
+			t.Fatalf("failed to add pending transaction: %v", err)
+		}
+	}
+
+	for _, tx := range private {
+		if err := w.eth.PrivatePool().Add(tx, 1); err != nil {
+			t.Fatalf("failed to add private transaction: %v", err)
+		}
+	}
+
+	if w.eth.TxPool().Get(private[0].Hash()) != nil {
+		t.Fatalf("private transaction leaked into the pool")
+	}
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: common.HexToAddress("0xc014ba5e")})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	if err := w.fillTransactions(context.Background(), nil, env, context.Background()); err != nil {
+		t.Fatalf("failed to fill transactions: %v", err)
+	}
+
+	// The private transaction with a nonce gap is left out
+	want := types.Transactions{public, private[0]}
+	if len(env.txs) != len(want) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(env.txs), len(want))
+	}
+
+	for i, tx := range env.txs {
+		if tx.Hash() != want[i].Hash() {
+			t.Errorf("transaction %d mismatch: have %s, want %s", i, tx.Hash(), want[i].Hash())
+		}
+	}
+}
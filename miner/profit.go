@@ -0,0 +1,31 @@
+package miner
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Revenue of the last sealed block, values in gwei.
+var (
+	profitTipsGauge         = metrics.NewRegisteredGauge("worker/profit/tips", nil)
+	profitTransfersGauge    = metrics.NewRegisteredGauge("worker/profit/transfers", nil)
+	profitBurntGauge        = metrics.NewRegisteredGauge("worker/profit/burnt", nil)
+	profitValueGauge        = metrics.NewRegisteredGauge("worker/profit/value", nil)
+	profitStateSyncGasGauge = metrics.NewRegisteredGauge("worker/profit/statesyncgas", nil)
+)
+
+// updateProfitMetrics reports the revenue of a sealed block.
+func updateProfitMetrics(profit *types.BlockProfit) {
+	gwei := func(wei *big.Int) int64 {
+		return new(big.Int).Div(wei, big.NewInt(params.GWei)).Int64()
+	}
+
+	profitTipsGauge.Update(gwei(profit.Tips()))
+	profitTransfersGauge.Update(gwei(profit.Transfers()))
+	profitBurntGauge.Update(gwei(profit.Burnt()))
+	profitValueGauge.Update(gwei(profit.Value()))
+	profitStateSyncGasGauge.Update(int64(profit.StateSyncGas))
+}
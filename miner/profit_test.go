@@ -0,0 +1,97 @@
+package miner
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestBlockProfit(t *testing.T) {
+	t.Parallel()
+
+	w, cfg := newBuilderTestWorker(t)
+
+	env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: common.HexToAddress("0xc014ba5e")})
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+
+	// The second transaction pays the coinbase directly on top of its tip
+	var (
+		tip     = newBuilderTestTx(t, cfg, 0, params.GWei)
+		payment = types.MustSignNewTx(testBankKey, types.LatestSigner(cfg), &types.DynamicFeeTx{
+			ChainID:   cfg.ChainID,
+			Nonce:     1,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(1 + 100*params.InitialBaseFee),
+			Gas:       params.TxGas,
+			To:        &env.coinbase,
+			Value:     big.NewInt(params.Ether),
+		})
+	)
+
+	for _, tx := range []*types.Transaction{tip, payment} {
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+
+		if _, err := w.commitTransaction(env, tx, context.Background()); err != nil {
+			t.Fatalf("failed to commit transaction: %v", err)
+		}
+
+		env.tcount++
+	}
+
+	profit := types.NewBlockProfit(w.chainConfig, env.header, env.coinbase, env.txs, env.receipts, env.balances, []*types.StateSyncData{{ID: 1, Gas: 50000}})
+
+	var (
+		baseFee = env.header.BaseFee
+		gas     = new(big.Int).SetUint64(params.TxGas)
+	)
+
+	for i, want := range []struct {
+		tip      *big.Int
+		transfer *big.Int
+	}{
+		{new(big.Int).Mul(gas, big.NewInt(params.GWei)), new(big.Int)},
+		{new(big.Int).Set(gas), big.NewInt(params.Ether)},
+	} {
+		have := profit.Txs[i]
+
+		if have.Hash != env.txs[i].Hash() || have.GasUsed != params.TxGas {
+			t.Errorf("transaction %d mismatch: have %x (gas %d), want %x", i, have.Hash, have.GasUsed, env.txs[i].Hash())
+		}
+
+		if have.Tip.Cmp(want.tip) != 0 {
+			t.Errorf("transaction %d tip mismatch: have %v, want %v", i, have.Tip, want.tip)
+		}
+
+		if have.Transfer.Cmp(want.transfer) != 0 {
+			t.Errorf("transaction %d transfer mismatch: have %v, want %v", i, have.Transfer, want.transfer)
+		}
+
+		if burnt := new(big.Int).Mul(gas, baseFee); have.Burnt.Cmp(burnt) != 0 {
+			t.Errorf("transaction %d burnt mismatch: have %v, want %v", i, have.Burnt, burnt)
+		}
+	}
+
+	if want := common.HexToAddress(cfg.Bor.CalculateBurntContract(env.header.Number.Uint64())); profit.BurntContract != want {
+		t.Errorf("burnt contract mismatch: have %x, want %x", profit.BurntContract, want)
+	}
+
+	if profit.StateSyncs != 1 || profit.StateSyncGas != 50000 {
+		t.Errorf("state sync mismatch: have %d events (gas %d), want 1 (gas 50000)", profit.StateSyncs, profit.StateSyncGas)
+	}
+
+	// The value accounts for the direct payment, as the bids of external builders
+	if value := envFees(env); value.Cmp(profit.Value()) != 0 || value.Cmp(big.NewInt(params.Ether)) <= 0 {
+		t.Errorf("value mismatch: have %v, profit %v", value, profit.Value())
+	}
+}
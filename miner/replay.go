@@ -0,0 +1,160 @@
+package miner
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ReplayCanonical is the algorithm of the canonical block in replay results.
+const ReplayCanonical = "canonical"
+
+// ReplayResult is a block built during a replay.
+type ReplayResult struct {
+	Algorithm string
+	Txs       int
+	GasUsed   uint64
+	Value     *big.Int // Payment to the coinbase
+}
+
+// Replay rebuilds the canonical block with the given number with each of the
+// algorithms, out of the transactions pending when it was built and on top of
+// the state of its parent. The results follow the one of the canonical block,
+// executed the same way. Fees are paid to coinbase, the one of the canonical
+// block if nil. Bundles, private and conditional lanes are not replayed.
+func Replay(chain *core.BlockChain, number uint64, coinbase *common.Address, txs types.Transactions, algorithms []string) ([]*ReplayResult, error) {
+	block := chain.GetBlockByNumber(number)
+	if block == nil || number == 0 {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+
+	parent := chain.GetHeader(block.ParentHash(), number-1)
+	if parent == nil || !chain.HasState(parent.Root) {
+		return nil, fmt.Errorf("state of block %d not available", number-1)
+	}
+
+	if coinbase == nil {
+		cb := block.Coinbase()
+		coinbase = &cb
+	}
+
+	w := &worker{
+		config:           &Config{},
+		chainConfig:      chain.Config(),
+		chain:            chain,
+		conditionalSkips: newConditionalSkips(),
+	}
+
+	replay := func(name string, fill func(env *environment) error) (*ReplayResult, error) {
+		header := types.CopyHeader(block.Header())
+		header.GasUsed = 0
+
+		env, err := w.makeEnv(parent, header, *coinbase)
+		if err != nil {
+			return nil, err
+		}
+		defer env.discard()
+
+		env.gasPool = new(core.GasPool).AddGas(header.GasLimit)
+		balance := env.state.GetBalance(env.coinbase)
+
+		if err := fill(env); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		return &ReplayResult{
+			Algorithm: name,
+			Txs:       len(env.txs),
+			GasUsed:   env.header.GasUsed,
+			Value:     new(big.Int).Sub(env.state.GetBalance(env.coinbase), balance),
+		}, nil
+	}
+
+	canonical, err := replay(ReplayCanonical, func(env *environment) error {
+		for _, tx := range block.Transactions() {
+			env.state.SetTxContext(tx.Hash(), env.tcount)
+
+			// State sync transactions are applied by the consensus engine
+			if _, err := w.commitTransaction(env, tx, context.Background()); err != nil {
+				log.Debug("Skipping canonical transaction", "number", number, "hash", tx.Hash(), "err", err)
+				continue
+			}
+
+			env.tcount++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*ReplayResult{canonical}
+
+	for _, name := range algorithms {
+		algorithm, err := NewBlockBuildingAlgorithm(name)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := replay(algorithm.Name(), func(env *environment) error {
+			pending := &pendingSet{remotes: replayPending(env, txs)}
+			return algorithm.Fill(context.Background(), w, env, pending, nil, context.Background())
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// replayPending groups txs by sender, keeping for each the transactions which
+// are executable on the state of env in nonce order.
+func replayPending(env *environment, txs types.Transactions) map[common.Address]types.Transactions {
+	pending := make(map[common.Address]types.Transactions)
+
+	for _, tx := range txs {
+		from, err := types.Sender(env.signer, tx)
+		if err != nil {
+			continue
+		}
+
+		pending[from] = append(pending[from], tx)
+	}
+
+	for from, list := range pending {
+		sort.Stable(types.TxByNonce(list))
+
+		var (
+			nonce = env.state.GetNonce(from)
+			start = 0
+		)
+
+		for start < len(list) && list[start].Nonce() < nonce {
+			start++
+		}
+
+		end := start
+		for end < len(list) && list[end].Nonce() == nonce+uint64(end-start) {
+			end++
+		}
+
+		if start == end {
+			delete(pending, from)
+			continue
+		}
+
+		pending[from] = list[start:end]
+	}
+
+	return pending
+}
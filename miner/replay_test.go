@@ -0,0 +1,102 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				TestBankAddress: {Balance: testBankFunds},
+				testUserAddress: {Balance: testBankFunds},
+			},
+			GasLimit: 30_000_000,
+		}
+		signer = types.LatestSigner(params.TestChainConfig)
+	)
+
+	newTx := func(key, tip int64) *types.Transaction {
+		k := testBankKey
+		if key != 0 {
+			k = testUserKey
+		}
+
+		return types.MustSignNewTx(k, signer, &types.DynamicFeeTx{
+			ChainID:   params.TestChainConfig.ChainID,
+			GasTipCap: big.NewInt(tip),
+			GasFeeCap: big.NewInt(100 * params.GWei),
+			Gas:       params.TxGas,
+			To:        &common.Address{0x01},
+		})
+	}
+
+	// The canonical block only includes the cheap transaction of the bank,
+	// while the user's transaction was pending too
+	var (
+		cheap  = newTx(0, 1)
+		pricey = newTx(1, params.GWei)
+	)
+
+	genesis := gspec.MustCommit(db)
+
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, db, 1, func(i int, gen *core.BlockGen) {
+		gen.AddTx(cheap)
+	})
+
+	chain, err := core.NewBlockChain(db, nil, gspec, nil, engine, vm.Config{}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	results, err := Replay(chain, 1, nil, types.Transactions{pricey, cheap}, []string{AlgorithmGreedyTip, AlgorithmGreedyProfit})
+	if err != nil {
+		t.Fatalf("failed to replay block: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("result count mismatch: have %d, want %d", len(results), 3)
+	}
+
+	canonical := results[0]
+	if canonical.Algorithm != ReplayCanonical || canonical.Txs != 1 || canonical.GasUsed != blocks[0].GasUsed() {
+		t.Errorf("canonical result mismatch: have %+v", canonical)
+	}
+
+	if want := big.NewInt(int64(params.TxGas)); canonical.Value.Cmp(want) != 0 {
+		t.Errorf("canonical value mismatch: have %v, want %v", canonical.Value, want)
+	}
+
+	for _, result := range results[1:] {
+		if result.Txs != 2 || result.GasUsed != 2*params.TxGas {
+			t.Errorf("%s: result mismatch: have %+v", result.Algorithm, result)
+		}
+
+		if result.Value.Cmp(canonical.Value) <= 0 {
+			t.Errorf("%s: value %v not above canonical %v", result.Algorithm, result.Value, canonical.Value)
+		}
+	}
+
+	if _, err := Replay(chain, 2, nil, nil, nil); err == nil {
+		t.Errorf("replay of missing block succeeded")
+	}
+}
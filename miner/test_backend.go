@@ -14,6 +14,8 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/bundlepool"
+	"github.com/ethereum/go-ethereum/core/privatepool"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -88,11 +90,13 @@ func init() {
 
 // testWorkerBackend implements worker.Backend interfaces and wraps all information needed during the testing.
 type testWorkerBackend struct {
-	DB         ethdb.Database
-	txPool     *txpool.TxPool
-	chain      *core.BlockChain
-	Genesis    *core.Genesis
-	uncleBlock *types.Block
+	DB          ethdb.Database
+	txPool      *txpool.TxPool
+	bundlePool  *bundlepool.BundlePool
+	privatePool *privatepool.PrivatePool
+	chain       *core.BlockChain
+	Genesis     *core.Genesis
+	uncleBlock  *types.Block
 }
 
 // PeerCount implements testWorkerBackend.
@@ -150,16 +154,24 @@ func newTestWorkerBackend(t TensingObject, chainConfig *params.ChainConfig, engi
 	})
 
 	return &testWorkerBackend{
-		DB:         db,
-		chain:      chain,
-		txPool:     txpool,
-		Genesis:    &gspec,
-		uncleBlock: blocks[0],
+		DB:          db,
+		chain:       chain,
+		txPool:      txpool,
+		bundlePool:  bundlepool.New(bundlepool.DefaultConfig, chain),
+		privatePool: privatepool.New(privatepool.DefaultConfig, chain.Config(), chain),
+		Genesis:     &gspec,
+		uncleBlock:  blocks[0],
 	}
 }
 
 func (b *testWorkerBackend) BlockChain() *core.BlockChain { return b.chain }
 func (b *testWorkerBackend) TxPool() *txpool.TxPool       { return b.txPool }
+func (b *testWorkerBackend) BundlePool() *bundlepool.BundlePool {
+	return b.bundlePool
+}
+func (b *testWorkerBackend) PrivatePool() *privatepool.PrivatePool {
+	return b.privatePool
+}
 func (b *testWorkerBackend) StateAtBlock(block *types.Block, reexec uint64, base *state.StateDB, checkLive bool, preferDisk bool) (statedb *state.StateDB, err error) {
 	return nil, errors.New("not supported")
 }
@@ -294,6 +306,8 @@ func newWorkerWithDelay(config *Config, chainConfig *params.ChainConfig, engine
 		resubmitIntervalCh:  make(chan time.Duration),
 		resubmitAdjustCh:    make(chan *intervalAdjust, resubmitAdjustChanSize),
 		interruptCommitFlag: config.CommitInterruptFlag,
+		conditionalSkips:    newConditionalSkips(),
+		algorithm:           newWorkerAlgorithm(config.Algorithm),
 	}
 	worker.noempty.Store(true)
 	worker.profileCount = new(int32)
@@ -323,6 +337,10 @@ func newWorkerWithDelay(config *Config, chainConfig *params.ChainConfig, engine
 		recommit = minRecommitInterval
 	}
 
+	if len(config.Builders) > 0 {
+		worker.builders = newBuilderClient(config.Builders, config.BuilderTimeout, config.BuilderCutoff)
+	}
+
 	ctx := tracing.WithTracer(context.Background(), otel.GetTracerProvider().Tracer("MinerWorker"))
 
 	worker.wg.Add(4)
@@ -37,6 +37,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	cmath "github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/common/tracing"
@@ -44,7 +45,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/blockstm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -52,7 +53,6 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 )
 
@@ -122,7 +122,14 @@ type environment struct {
 	header   *types.Header
 	txs      []*types.Transaction
 	receipts []*types.Receipt
+	balances []*big.Int // Coinbase balance before the first transaction and after each one
 	uncles   map[common.Hash]*types.Header
+
+	stateSyncs []*types.StateSyncData // State sync data committed when finalizing the block
+
+	bundles []*simulatedBundle // Bundles not yet included, most profitable first
+
+	accesses *txAccesses // State accessed by the transactions, nil if their dependencies aren't recorded
 }
 
 // copy creates a deep copy of environment.
@@ -136,6 +143,8 @@ func (env *environment) copy() *environment {
 		coinbase:  env.coinbase,
 		header:    types.CopyHeader(env.header),
 		receipts:  copyReceipts(env.receipts),
+		balances:  append([]*big.Int(nil), env.balances...),
+		accesses:  env.accesses.copy(),
 	}
 
 	if env.gasPool != nil {
@@ -179,11 +188,13 @@ func (env *environment) discard() {
 // task contains all information for consensus engine sealing and result submitting.
 type task struct {
 	//nolint:containedctx
-	ctx       context.Context
-	receipts  []*types.Receipt
-	state     *state.StateDB
-	block     *types.Block
-	createdAt time.Time
+	ctx        context.Context
+	receipts   []*types.Receipt
+	state      *state.StateDB
+	block      *types.Block
+	profit     *types.BlockProfit
+	stateSyncs []*types.StateSyncData
+	createdAt  time.Time
 }
 
 const (
@@ -234,6 +245,7 @@ type worker struct {
 
 	// Feeds
 	pendingLogsFeed event.Feed
+	payloadFeed     event.Feed // Improved versions of payloads and of the local sealing work
 
 	// Subscriptions
 	mux          *event.TypeMux
@@ -307,6 +319,17 @@ type worker struct {
 	profileCount        *int32 // Global count for profiling
 	interruptCommitFlag bool   // Interrupt commit ( Default true )
 	interruptedTxCache  *vm.TxCache
+
+	builders  *builderClient // Client for external block builders, nil if none configured
+	auctionMu sync.Mutex     // Lock serialising updates of the builder auction records
+
+	bestWork *PayloadUpdate // Most valuable local sealing work announced for the current parent, only accessed by the main loop
+
+	conditionalSkips *conditionalSkipCache // Last reason conditional transactions were left out of a block for
+
+	algorithm BlockBuildingAlgorithm // Strategy picking and ordering the pending transactions of blocks
+
+	parallelProcs int // Workers executing the candidate transactions in parallel, zero to execute them sequentially
 }
 
 //nolint:staticcheck
@@ -337,6 +360,9 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		resubmitIntervalCh:  make(chan time.Duration),
 		resubmitAdjustCh:    make(chan *intervalAdjust, resubmitAdjustChanSize),
 		interruptCommitFlag: config.CommitInterruptFlag,
+		conditionalSkips:    newConditionalSkips(),
+		algorithm:           newWorkerAlgorithm(config.Algorithm),
+		parallelProcs:       parallelBuildProcs(config, eth.BlockChain()),
 	}
 	worker.noempty.Store(true)
 	worker.profileCount = new(int32)
@@ -381,6 +407,10 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 
 	worker.newpayloadTimeout = newpayloadTimeout
 
+	if len(config.Builders) > 0 {
+		worker.builders = newBuilderClient(config.Builders, config.BuilderTimeout, config.BuilderCutoff)
+	}
+
 	ctx := tracing.WithTracer(context.Background(), otel.GetTracerProvider().Tracer("MinerWorker"))
 
 	worker.wg.Add(4)
@@ -497,6 +527,10 @@ func (w *worker) close() {
 	w.running.Store(false)
 	close(w.exitCh)
 	w.wg.Wait()
+
+	if w.builders != nil {
+		w.builders.close()
+	}
 }
 
 // recalcRecommit recalculates the resubmitting interval upon feedback.
@@ -581,6 +615,7 @@ func (w *worker) newWorkLoop(ctx context.Context, recommit time.Duration) {
 
 		case head := <-w.chainHeadCh:
 			clearPending(head.Block.NumberU64())
+			w.pruneBuilderAuctions(head.Block.NumberU64())
 
 			timestamp = time.Now().Unix()
 
@@ -902,7 +937,10 @@ func (w *worker) resultLoop() {
 
 					logs = append(logs, receipt.Logs...)
 				}
-				// Commit block and state to database.
+				// Commit block and state to database, along with the state sync
+				// data of the block for the chain to send them out.
+				w.chain.SetStateSync(task.stateSyncs)
+
 				tracing.Exec(ctx, "", "resultLoop.WriteBlockAndSetHead", func(ctx context.Context, span trace.Span) {
 					_, err = w.chain.WriteBlockAndSetHead(ctx, block, receipts, logs, task.state, true)
 				})
@@ -926,6 +964,18 @@ func (w *worker) resultLoop() {
 			log.Info("Successfully sealed new block", "number", block.Number(), "sealhash", sealhash, "hash", hash,
 				"elapsed", common.PrettyDuration(time.Since(task.createdAt)))
 
+			if w.builders != nil {
+				w.sealedBuilderBlock(block)
+			}
+
+			if task.profit != nil {
+				profit := *task.profit
+				profit.Hash = hash
+
+				rawdb.WriteBlockProfit(w.chain.DB(), &profit)
+				updateProfitMetrics(&profit)
+			}
+
 			// Broadcast the block and announce chain insertion event
 			w.mux.Post(core.NewMinedBlockEvent{Block: block})
 
@@ -963,8 +1013,13 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 		ancestors: mapset.NewSet[common.Hash](),
 		family:    mapset.NewSet[common.Hash](),
 		header:    header,
+		balances:  []*big.Int{state.GetBalance(coinbase)},
 		uncles:    make(map[common.Hash]*types.Header),
 	}
+
+	if w.recordsTxDependency(header) {
+		env.accesses = new(txAccesses)
+	}
 	// when 08 is processed ancestors contain 07 (quick block)
 	for _, ancestor := range w.chain.GetBlocksFromHash(parent.Hash(), 7) {
 		for _, uncle := range ancestor.Uncles() {
@@ -1033,7 +1088,25 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction, inte
 	// nolint : staticcheck
 	interruptCtx = vm.SetCurrentTxOnContext(interruptCtx, tx.Hash())
 
+	// Record the state accessed by the transaction for its dependencies
+	if env.accesses != nil {
+		env.state.AddEmptyMVHashMap()
+	}
+
 	receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig(), interruptCtx)
+
+	if env.accesses != nil {
+		reads, writes := env.state.MVReadList(), env.state.MVFullWriteList()
+
+		env.state.SetMVHashmap(nil)
+		env.state.ClearReadMap()
+		env.state.ClearWriteMap()
+
+		if err == nil {
+			env.accesses.add(reads, writes)
+		}
+	}
+
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
@@ -1043,12 +1116,13 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction, inte
 
 	env.txs = append(env.txs, tx)
 	env.receipts = append(env.receipts, receipt)
+	env.balances = append(env.balances, env.state.GetBalance(env.coinbase))
 
 	return receipt.Logs, nil
 }
 
 //nolint:gocognit
-func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByPriceAndNonce, interrupt *atomic.Int32, interruptCtx context.Context) error {
+func (w *worker) commitTransactions(env *environment, txs orderedTxs, interrupt *atomic.Int32, interruptCtx context.Context) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
@@ -1056,45 +1130,13 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 
 	var coalescedLogs []*types.Log
 
-	var depsMVReadList [][]blockstm.ReadDescriptor
-
-	var depsMVFullWriteList [][]blockstm.WriteDescriptor
-
-	var mvReadMapList []map[blockstm.Key]blockstm.ReadDescriptor
-
-	var deps map[int]map[int]bool
-
-	chDeps := make(chan blockstm.TxDep)
-
-	var count int
-
-	var depsWg sync.WaitGroup
-
-	EnableMVHashMap := false
-
-	// create and add empty mvHashMap in statedb
-	if EnableMVHashMap {
-		depsMVReadList = [][]blockstm.ReadDescriptor{}
-
-		depsMVFullWriteList = [][]blockstm.WriteDescriptor{}
-
-		mvReadMapList = []map[blockstm.Key]blockstm.ReadDescriptor{}
-
-		deps = map[int]map[int]bool{}
-
-		chDeps = make(chan blockstm.TxDep)
+	// Execute the candidates speculatively in parallel if configured, which
+	// looks ahead at the following transactions of txs
+	commit := w.commitTransaction
 
-		count = 0
-
-		depsWg.Add(1)
-
-		go func(chDeps chan blockstm.TxDep) {
-			for t := range chDeps {
-				deps = blockstm.UpdateDeps(deps, t)
-			}
-
-			depsWg.Done()
-		}(chDeps)
+	if w.parallelProcs > 0 {
+		spec := newSpeculator(w, env, txs)
+		txs, commit = spec.txs, spec.commit
 	}
 
 	initialGasLimit := env.gasPool.Gas()
@@ -1116,10 +1158,6 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 mainloop:
 	for {
 		if interruptCtx != nil {
-			if EnableMVHashMap {
-				env.state.AddEmptyMVHashMap()
-			}
-
 			// case of interrupting by timeout
 			select {
 			case <-interruptCtx.Done():
@@ -1149,29 +1187,25 @@ mainloop:
 			breakCause = "all transactions has been included"
 			break
 		}
+		// Merge in the bundles paying the coinbase more per gas than the next transaction
+		if len(env.bundles) > 0 {
+			tip, _ := tx.EffectiveGasTip(env.header.BaseFee)
+			w.commitBundles(env, tip)
+
+			if env.gasPool.Gas() < params.TxGas {
+				breakCause = "Not enough gas for further transactions"
+				break
+			}
+		}
 		// Error may be ignored here. The error has already been checked
 		// during transaction acceptance is the transaction pool.
 		from, _ := types.Sender(env.signer, tx)
 
-		// not prioritising conditional transaction, yet.
-		//nolint:nestif
-		if options := tx.GetOptions(); options != nil {
-			if err := env.header.ValidateBlockNumberOptions4337(options.BlockNumberMin, options.BlockNumberMax); err != nil {
-				log.Trace("Dropping conditional transaction", "from", from, "hash", tx.Hash(), "reason", err)
-				txs.Pop()
-
-				continue
-			}
-
-			if err := env.header.ValidateTimestampOptions4337(options.TimestampMin, options.TimestampMax); err != nil {
-				log.Trace("Dropping conditional transaction", "from", from, "hash", tx.Hash(), "reason", err)
-				txs.Pop()
-
-				continue
-			}
-
-			if err := env.state.ValidateKnownAccounts(options.KnownAccounts); err != nil {
-				log.Trace("Dropping conditional transaction", "from", from, "hash", tx.Hash(), "reason", err)
+		// Re-check the options of conditional transactions against the state
+		// they're about to be executed on.
+		if tx.GetOptions() != nil {
+			if err := w.validateConditionalTx(env, tx); err != nil {
+				w.skipConditionalTx(env, tx, err)
 				txs.Pop()
 
 				continue
@@ -1195,7 +1229,7 @@ mainloop:
 			start = time.Now()
 		})
 
-		logs, err := w.commitTransaction(env, tx, interruptCtx)
+		logs, err := commit(env, tx, interruptCtx)
 
 		switch {
 		case errors.Is(err, core.ErrNonceTooLow):
@@ -1203,24 +1237,16 @@ mainloop:
 			log.Trace("Skipping transaction with low nonce", "sender", from, "nonce", tx.Nonce())
 			txs.Shift()
 
+		case errors.Is(err, errReordered):
+			// The speculative execution moved another transaction ahead, take it first
+
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
 			env.tcount++
 
-			if EnableMVHashMap {
-				depsMVReadList = append(depsMVReadList, env.state.MVReadList())
-				depsMVFullWriteList = append(depsMVFullWriteList, env.state.MVFullWriteList())
-				mvReadMapList = append(mvReadMapList, env.state.MVReadMap())
-
-				temp := blockstm.TxDep{
-					Index:         env.tcount - 1,
-					ReadList:      depsMVReadList[count],
-					FullWriteList: depsMVFullWriteList,
-				}
-
-				chDeps <- temp
-				count++
+			if tx.GetOptions() != nil {
+				conditionalCommittedMeter.Mark(1)
 			}
 
 			txs.Shift()
@@ -1235,73 +1261,6 @@ mainloop:
 			log.Debug("Transaction failed, account skipped", "hash", tx.Hash(), "err", err)
 			txs.Pop()
 		}
-
-		if EnableMVHashMap {
-			env.state.ClearReadMap()
-			env.state.ClearWriteMap()
-		}
-	}
-
-	// nolint:nestif
-	if EnableMVHashMap && w.IsRunning() {
-		close(chDeps)
-		depsWg.Wait()
-
-		var blockExtraData types.BlockExtraData
-
-		tempVanity := env.header.Extra[:types.ExtraVanityLength]
-		tempSeal := env.header.Extra[len(env.header.Extra)-types.ExtraSealLength:]
-
-		if len(mvReadMapList) > 0 {
-			tempDeps := make([][]uint64, len(mvReadMapList))
-
-			for j := range deps[0] {
-				tempDeps[0] = append(tempDeps[0], uint64(j))
-			}
-
-			delayFlag := true
-
-			for i := 1; i <= len(mvReadMapList)-1; i++ {
-				reads := mvReadMapList[i-1]
-
-				_, ok1 := reads[blockstm.NewSubpathKey(env.coinbase, state.BalancePath)]
-				_, ok2 := reads[blockstm.NewSubpathKey(common.HexToAddress(w.chainConfig.Bor.CalculateBurntContract(env.header.Number.Uint64())), state.BalancePath)]
-
-				if ok1 || ok2 {
-					delayFlag = false
-				}
-
-				for j := range deps[i] {
-					tempDeps[i] = append(tempDeps[i], uint64(j))
-				}
-			}
-
-			if err := rlp.DecodeBytes(env.header.Extra[types.ExtraVanityLength:len(env.header.Extra)-types.ExtraSealLength], &blockExtraData); err != nil {
-				log.Error("error while decoding block extra data", "err", err)
-				return err
-			}
-
-			if delayFlag {
-				blockExtraData.TxDependency = tempDeps
-			} else {
-				blockExtraData.TxDependency = nil
-			}
-		} else {
-			blockExtraData.TxDependency = nil
-		}
-
-		blockExtraDataBytes, err := rlp.EncodeToBytes(blockExtraData)
-		if err != nil {
-			log.Error("error while encoding block extra data: %v", err)
-			return err
-		}
-
-		env.header.Extra = []byte{}
-
-		env.header.Extra = append(tempVanity, blockExtraDataBytes...)
-
-		env.header.Extra = append(env.header.Extra, tempSeal...)
-
 	}
 
 	if !w.IsRunning() && len(coalescedLogs) > 0 {
@@ -1601,85 +1560,64 @@ func (w *worker) fillTransactions(ctx context.Context, interrupt *atomic.Int32,
 		)
 	})
 
-	var (
-		localEnvTCount  int
-		remoteEnvTCount int
-		err             error
-	)
-
-	if len(localTxs) > 0 {
-		var txs *types.TransactionsByPriceAndNonce
+	var err error
 
-		tracing.Exec(ctx, "", "worker.LocalTransactionsByPriceAndNonce", func(ctx context.Context, span trace.Span) {
-			var baseFee *uint256.Int
-			if env.header.BaseFee != nil {
-				baseFee = cmath.FromBig(env.header.BaseFee)
-			}
+	env.bundles = w.simulateBundles(env)
 
-			txs = types.NewTransactionsByPriceAndNonce(env.signer, localTxs, baseFee)
+	w.mergePrivateTxs(env, localTxs, remoteTxs)
 
-			tracing.SetAttributes(
-				span,
-				attribute.Int("len of tx local Heads", txs.GetTxs()),
-			)
-		})
-
-		tracing.Exec(ctx, "", "worker.LocalCommitTransactions", func(ctx context.Context, span trace.Span) {
-			err = w.commitTransactions(env, txs, interrupt, interruptCtx)
-		})
+	if w.config.ConditionalGasShare > 0 {
+		if conditional := splitConditionalTxs(localTxs, remoteTxs); len(conditional) > 0 {
+			tracing.Exec(ctx, "", "worker.ConditionalCommitTransactions", func(ctx context.Context, span trace.Span) {
+				err = w.commitConditionalTxs(env, conditional, interrupt, interruptCtx)
+			})
 
-		if err != nil {
-			return err
+			if err != nil {
+				return err
+			}
 		}
-
-		localEnvTCount = env.tcount
 	}
 
-	if len(remoteTxs) > 0 {
-		var txs *types.TransactionsByPriceAndNonce
+	tracing.Exec(ctx, "", "worker.FillTransactions", func(ctx context.Context, span trace.Span) {
+		err = w.algorithm.Fill(ctx, w, env, &pendingSet{locals: localTxs, remotes: remoteTxs}, interrupt, interruptCtx)
 
-		tracing.Exec(ctx, "", "worker.RemoteTransactionsByPriceAndNonce", func(ctx context.Context, span trace.Span) {
-			var baseFee *uint256.Int
-			if env.header.BaseFee != nil {
-				baseFee = cmath.FromBig(env.header.BaseFee)
-			}
+		tracing.SetAttributes(
+			span,
+			attribute.String("algorithm", w.algorithm.Name()),
+			attribute.Int("len of final txs", len(env.txs)),
+		)
+	})
 
-			txs = types.NewTransactionsByPriceAndNonce(env.signer, remoteTxs, baseFee)
+	return err
+}
 
-			tracing.SetAttributes(
-				span,
-				attribute.Int("len of tx remote Heads", txs.GetTxs()),
-			)
-		})
+// generateWork generates a sealing block based on the given parameters.
+func (w *worker) generateWork(ctx context.Context, params *generateParams) (*types.Block, *big.Int, error) {
+	// When building on behalf of a proposer with payouts enabled, keep the fees
+	// with the builder and pay the proposer through a final transaction.
+	var proposer *common.Address
 
-		tracing.Exec(ctx, "", "worker.RemoteCommitTransactions", func(ctx context.Context, span trace.Span) {
-			err = w.commitTransactions(env, txs, interrupt, interruptCtx)
-		})
+	if w.config.Payout.enabled() && !params.noTxs && params.coinbase != w.config.Payout.address() {
+		recipient, cpy := params.coinbase, *params
+		cpy.coinbase = w.config.Payout.address()
 
-		if err != nil {
-			return err
-		}
-
-		remoteEnvTCount = env.tcount
+		proposer, params = &recipient, &cpy
 	}
 
-	tracing.SetAttributes(
-		span,
-		attribute.Int("len of final local txs ", localEnvTCount),
-		attribute.Int("len of final remote txs", remoteEnvTCount),
-	)
-
-	return nil
-}
-
-// generateWork generates a sealing block based on the given parameters.
-func (w *worker) generateWork(ctx context.Context, params *generateParams) (*types.Block, *big.Int, error) {
 	work, err := w.prepareWork(params)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer work.discard()
 
+	var payment *proposerPayment
+
+	if proposer != nil {
+		if payment, err = w.reservePayment(work, *proposer); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// nolint : contextcheck
 	var interruptCtx = context.Background()
 
@@ -1697,12 +1635,30 @@ func (w *worker) generateWork(ctx context.Context, params *generateParams) (*typ
 		}
 	}
 
-	block, err := w.engine.FinalizeAndAssemble(ctx, w.chain, work.header, work.state, work.txs, work.unclelist(), work.receipts, params.withdrawals)
+	var paid *big.Int
+
+	if payment != nil {
+		// Rather than failing the block, keep the fees with the builder if the
+		// proposer can't be paid
+		if paid, err = w.commitPayment(ctx, work, payment); err != nil {
+			log.Warn("Failed to pay proposer, building block without payment", "number", work.header.Number, "proposer", payment.proposer, "err", err)
+
+			paid = new(big.Int)
+		}
+	}
+
+	w.setTxDependency(work)
+
+	block, err := w.engine.FinalizeAndAssemble(ctx, core.NewStateSyncCollector(w.chain), work.header, work.state, work.txs, work.unclelist(), work.receipts, params.withdrawals)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return block, totalFees(block, work.receipts), nil
+	if paid != nil {
+		return block, paid, nil
+	}
+
+	return block, totalFees(block, work.receipts, work.coinbase, work.balances), nil
 }
 
 // commitWork generates several new sealing tasks based on the parent block
@@ -1764,6 +1720,9 @@ func (w *worker) commitWork(ctx context.Context, interrupt *atomic.Int32, noempt
 	if !noempty && !w.noempty.Load() {
 		_ = w.commit(ctx, work.copy(), nil, false, start)
 	}
+	// Ask the external builders for a body while the local one is filled.
+	bidCh := w.requestBuilderBid(ctx, work)
+
 	// Fill pending transactions from the txpool into the block.
 	err = w.fillTransactions(ctx, interrupt, work, interruptCtx)
 
@@ -1795,6 +1754,13 @@ func (w *worker) commitWork(ctx context.Context, interrupt *atomic.Int32, noempt
 		work.discard()
 		return
 	}
+	// Replace the local body by the builder's one if it's more valuable.
+	if bidCh != nil {
+		if builderWork := w.applyBuilderBid(ctx, work, <-bidCh); builderWork != work {
+			work.discard()
+			work = builderWork
+		}
+	}
 	// Submit the generated block for consensus sealing.
 	_ = w.commit(ctx, work.copy(), w.fullTaskHook, true, start)
 
@@ -1843,8 +1809,11 @@ func (w *worker) commit(ctx context.Context, env *environment, interval func(),
 		// Create a local environment copy, avoid the data race with snapshot state.
 		// https://github.com/ethereum/go-ethereum/issues/24299
 		env := env.copy()
+		w.setTxDependency(env)
 		// Withdrawals are set to nil here, because this is only called in PoW.
-		block, err := w.engine.FinalizeAndAssemble(ctx, w.chain, env.header, env.state, env.txs, env.unclelist(), env.receipts, nil)
+		stateSyncs := core.NewStateSyncCollector(w.chain)
+
+		block, err := w.engine.FinalizeAndAssemble(ctx, stateSyncs, env.header, env.state, env.txs, env.unclelist(), env.receipts, nil)
 
 		tracing.SetAttributes(
 			span,
@@ -1859,19 +1828,27 @@ func (w *worker) commit(ctx context.Context, env *environment, interval func(),
 			return err
 		}
 
+		env.stateSyncs = stateSyncs.GetStateSync()
+
 		// If we're post merge, just ignore
 		if !w.isTTDReached(block.Header()) {
+			profit := types.NewBlockProfit(w.chainConfig, block.Header(), env.coinbase, block.Transactions(), env.receipts, env.balances, env.stateSyncs)
+
 			select {
-			case w.taskCh <- &task{ctx: ctx, receipts: env.receipts, state: env.state, block: block, createdAt: time.Now()}:
+			case w.taskCh <- &task{ctx: ctx, receipts: env.receipts, state: env.state, block: block, profit: profit, stateSyncs: env.stateSyncs, createdAt: time.Now()}:
 				w.unconfirmed.Shift(block.NumberU64() - 1)
 
-				fees := totalFees(block, env.receipts)
+				fees := profit.Value()
 				feesInEther := new(big.Float).Quo(new(big.Float).SetInt(fees), big.NewFloat(params.Ether))
 				log.Info("Commit new sealing work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
 					"uncles", len(env.uncles), "txs", env.tcount,
 					"gas", block.GasUsed(), "fees", feesInEther,
 					"elapsed", common.PrettyDuration(time.Since(start)))
 
+				if update {
+					w.announceWork(block, fees, time.Since(start))
+				}
+
 			case <-w.exitCh:
 				log.Info("Worker has exited")
 			}
@@ -1885,23 +1862,40 @@ func (w *worker) commit(ctx context.Context, env *environment, interval func(),
 	return nil
 }
 
+// announceWork streams the given full sealing work to the payload update
+// subscribers if it improves on the work already announced for its parent.
+func (w *worker) announceWork(block *types.Block, fees *big.Int, elapsed time.Duration) {
+	if best := w.bestWork; best != nil && best.ParentHash == block.ParentHash() && fees.Cmp(best.Fees) <= 0 {
+		return
+	}
+
+	w.bestWork = newPayloadUpdate(engine.PayloadID{}, block, fees, elapsed)
+	w.payloadFeed.Send(w.bestWork)
+}
+
 // getSealingBlock generates the sealing block based on the given parameters.
 // The generation result will be passed back via the given channel no matter
 // the generation itself succeeds or not.
 func (w *worker) getSealingBlock(parent common.Hash, timestamp uint64, coinbase common.Address, random common.Hash, withdrawals types.Withdrawals, noTxs bool) (*types.Block, *big.Int, error) {
+	return w.requestWork(&generateParams{
+		timestamp:   timestamp,
+		forceTime:   true,
+		parentHash:  parent,
+		coinbase:    coinbase,
+		random:      random,
+		withdrawals: withdrawals,
+		noUncle:     true,
+		noTxs:       noTxs,
+	})
+}
+
+// requestWork hands the given parameters to the main loop and waits for the
+// generated block.
+func (w *worker) requestWork(params *generateParams) (*types.Block, *big.Int, error) {
 	ctx := tracing.WithTracer(context.Background(), otel.GetTracerProvider().Tracer("getSealingBlock"))
 
 	req := &getWorkReq{
-		params: &generateParams{
-			timestamp:   timestamp,
-			forceTime:   true,
-			parentHash:  parent,
-			coinbase:    coinbase,
-			random:      random,
-			withdrawals: withdrawals,
-			noUncle:     true,
-			noTxs:       noTxs,
-		},
+		params: params,
 		result: make(chan *newPayloadResult, 1),
 		ctx:    ctx,
 	}
@@ -1945,16 +1939,11 @@ func (w *worker) postSideBlock(event core.ChainSideEvent) {
 	}
 }
 
-// totalFees computes total consumed miner fees in Wei. Block transactions and receipts have to have the same order.
-func totalFees(block *types.Block, receipts []*types.Receipt) *big.Int {
-	feesWei := new(big.Int)
-
-	for i, tx := range block.Transactions() {
-		minerFee, _ := tx.EffectiveGasTip(block.BaseFee())
-		feesWei.Add(feesWei, new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), minerFee))
-	}
-
-	return feesWei
+// totalFees computes the value of the block to the coinbase in Wei: the tips
+// and the transfers to the coinbase. Block transactions, receipts and coinbase
+// balances have to have the same order.
+func totalFees(block *types.Block, receipts []*types.Receipt, coinbase common.Address, balances []*big.Int) *big.Int {
+	return types.NewBlockProfit(nil, block.Header(), coinbase, block.Transactions(), receipts, balances, nil).Value()
 }
 
 // signalToErr converts the interruption signal to a concrete error type for return.
@@ -17,8 +17,12 @@
 package miner
 
 import (
+	"context"
+	"flag"
 	"math/big"
+	"math/rand"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -35,8 +39,10 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool/recorder"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
@@ -1010,3 +1016,231 @@ func BenchmarkBorMiningBlockSTMMetadata(b *testing.B) {
 		}
 	}
 }
+
+var updateMempools = flag.Bool("update-mempools", false, "regenerate the mempool recordings of the block building benchmarks")
+
+var (
+	// benchCoinbase is the coinbase of the blocks built out of the benchmark
+	// mempools, which some of their transactions transfer to.
+	benchCoinbase = common.HexToAddress("0xc014ba5e")
+
+	// benchRecipient receives the transfers of the benchmark mempools.
+	benchRecipient = common.HexToAddress("0xdecaf")
+)
+
+// benchMempools are the mempools the block building algorithms are compared
+// on, recorded in testdata/mempools in the format of the mempool recorder.
+// They hold more transactions than fit in a block.
+var benchMempools = []struct {
+	name   string
+	payers int // Percentage of senders transferring to the coinbase instead of tipping
+}{
+	{"tips", 0},
+	{"payments", 20},
+}
+
+// newBenchMempool returns the transactions of senders sending txs transactions
+// each, with random tips. Some of the senders transfer to the coinbase instead
+// of tipping. The mempool only depends on the parameters, but the signatures.
+func newBenchMempool(tb testing.TB, cfg *params.ChainConfig, senders, txs, payers int) types.Transactions {
+	tb.Helper()
+
+	var (
+		rng     = rand.New(rand.NewSource(int64(senders*txs + payers)))
+		signer  = types.LatestSigner(cfg)
+		mempool = make(types.Transactions, 0, senders*txs)
+	)
+
+	for i := 0; i < senders; i++ {
+		key, err := crypto.ToECDSA(crypto.Keccak256(big.NewInt(int64(i + 1)).Bytes()))
+		if err != nil {
+			tb.Fatalf("failed to derive sender key: %v", err)
+		}
+
+		payer := rng.Intn(100) < payers
+
+		for nonce := 0; nonce < txs; nonce++ {
+			var (
+				tip   = big.NewInt(rng.Int63n(100*params.GWei) + 1)
+				to    = benchRecipient
+				value = big.NewInt(1)
+			)
+
+			if payer {
+				to, value = benchCoinbase, new(big.Int).Mul(tip, big.NewInt(int64(2*params.TxGas)))
+				tip = big.NewInt(1)
+			}
+
+			mempool = append(mempool, types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   cfg.ChainID,
+				Nonce:     uint64(nonce),
+				GasTipCap: tip,
+				GasFeeCap: new(big.Int).Add(tip, big.NewInt(100*params.InitialBaseFee)),
+				Gas:       params.TxGas,
+				To:        &to,
+				Value:     value,
+			}))
+		}
+	}
+
+	return mempool
+}
+
+// benchMempoolPath returns the path of the recording of a benchmark mempool.
+func benchMempoolPath(name string) string {
+	return filepath.Join("testdata", "mempools", name+".rec")
+}
+
+// loadBenchMempool returns the transactions pending for block 1 in the
+// recording of a benchmark mempool.
+func loadBenchMempool(tb testing.TB, name string) types.Transactions {
+	tb.Helper()
+
+	txs, err := recorder.Mempool(benchMempoolPath(name), 1)
+	if err != nil {
+		tb.Fatalf("failed to load %s mempool: %v", name, err)
+	}
+
+	return txs
+}
+
+// recordingFeeds stand in for the pool and chain a mempool recorder follows.
+type recordingFeeds struct {
+	txs, drops, heads event.Feed
+}
+
+func (f *recordingFeeds) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return f.txs.Subscribe(ch)
+}
+
+func (f *recordingFeeds) SubscribeDropTxsEvent(ch chan<- core.DropTxsEvent) event.Subscription {
+	return f.drops.Subscribe(ch)
+}
+
+func (f *recordingFeeds) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return f.heads.Subscribe(ch)
+}
+
+// TestBenchMempools checks that the recorded benchmark mempools hold the
+// transactions generated by newBenchMempool, and records them again with
+// -update-mempools.
+func TestBenchMempools(t *testing.T) {
+	t.Parallel()
+
+	cfg := params.BorUnittestChainConfig
+
+	for _, mempool := range benchMempools {
+		// 30M gas blocks fit 1428 transfers, the mempools hold 1920
+		txs := newBenchMempool(t, cfg, 80, 24, mempool.payers)
+
+		if *updateMempools {
+			var (
+				feeds = new(recordingFeeds)
+				path  = benchMempoolPath(mempool.name)
+			)
+
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				t.Fatalf("failed to remove %s mempool: %v", mempool.name, err)
+			}
+
+			r := recorder.New(path, feeds, feeds)
+			if err := r.Start(); err != nil {
+				t.Fatalf("failed to record %s mempool: %v", mempool.name, err)
+			}
+
+			feeds.txs.Send(core.NewTxsEvent{Txs: txs})
+			feeds.heads.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(&types.Header{Number: common.Big1})})
+
+			if err := r.Stop(); err != nil {
+				t.Fatalf("failed to record %s mempool: %v", mempool.name, err)
+			}
+		}
+
+		recorded := loadBenchMempool(t, mempool.name)
+		if len(recorded) != len(txs) {
+			t.Fatalf("%s: transaction count mismatch: have %d, want %d", mempool.name, len(recorded), len(txs))
+		}
+
+		// Signatures aren't deterministic, compare the rest of the transactions
+		signer := types.LatestSigner(cfg)
+
+		for i, tx := range recorded {
+			have, err := types.Sender(signer, tx)
+			if err != nil {
+				t.Fatalf("%s: failed to recover sender of transaction %d: %v", mempool.name, i, err)
+			}
+
+			want, _ := types.Sender(signer, txs[i])
+
+			if have != want || tx.Nonce() != txs[i].Nonce() || tx.GasTipCap().Cmp(txs[i].GasTipCap()) != 0 ||
+				*tx.To() != *txs[i].To() || tx.Value().Cmp(txs[i].Value()) != 0 {
+				t.Fatalf("%s: transaction %d mismatch: have %d from %s, want %d from %s", mempool.name, i, tx.Nonce(), have, txs[i].Nonce(), want)
+			}
+		}
+	}
+}
+
+// BenchmarkBlockBuildingAlgorithms compares the blocks built by each algorithm
+// out of the recorded benchmark mempools. The value of the blocks is reported
+// in gwei paid to the coinbase, along with their transaction count.
+func BenchmarkBlockBuildingAlgorithms(b *testing.B) {
+	for _, mempool := range benchMempools {
+		w, _ := newBuilderTestWorker(b)
+
+		env, err := w.prepareWork(&generateParams{timestamp: uint64(time.Now().Unix()), coinbase: benchCoinbase})
+		if err != nil {
+			b.Fatalf("failed to prepare work: %v", err)
+		}
+
+		txs := loadBenchMempool(b, mempool.name)
+
+		for _, tx := range txs {
+			from, err := types.Sender(env.signer, tx)
+			if err != nil {
+				b.Fatalf("failed to recover sender: %v", err)
+			}
+
+			if env.state.GetBalance(from).Sign() == 0 {
+				env.state.AddBalance(from, big.NewInt(params.Ether))
+			}
+		}
+
+		var (
+			pending = &pendingSet{remotes: replayPending(env, txs)}
+			balance = env.state.GetBalance(env.coinbase)
+		)
+
+		for _, name := range []string{AlgorithmGreedyTip, AlgorithmGreedyProfit, AlgorithmBundleAware, AlgorithmMulti} {
+			algorithm, err := NewBlockBuildingAlgorithm(name)
+			if err != nil {
+				b.Fatalf("failed to create %s algorithm: %v", name, err)
+			}
+
+			b.Run(mempool.name+"/"+name, func(b *testing.B) {
+				var (
+					value = new(big.Int)
+					count int
+				)
+
+				for i := 0; i < b.N; i++ {
+					algoEnv := env.copy()
+
+					if err := algorithm.Fill(context.Background(), w, algoEnv, pending.copy(), nil, context.Background()); err != nil {
+						b.Fatalf("failed to fill transactions: %v", err)
+					}
+
+					value.Sub(algoEnv.state.GetBalance(algoEnv.coinbase), balance)
+					count = len(algoEnv.txs)
+
+					algoEnv.discard()
+				}
+
+				gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(value), big.NewFloat(params.GWei)).Float64()
+				b.ReportMetric(gwei, "gwei/block")
+				b.ReportMetric(float64(count), "txs/block")
+			})
+		}
+
+		env.discard()
+	}
+}
@@ -492,6 +492,19 @@ func TestFetchStateSyncEvents(t *testing.T) {
 	// Validate the state sync transactions set by consensus
 	validateStateSyncEvents(t, eventRecords, chain.GetStateSync())
 
+	// Finalizing the block again outside of the chain, as relays and builders
+	// do, keeps its state sync transactions away from the chain
+	chain.SetStateSync(nil)
+
+	statedb, err := chain.State()
+	require.NoError(t, err)
+
+	collector := core.NewStateSyncCollector(chain)
+	_bor.Finalize(collector, types.CopyHeader(block.Header()), statedb, nil, nil, nil)
+
+	validateStateSyncEvents(t, eventRecords, collector.GetStateSync())
+	require.Empty(t, chain.GetStateSync(), "state sync events should stay out of the chain")
+
 	insertNewBlock(t, chain, block)
 }
 
@@ -0,0 +1,414 @@
+// Package simulator runs networks of in-process bor validators on a simulated
+// clock, for consensus and block production scenario tests.
+//
+// Every validator is a full eth.Ethereum node running bor consensus against a
+// shared simulated Heimdall. The simulator stands for the p2p network instead of
+// the node's own networking: it seals the blocks of the validators at their
+// slots, including the backup proposer wiggle, and propagates them to the other
+// nodes with a configurable latency. Faults are injected by taking nodes
+// offline, partitioning the network and delaying the blocks of a node.
+package simulator
+
+import (
+	"container/heap"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/simulated"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/cli/server/chains"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Defaults of the config.
+const (
+	DefaultPeriod            = 2
+	DefaultLatency           = 200 * time.Millisecond
+	DefaultMilestoneInterval = 12 * time.Second
+)
+
+// Config is the configuration of a simulated network.
+type Config struct {
+	Validators int           // Number of validators, each running a node
+	Period     uint64        // Block period in seconds, also the producer delay and backup multiplier
+	Latency    time.Duration // Propagation delay of the blocks between the nodes
+
+	// Milestones are fetched by the nodes every MilestoneInterval, and are
+	// made of the chain of the HeimdallNode.
+	MilestoneInterval time.Duration
+	MilestoneLength   uint64
+	Confirmations     uint64
+	HeimdallNode      int
+}
+
+// Network is a simulated network of bor validators. It isn't safe for
+// concurrent use.
+type Network struct {
+	config   Config
+	nodes    []*Node
+	heimdall *simulated.Heimdall
+
+	start time.Time
+	now   time.Time
+	mu    sync.RWMutex // Guards now, read by the consensus engines concurrently
+
+	events eventQueue
+	seq    uint64
+
+	partition map[int]int // Partition of each node, nil if not partitioned
+}
+
+// New creates a network of validators, with the first one being the only
+// validator of the first span, hardcoded in the genesis contracts, and all
+// of them the validators of the following spans. The clock starts at the
+// genesis time.
+func New(config Config) (*Network, error) {
+	if config.Validators < 1 {
+		return nil, fmt.Errorf("invalid validator count %d", config.Validators)
+	}
+
+	if config.Period == 0 {
+		config.Period = DefaultPeriod
+	}
+
+	if config.Latency == 0 {
+		config.Latency = DefaultLatency
+	}
+
+	if config.MilestoneInterval == 0 {
+		config.MilestoneInterval = DefaultMilestoneInterval
+	}
+
+	if config.Confirmations == 0 {
+		config.Confirmations = simulated.DefaultConfirmations
+	}
+
+	keys := make([]*ecdsa.PrivateKey, config.Validators)
+	validators := make([]*valset.Validator, config.Validators)
+
+	for i := range keys {
+		// Deterministic keys keep the proposer order of the scenarios stable
+		keys[i], _ = crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("validator-%d", i))))
+		validators[i] = &valset.Validator{ID: uint64(i + 1), Address: crypto.PubkeyToAddress(keys[i].PublicKey), VotingPower: 10}
+	}
+
+	chain := chains.GetDeveloperBorChain(config.Period, 30_000_000, validators[0].Address)
+
+	n := &Network{
+		config: config,
+		heimdall: simulated.New(simulated.Config{
+			ChainID:         chain.Genesis.Config.ChainID.String(),
+			Validators:      validators,
+			FirstSpanEnd:    chains.DeveloperFirstSpanEnd,
+			MilestoneLength: config.MilestoneLength,
+			Confirmations:   config.Confirmations,
+		}),
+		start: time.Unix(int64(chain.Genesis.Timestamp), 0),
+	}
+	n.now = n.start
+
+	for i, key := range keys {
+		node, err := newNode(n, i, key, chain.Genesis)
+		if err != nil {
+			n.Close()
+			return nil, err
+		}
+
+		n.nodes = append(n.nodes, node)
+	}
+
+	if config.HeimdallNode < 0 || config.HeimdallNode >= len(n.nodes) {
+		n.Close()
+		return nil, fmt.Errorf("invalid heimdall node %d", config.HeimdallNode)
+	}
+
+	n.heimdall.SetChain(n.nodes[config.HeimdallNode].backend.APIBackend)
+	n.schedule(&event{at: n.now.Add(config.MilestoneInterval), milestone: true})
+
+	return n, nil
+}
+
+// Close stops all the nodes.
+func (n *Network) Close() {
+	for _, node := range n.nodes {
+		node.close()
+	}
+}
+
+// Nodes returns the nodes of the network, in validator order.
+func (n *Network) Nodes() []*Node {
+	return n.nodes
+}
+
+// Node returns the i-th node of the network.
+func (n *Network) Node(i int) *Node {
+	return n.nodes[i]
+}
+
+// Heimdall returns the simulated Heimdall shared by the nodes.
+func (n *Network) Heimdall() *simulated.Heimdall {
+	return n.heimdall
+}
+
+// Now returns the current time of the simulated clock.
+func (n *Network) Now() time.Time {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.now
+}
+
+// Elapsed returns the simulated time since the start of the network.
+func (n *Network) Elapsed() time.Duration {
+	return n.Now().Sub(n.start)
+}
+
+// Run advances the simulated clock by d, sealing and propagating the blocks
+// due in the meantime.
+func (n *Network) Run(d time.Duration) error {
+	return n.run(n.Now().Add(d), func() bool { return false })
+}
+
+// RunUntil advances the simulated clock until the heads of all the online
+// nodes reach number, or fails after timeout of simulated time.
+func (n *Network) RunUntil(number uint64, timeout time.Duration) error {
+	reached := func() bool {
+		for _, node := range n.nodes {
+			if node.online && node.Head().Number.Uint64() < number {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err := n.run(n.Now().Add(timeout), reached); err != nil {
+		return err
+	}
+
+	if !reached() {
+		return fmt.Errorf("block %d not reached after %v", number, timeout)
+	}
+
+	return nil
+}
+
+// SetOnline takes a node offline, where it neither seals nor receives blocks,
+// or back online, where it catches up with its peers.
+func (n *Network) SetOnline(i int, online bool) {
+	n.nodes[i].online = online
+
+	if online {
+		n.syncAll()
+	}
+}
+
+// Partition splits the network in groups of nodes, which only exchange blocks
+// within their group. The nodes in no group form a group of their own.
+func (n *Network) Partition(groups ...[]int) {
+	n.partition = make(map[int]int, len(n.nodes))
+
+	for i := range n.nodes {
+		n.partition[i] = len(groups)
+	}
+
+	for g, group := range groups {
+		for _, i := range group {
+			n.partition[i] = g
+		}
+	}
+}
+
+// Heal removes the partition, the nodes then catching up with each other.
+func (n *Network) Heal() {
+	n.partition = nil
+	n.syncAll()
+}
+
+// DelayBlocks delays the propagation of the blocks sealed by the i-th node by
+// d, on top of the latency.
+func (n *Network) DelayBlocks(i int, d time.Duration) {
+	n.nodes[i].delay = d
+}
+
+// Proposer returns the index of the in-turn proposer of the block following
+// the head of the i-th node, or -1 if it isn't one of the nodes.
+func (n *Network) Proposer(i int) (int, error) {
+	node := n.nodes[i]
+
+	slot, err := node.engine.GetSlot(context.Background(), node.chain(), node.Head())
+	if err != nil {
+		return -1, err
+	}
+
+	for j, other := range n.nodes {
+		if other.address == slot.Proposer {
+			return j, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// clock returns the current time of the simulated clock, for the consensus
+// engines.
+func (n *Network) clock() time.Time {
+	return n.Now()
+}
+
+// connected returns whether blocks propagate between the nodes.
+func (n *Network) connected(from *Node, to *Node) bool {
+	if !from.online || !to.online {
+		return false
+	}
+
+	return n.partition == nil || n.partition[from.index] == n.partition[to.index]
+}
+
+// run processes the events until the clock reaches end or done returns true.
+func (n *Network) run(end time.Time, done func() bool) error {
+	for !done() {
+		for _, node := range n.nodes {
+			if err := node.scheduleProposal(); err != nil {
+				return err
+			}
+		}
+
+		if n.events.Len() == 0 || n.events[0].at.After(end) {
+			break
+		}
+
+		ev := heap.Pop(&n.events).(*event)
+
+		n.mu.Lock()
+		n.now = ev.at
+		n.mu.Unlock()
+
+		switch {
+		case ev.milestone:
+			n.processMilestone()
+			n.schedule(&event{at: ev.at.Add(n.config.MilestoneInterval), milestone: true})
+
+		case ev.block != nil:
+			if n.connected(ev.from, ev.to) {
+				ev.to.importFrom(ev.from, ev.block)
+			}
+
+		default:
+			if err := ev.proposer.propose(ev); err != nil {
+				return err
+			}
+		}
+	}
+
+	if done() {
+		return nil
+	}
+
+	n.mu.Lock()
+	if n.now.Before(end) {
+		n.now = end
+	}
+	n.mu.Unlock()
+
+	return nil
+}
+
+// broadcast propagates a block sealed by a node to its peers.
+func (n *Network) broadcast(from *Node, block *types.Block) {
+	for _, to := range n.nodes {
+		if to != from && n.connected(from, to) {
+			n.schedule(&event{at: n.Now().Add(n.config.Latency + from.delay), from: from, to: to, block: block})
+		}
+	}
+}
+
+// syncAll propagates the heads of all the nodes to their peers, to have the
+// nodes catching up after faults.
+func (n *Network) syncAll() {
+	for _, from := range n.nodes {
+		for _, to := range n.nodes {
+			if to != from && n.connected(from, to) {
+				n.schedule(&event{at: n.Now().Add(n.config.Latency), from: from, to: to, block: from.backend.BlockChain().GetBlockByHash(from.Head().Hash())})
+			}
+		}
+	}
+}
+
+// processMilestone whitelists the latest milestone on the online nodes, as the
+// milestone whitelisting service does.
+func (n *Network) processMilestone() {
+	milestone, err := n.heimdall.FetchMilestone(context.Background())
+	if err != nil {
+		log.Debug("No milestone to whitelist", "err", err)
+		return
+	}
+
+	end, hash := milestone.EndBlock.Uint64(), milestone.Hash
+
+	for _, node := range n.nodes {
+		if !node.online {
+			continue
+		}
+
+		if header := node.backend.BlockChain().GetHeaderByNumber(end); header != nil && header.Hash() == hash {
+			node.backend.Downloader().ProcessMilestone(end, hash)
+		} else {
+			node.backend.Downloader().ProcessFutureMilestone(end, hash)
+		}
+	}
+}
+
+func (n *Network) schedule(ev *event) {
+	n.seq++
+	ev.seq = n.seq
+
+	heap.Push(&n.events, ev)
+}
+
+// event is a block sealed by a proposer, a block received by a node, or the
+// fetching of the latest milestone.
+type event struct {
+	at  time.Time
+	seq uint64 // Orders the events due at the same time
+
+	proposer *Node
+	parent   common.Hash
+
+	from  *Node
+	to    *Node
+	block *types.Block
+
+	milestone bool
+}
+
+// eventQueue is a priority queue of events by due time.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+
+	return q[i].at.Before(q[j].at)
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	ev := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return ev
+}
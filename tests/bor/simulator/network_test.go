@@ -0,0 +1,223 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestNetwork(t *testing.T, config Config) *Network {
+	t.Helper()
+
+	n, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create network: %v", err)
+	}
+
+	t.Cleanup(n.Close)
+
+	return n
+}
+
+// checkConverged checks that all the online nodes share the same head.
+func checkConverged(t *testing.T, n *Network) {
+	t.Helper()
+
+	head := n.Node(0).Head()
+
+	for i, node := range n.Nodes() {
+		if node.Online() && node.Head().Hash() != head.Hash() {
+			t.Errorf("validator %d head mismatch: have %d %x, want %d %x", i, node.Head().Number, node.Head().Hash(), head.Number, head.Hash())
+		}
+	}
+}
+
+// checkFinalized checks that all the nodes whitelisted a milestone at or after
+// number, which is part of their canonical chain.
+func checkFinalized(t *testing.T, n *Network, number uint64) {
+	t.Helper()
+
+	for i, node := range n.Nodes() {
+		ok, end, hash := node.Finalized()
+		if !ok || end < number {
+			t.Errorf("validator %d finalized mismatch: have %v %d, want at least %d", i, ok, end, number)
+			continue
+		}
+
+		if canonical := node.Backend().BlockChain().GetCanonicalHash(end); canonical != hash {
+			t.Errorf("validator %d finalized block %d not canonical: have %x, want %x", i, end, canonical, hash)
+		}
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, Config{Validators: 3})
+
+	if err := n.RunUntil(64, 10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	checkConverged(t, n)
+
+	// All the validators sealed their sprints in turn, after the first span
+	for i, node := range n.Nodes() {
+		if node.Sealed() == 0 {
+			t.Errorf("validator %d sealed no block", i)
+		}
+
+		if node.Reorgs() != 0 || node.Rejected() != 0 {
+			t.Errorf("validator %d reorged %d times and rejected %d times", i, node.Reorgs(), node.Rejected())
+		}
+	}
+
+	if outOfTurn, err := n.Node(1).OutOfTurn(); err != nil || outOfTurn != 0 {
+		t.Errorf("out-of-turn block count mismatch: have %d (err %v), want 0", outOfTurn, err)
+	}
+
+	// Each block takes a period, and the first block of the sprints the
+	// producer delay, both of 2 seconds
+	if have, want := n.Elapsed(), 64*DefaultPeriod*time.Second; have > want+DefaultLatency {
+		t.Errorf("elapsed time mismatch: have %v, want %v", have, want)
+	}
+
+	checkFinalized(t, n, 31)
+}
+
+func TestOfflineProposer(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, Config{Validators: 3})
+
+	if err := n.RunUntil(16, 10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Take the next proposer offline, the backup proposers take over
+	proposer, err := n.Proposer(0)
+	if err != nil || proposer < 0 {
+		t.Fatalf("failed to get the proposer: %d (err %v)", proposer, err)
+	}
+
+	n.SetOnline(proposer, false)
+
+	if err := n.RunUntil(64, 10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	online := (proposer + 1) % 3
+
+	outOfTurn, err := n.Node(online).OutOfTurn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if outOfTurn == 0 {
+		t.Errorf("no out-of-turn block with validator %d offline", proposer)
+	}
+
+	checkConverged(t, n)
+
+	// The proposer catches up once back online
+	n.SetOnline(proposer, true)
+
+	if err := n.Run(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	checkConverged(t, n)
+
+	for i, node := range n.Nodes() {
+		if node.MaxReorgDepth() > 1 {
+			t.Errorf("validator %d reorged %d blocks deep", i, node.MaxReorgDepth())
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, Config{Validators: 4, MilestoneLength: 8, Confirmations: 4})
+
+	if err := n.RunUntil(16, 10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Isolate a validator, which keeps sealing its own fork
+	n.Partition([]int{0, 1, 2}, []int{3})
+
+	if err := n.Run(2 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	fork := n.Node(3).Head()
+	if fork.Hash() == n.Node(0).Head().Hash() || fork.Number.Uint64() <= 16 {
+		t.Fatalf("isolated validator didn't fork: head %d", fork.Number)
+	}
+
+	// The fork is dropped once healed, up to the finalized milestones
+	n.Heal()
+
+	if err := n.Run(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	checkConverged(t, n)
+
+	if have := n.Node(3).MaxReorgDepth(); have == 0 {
+		t.Errorf("isolated validator didn't reorg")
+	}
+
+	for i := 0; i < 3; i++ {
+		if have := n.Node(i).MaxReorgDepth(); have != 0 {
+			t.Errorf("validator %d reorged %d blocks deep", i, have)
+		}
+	}
+
+	checkFinalized(t, n, fork.Number.Uint64())
+}
+
+func TestDelayedBlocks(t *testing.T) {
+	t.Parallel()
+
+	n := newTestNetwork(t, Config{Validators: 3})
+
+	if err := n.RunUntil(16, 10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocks arriving after the slot of the backup proposer are competing with
+	// its own, and replace them as their difficulty is higher
+	proposer, err := n.Proposer(0)
+	if err != nil || proposer < 0 {
+		t.Fatalf("failed to get the proposer: %d (err %v)", proposer, err)
+	}
+
+	n.DelayBlocks(proposer, 3*DefaultPeriod*time.Second)
+
+	if err := n.Run(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	n.DelayBlocks(proposer, 0)
+
+	if err := n.Run(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	checkConverged(t, n)
+
+	var reorgs int
+
+	for i, node := range n.Nodes() {
+		reorgs += node.Reorgs()
+
+		if depth := node.MaxReorgDepth(); depth > 4 {
+			t.Errorf("validator %d reorged %d blocks deep", i, depth)
+		}
+	}
+
+	if reorgs == 0 {
+		t.Errorf("no reorg with the blocks of validator %d delayed", proposer)
+	}
+}
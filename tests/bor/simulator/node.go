@@ -0,0 +1,437 @@
+package simulator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/simulated"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// sealTimeout is how long to wait for the engine to seal a block, which is
+// immediate as the blocks are sealed at their slot.
+const sealTimeout = 10 * time.Second
+
+// errSealTimeout is returned when the engine doesn't seal a block due.
+var errSealTimeout = errors.New("sealing timed out")
+
+// Node is a validator of a simulated network.
+type Node struct {
+	network *Network
+	index   int
+	key     *ecdsa.PrivateKey
+	address common.Address
+
+	datadir string
+	stack   *node.Node
+	backend *eth.Ethereum
+	engine  *bor.Bor
+
+	online bool
+	delay  time.Duration // Propagation delay of the sealed blocks on top of the latency
+
+	proposal  common.Hash // Parent of the last proposal scheduled
+	scheduled bool
+
+	sealed   int
+	reorgs   int
+	maxReorg uint64
+	rejected int
+}
+
+// newNode creates a validator node sealing with key. The node isn't started,
+// as the network stands for its networking.
+func newNode(network *Network, index int, key *ecdsa.PrivateKey, genesis *core.Genesis) (*Node, error) {
+	datadir, err := os.MkdirTemp("", "bor-simulator-")
+	if err != nil {
+		return nil, err
+	}
+
+	stack, err := node.New(&node.Config{
+		Name:    fmt.Sprintf("validator-%d", index),
+		Version: params.Version,
+		DataDir: datadir,
+		P2P: p2p.Config{
+			NoDiscovery: true,
+			MaxPeers:    0,
+		},
+		UseLightweightKDF: true,
+	})
+	if err != nil {
+		os.RemoveAll(datadir)
+		return nil, err
+	}
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend, err := eth.New(stack, &ethconfig.Config{
+		Genesis:         genesis,
+		NetworkId:       genesis.Config.ChainID.Uint64(),
+		SyncMode:        downloader.FullSync,
+		NoPruning:       true,
+		DatabaseCache:   16,
+		DatabaseHandles: 64,
+		TxPool:          txpool.DefaultConfig,
+		GPO:             ethconfig.Defaults.GPO,
+		Miner: miner.Config{
+			Etherbase: address,
+			GasCeil:   genesis.GasLimit,
+			GasPrice:  big.NewInt(1),
+			Recommit:  time.Second,
+		},
+		// Replaced by the Heimdall shared by the network below
+		DevHeimdall: &simulated.Config{},
+	})
+	if err != nil {
+		stack.Close()
+		os.RemoveAll(datadir)
+
+		return nil, err
+	}
+
+	engine, ok := backend.Engine().(*bor.Bor)
+	if !ok {
+		stack.Close()
+		os.RemoveAll(datadir)
+
+		return nil, errors.New("not bor consensus")
+	}
+
+	engine.SetHeimdallClient(network.heimdall)
+	engine.SetClock(network.clock)
+	engine.Authorize(address, func(_ accounts.Account, _ string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	})
+
+	return &Node{
+		network: network,
+		index:   index,
+		key:     key,
+		address: address,
+		datadir: datadir,
+		stack:   stack,
+		backend: backend,
+		engine:  engine,
+		online:  true,
+	}, nil
+}
+
+// Address returns the address of the validator.
+func (n *Node) Address() common.Address {
+	return n.address
+}
+
+// Key returns the signing key of the validator.
+func (n *Node) Key() *ecdsa.PrivateKey {
+	return n.key
+}
+
+// Backend returns the eth backend of the node.
+func (n *Node) Backend() *eth.Ethereum {
+	return n.backend
+}
+
+// Online returns whether the node is online.
+func (n *Node) Online() bool {
+	return n.online
+}
+
+// Head returns the header of the head block of the node.
+func (n *Node) Head() *types.Header {
+	return n.chain().CurrentBlock()
+}
+
+// Sealed returns the number of blocks sealed by the node.
+func (n *Node) Sealed() int {
+	return n.sealed
+}
+
+// Reorgs returns the number of reorgs of the node.
+func (n *Node) Reorgs() int {
+	return n.reorgs
+}
+
+// MaxReorgDepth returns the number of blocks dropped by the deepest reorg of
+// the node.
+func (n *Node) MaxReorgDepth() uint64 {
+	return n.maxReorg
+}
+
+// Rejected returns the number of times the node rejected blocks it received.
+func (n *Node) Rejected() int {
+	return n.rejected
+}
+
+// Finalized returns the end block of the milestone whitelisted by the node.
+func (n *Node) Finalized() (bool, uint64, common.Hash) {
+	return n.backend.Downloader().GetWhitelistedMilestone()
+}
+
+// OutOfTurn returns the number of blocks of the canonical chain of the node
+// sealed by another validator than the in-turn proposer.
+func (n *Node) OutOfTurn() (int, error) {
+	var (
+		chain = n.chain()
+		count int
+	)
+
+	for number := uint64(1); number <= n.Head().Number.Uint64(); number++ {
+		header := chain.GetHeaderByNumber(number)
+		parent := chain.GetHeader(header.ParentHash, number-1)
+
+		slot, err := n.engine.GetSlot(context.Background(), chain, parent)
+		if err != nil {
+			return 0, err
+		}
+
+		author, err := n.engine.Author(header)
+		if err != nil {
+			return 0, err
+		}
+
+		if author != slot.Proposer {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// SendTransaction adds a transaction to the pool of the node, and to the
+// pools of the nodes connected to it.
+func (n *Node) SendTransaction(tx *types.Transaction) error {
+	if err := n.backend.TxPool().AddLocal(tx); err != nil {
+		return err
+	}
+
+	for _, peer := range n.network.nodes {
+		if peer != n && n.network.connected(n, peer) {
+			peer.backend.TxPool().AddRemotesSync([]*types.Transaction{tx})
+		}
+	}
+
+	return nil
+}
+
+func (n *Node) chain() *core.BlockChain {
+	return n.backend.BlockChain()
+}
+
+// scheduleProposal schedules sealing a block on top of the head of the node at
+// its slot, if the node is a validator and the head changed.
+func (n *Node) scheduleProposal() error {
+	head := n.Head()
+
+	if !n.online || (n.scheduled && n.proposal == head.Hash()) {
+		return nil
+	}
+
+	n.proposal, n.scheduled = head.Hash(), true
+
+	slot, err := n.engine.GetSlot(context.Background(), n.chain(), head)
+	if err != nil {
+		return fmt.Errorf("validator %d: %w", n.index, err)
+	}
+
+	if slot.Succession < 0 {
+		return nil
+	}
+
+	// The slot is the one the engine prepares the header for
+	at := time.Unix(int64(head.Time+bor.CalcProducerDelay(slot.Number, slot.Succession, n.chain().Config().Bor)), 0)
+	if now := n.network.Now(); at.Before(now) {
+		at = now
+	}
+
+	n.network.schedule(&event{at: at, proposer: n, parent: head.Hash()})
+
+	return nil
+}
+
+// propose seals a block on top of the head of the node and broadcasts it,
+// unless the head changed since the proposal was scheduled.
+func (n *Node) propose(ev *event) error {
+	if !n.online || n.Head().Hash() != ev.parent {
+		return nil
+	}
+
+	block, err := n.seal(n.Head())
+	if err != nil {
+		return fmt.Errorf("validator %d: %w", n.index, err)
+	}
+
+	n.sealed++
+	n.insert([]*types.Block{block})
+	n.network.broadcast(n, block)
+
+	return nil
+}
+
+// seal builds and seals a block on top of parent, out of the pending
+// transactions of the node.
+func (n *Node) seal(parent *types.Header) (*types.Block, error) {
+	var (
+		chain  = n.chain()
+		config = chain.Config()
+	)
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   core.CalcGasLimit(parent.GasLimit, parent.GasLimit),
+		Time:       uint64(n.network.Now().Unix()),
+	}
+
+	if config.IsLondon(header.Number) {
+		header.BaseFee = misc.CalcBaseFeeUint(config, parent).ToBig()
+	}
+
+	if err := n.engine.Prepare(chain, header); err != nil {
+		return nil, err
+	}
+
+	statedb, err := chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, receipts := n.applyTransactions(header, statedb)
+
+	block, err := n.engine.FinalizeAndAssemble(context.Background(), chain, header, statedb, txs, nil, receipts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan *types.Block, 1)
+
+	if err := n.engine.Seal(context.Background(), chain, block, results, nil); err != nil {
+		return nil, err
+	}
+
+	select {
+	case block := <-results:
+		return block, nil
+	case <-time.After(sealTimeout):
+		return nil, errSealTimeout
+	}
+}
+
+// applyTransactions applies the pending transactions of the node to statedb,
+// by price and nonce.
+func (n *Node) applyTransactions(header *types.Header, statedb *state.StateDB) ([]*types.Transaction, []*types.Receipt) {
+	var (
+		chain    = n.chain()
+		signer   = types.MakeSigner(chain.Config(), header.Number)
+		gasPool  = new(core.GasPool).AddGas(header.GasLimit)
+		txs      []*types.Transaction
+		receipts []*types.Receipt
+	)
+
+	baseFee, _ := uint256.FromBig(header.BaseFee)
+	pending := types.NewTransactionsByPriceAndNonce(signer, n.backend.TxPool().Pending(context.Background(), true), baseFee)
+
+	for tx := pending.Peek(); tx != nil; tx = pending.Peek() {
+		statedb.SetTxContext(tx.Hash(), len(txs))
+
+		receipt, err := core.ApplyTransaction(chain.Config(), chain, &n.address, gasPool, statedb, header, tx, &header.GasUsed, *chain.GetVMConfig(), context.Background())
+		if err != nil {
+			log.Debug("Skipping transaction", "hash", tx.Hash(), "err", err)
+			pending.Pop()
+
+			continue
+		}
+
+		txs = append(txs, tx)
+		receipts = append(receipts, receipt)
+
+		pending.Shift()
+	}
+
+	return txs, receipts
+}
+
+// importFrom imports a block received from another node, along with the
+// ancestors missing locally.
+func (n *Node) importFrom(from *Node, block *types.Block) {
+	var blocks []*types.Block
+
+	for b := block; b != nil && !n.chain().HasBlock(b.Hash(), b.NumberU64()); b = from.chain().GetBlock(b.ParentHash(), b.NumberU64()-1) {
+		blocks = append(blocks, b)
+	}
+
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+
+	if len(blocks) > 0 {
+		n.insert(blocks)
+	}
+}
+
+// insert inserts blocks in the chain of the node, accounting for the reorgs.
+func (n *Node) insert(blocks []*types.Block) {
+	old := n.Head()
+
+	if _, err := n.chain().InsertChain(blocks); err != nil {
+		log.Debug("Rejected blocks", "validator", n.index, "number", blocks[0].NumberU64(), "count", len(blocks), "err", err)
+		n.rejected++
+	}
+
+	if depth := n.reorgDepth(old); depth > 0 {
+		n.reorgs++
+
+		if depth > n.maxReorg {
+			n.maxReorg = depth
+		}
+	}
+}
+
+// reorgDepth returns the number of blocks of the chain ending at old dropped
+// from the canonical chain.
+func (n *Node) reorgDepth(old *types.Header) uint64 {
+	chain := n.chain()
+
+	header := old
+	for header != nil && chain.GetCanonicalHash(header.Number.Uint64()) != header.Hash() {
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+
+	if header == nil {
+		return old.Number.Uint64()
+	}
+
+	return old.Number.Uint64() - header.Number.Uint64()
+}
+
+func (n *Node) close() {
+	n.backend.Miner().Close()
+	n.backend.TxPool().Stop()
+	n.backend.BlockChain().Stop()
+	n.engine.Close()
+	n.stack.Close()
+
+	os.RemoveAll(n.datadir)
+}